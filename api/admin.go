@@ -0,0 +1,374 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+	"weatherapi.app/service"
+)
+
+const (
+	adminAPIKeyHeader    = "X-Admin-API-Key"
+	defaultAdminPageSize = 50
+	maxAdminPageSize     = 200
+
+	// exportBatchSize is how many subscriptions each export query fetches;
+	// batches are written out as they arrive so memory stays bounded
+	exportBatchSize = 500
+)
+
+// SubscriptionLister retrieves a page of subscriptions with a total count;
+// used by the admin listing endpoint
+type SubscriptionLister interface {
+	List(offset, limit int, confirmedOnly bool) ([]models.Subscription, int64, error)
+}
+
+// TokenCleaner removes expired tokens; used by the admin cleanup endpoint
+type TokenCleaner interface {
+	DeleteExpiredTokens() (int64, error)
+}
+
+// FailedEmailLister retrieves a page of dead-lettered emails with a total
+// count; used by the admin failed-emails endpoint
+type FailedEmailLister interface {
+	List(offset, limit int) ([]models.FailedEmail, int64, error)
+}
+
+// EmailPreviewer renders an email template with sample data; used by the
+// admin email-preview endpoint
+type EmailPreviewer interface {
+	RenderEmailPreview(emailType, city, frequency string) (string, error)
+}
+
+// CacheInvalidator removes the cached weather entry for a city; discovered
+// on the provider manager via type assertion so mocks without it keep working
+type CacheInvalidator interface {
+	InvalidateCity(ctx context.Context, city string) error
+}
+
+// SubscriptionExporter streams confirmed subscriptions in batches for the
+// CSV export endpoint; discovered on the subscription lister via type
+// assertion
+type SubscriptionExporter interface {
+	StreamConfirmed(batchSize int, fn func([]models.Subscription) error) error
+}
+
+// SubscriptionStatsReader reports aggregate subscription counts; discovered
+// on the subscription lister via type assertion
+type SubscriptionStatsReader interface {
+	CountByFrequency() (map[string]int64, error)
+	CountConfirmed() (int64, error)
+}
+
+// LastRunReporter reports the most recent bulk send run; discovered on the
+// subscription service via type assertion
+type LastRunReporter interface {
+	LastWeatherRun() (service.WeatherRunStats, bool)
+}
+
+// WeatherUpdateDryRunner runs the update pipeline without sending emails;
+// discovered on the subscription service via type assertion
+type WeatherUpdateDryRunner interface {
+	DryRunWeatherUpdate(frequency string) (*service.WeatherUpdateResult, error)
+}
+
+// adminAuth rejects requests without the configured admin API key; servers
+// with no key configured keep the admin endpoints locked
+func (s *Server) adminAuth(c *gin.Context) {
+	key := s.config.Server.AdminAPIKey
+	provided := c.GetHeader(adminAPIKeyHeader)
+
+	if key == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "invalid admin API key"})
+		return
+	}
+
+	c.Next()
+}
+
+// listSubscriptions returns a page of subscriptions for operators, optionally
+// filtered to confirmed ones
+func (s *Server) listSubscriptions(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		s.handleError(c, weathererr.NewValidationError("page must be a positive number"))
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultAdminPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxAdminPageSize {
+		s.handleError(c, weathererr.NewValidationError("pageSize must be between 1 and 200"))
+		return
+	}
+
+	confirmedOnly := c.Query("confirmed") == "true"
+
+	subscriptions, total, err := s.subscriptionLister.List((page-1)*pageSize, pageSize, confirmedOnly)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"subscriptions": subscriptions,
+		"total":         total,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+// listFailedEmails returns a page of dead-lettered emails, most recent
+// first, so operators can inspect terminal send failures
+func (s *Server) listFailedEmails(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		s.handleError(c, weathererr.NewValidationError("page must be a positive number"))
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultAdminPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxAdminPageSize {
+		s.handleError(c, weathererr.NewValidationError("pageSize must be between 1 and 200"))
+		return
+	}
+
+	failedEmails, total, err := s.failedEmailLister.List((page-1)*pageSize, pageSize)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"failed_emails": failedEmails,
+		"total":         total,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+// invalidateWeatherCache drops the cached weather entry for a city so the
+// next request refetches from the providers, e.g. after a provider returned
+// bad data
+func (s *Server) invalidateWeatherCache(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		s.handleError(c, weathererr.NewValidationError("city parameter is required"))
+		return
+	}
+
+	invalidator, ok := s.providerManager.(CacheInvalidator)
+	if !ok {
+		s.handleError(c, weathererr.NewNotFoundError("weather caching is not enabled"))
+		return
+	}
+
+	if err := invalidator.InvalidateCity(c.Request.Context(), city); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("Weather cache entry invalidated", "city", city)
+	c.JSON(http.StatusOK, gin.H{"invalidated": city})
+}
+
+// exportSubscriptions streams every confirmed subscription as CSV so
+// operators can pull the list into a spreadsheet. Batches are written as the
+// repository delivers them, so large datasets never sit in memory at once
+func (s *Server) exportSubscriptions(c *gin.Context) {
+	exporter, ok := s.subscriptionLister.(SubscriptionExporter)
+	if !ok {
+		s.handleError(c, weathererr.NewNotFoundError("subscription export is not available"))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition",
+		fmt.Sprintf("attachment; filename=\"subscriptions-%s.csv\"", time.Now().Format("2006-01-02")))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write([]string{"email", "city", "frequency", "confirmed", "created_at"}); err != nil {
+		requestLogger(c).Error("Subscription export failed writing header", "error", err)
+		return
+	}
+
+	err := exporter.StreamConfirmed(exportBatchSize, func(batch []models.Subscription) error {
+		for _, subscription := range batch {
+			record := []string{
+				subscription.Email,
+				subscription.City,
+				subscription.Frequency,
+				strconv.FormatBool(subscription.Confirmed),
+				subscription.CreatedAt.Format(time.RFC3339),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		// The status line is already on the wire; all we can do is cut the
+		// response short and log why
+		requestLogger(c).Error("Subscription export failed mid-stream", "error", err)
+		return
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		requestLogger(c).Error("Subscription export failed flushing output", "error", err)
+	}
+}
+
+// adminStats gives operators a quick overview: subscription counts in total,
+// by frequency and by confirmation state, plus the latest send-run outcome
+// when one has completed
+func (s *Server) adminStats(c *gin.Context) {
+	reader, ok := s.subscriptionLister.(SubscriptionStatsReader)
+	if !ok {
+		s.handleError(c, weathererr.NewNotFoundError("subscription statistics are not available"))
+		return
+	}
+
+	byFrequency, err := reader.CountByFrequency()
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	confirmed, err := reader.CountConfirmed()
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	var total int64
+	for _, count := range byFrequency {
+		total += count
+	}
+
+	response := gin.H{
+		"subscriptions": gin.H{
+			"total":        total,
+			"confirmed":    confirmed,
+			"by_frequency": byFrequency,
+		},
+	}
+
+	if reporter, ok := s.subscriptionService.(LastRunReporter); ok {
+		if run, available := reporter.LastWeatherRun(); available {
+			response["last_run"] = run
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// triggerWeatherUpdate runs a bulk weather update for one frequency on
+// demand. With dry_run=true the full selection and rendering pipeline runs —
+// including the real weather fetch — but nothing is sent, so operators can
+// verify targeting before enabling a new frequency
+func (s *Server) triggerWeatherUpdate(c *gin.Context) {
+	frequency := c.Query("frequency")
+	if frequency == "" {
+		s.handleError(c, weathererr.NewValidationError("frequency parameter is required"))
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	var result *service.WeatherUpdateResult
+	var err error
+	if dryRun {
+		runner, ok := s.subscriptionService.(WeatherUpdateDryRunner)
+		if !ok {
+			s.handleError(c, weathererr.NewNotFoundError("dry-run is not available"))
+			return
+		}
+		result, err = runner.DryRunWeatherUpdate(frequency)
+	} else {
+		result, err = s.subscriptionService.SendWeatherUpdate(frequency)
+	}
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("Weather update run triggered",
+		"frequency", frequency, "dryRun", dryRun, "sent", result.Sent, "failed", result.Failed)
+	c.JSON(http.StatusOK, gin.H{
+		"dry_run": dryRun,
+		"sent":    result.Sent,
+		"failed":  result.Failed,
+	})
+}
+
+// cleanupTokens deletes expired tokens on demand, complementing the
+// scheduler's periodic cleanup, and reports how many rows were removed
+func (s *Server) cleanupTokens(c *gin.Context) {
+	deleted, err := s.tokenCleaner.DeleteExpiredTokens()
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("Expired tokens cleaned up", "deleted", deleted)
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// emailPreview renders the requested email template with sample data and
+// returns the HTML directly, so template changes can be inspected without
+// triggering a real subscription
+func (s *Server) emailPreview(c *gin.Context) {
+	if s.emailPreviewer == nil {
+		s.handleError(c, weathererr.NewNotFoundError("email preview is not available"))
+		return
+	}
+
+	emailType := c.Query("type")
+	if emailType == "" {
+		s.handleError(c, weathererr.NewValidationError("type parameter is required"))
+		return
+	}
+
+	html, err := s.emailPreviewer.RenderEmailPreview(emailType, c.Query("city"), c.Query("frequency"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+}
+
+// listProviders describes the multi-provider setup for debugging: provider
+// order, which API keys are configured (booleans, never the key values),
+// cache and logging settings, capabilities, and circuit-breaker states when
+// present. Admin-protected because it reveals infrastructure details
+func (s *Server) listProviders(c *gin.Context) {
+	if s.providerMetrics == nil {
+		s.handleError(c, weathererr.NewNotFoundError("provider information is not available"))
+		return
+	}
+
+	response := gin.H{
+		"keys_configured": gin.H{
+			"weatherapi":     s.config.Weather.APIKey != "",
+			"openweathermap": s.config.Weather.OpenWeatherMapKey != "",
+			"accuweather":    s.config.Weather.AccuWeatherKey != "",
+		},
+	}
+	for key, value := range s.providerMetrics.GetProviderInfo() {
+		response[key] = value
+	}
+
+	c.JSON(http.StatusOK, response)
+}
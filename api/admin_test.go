@@ -0,0 +1,754 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+	"weatherapi.app/providers"
+	"weatherapi.app/service"
+)
+
+// MockSubscriptionLister for testing the admin listing endpoint
+type MockSubscriptionLister struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionLister) List(offset, limit int, confirmedOnly bool) ([]models.Subscription, int64, error) {
+	args := m.Called(offset, limit, confirmedOnly)
+	return args.Get(0).([]models.Subscription), args.Get(1).(int64), args.Error(2)
+}
+
+func setupAdminTestServer(t *testing.T, adminKey string, lister SubscriptionLister) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		SubscriptionLister:  lister,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestAdminListSubscriptions_MissingKey(t *testing.T) {
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminListSubscriptions_WrongKey(t *testing.T) {
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions", nil)
+	req.Header.Set(adminAPIKeyHeader, "wrong")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminListSubscriptions_NoKeyConfigured(t *testing.T) {
+	router := setupAdminTestServer(t, "", new(MockSubscriptionLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions", nil)
+	req.Header.Set(adminAPIKeyHeader, "")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminListSubscriptions_Success(t *testing.T) {
+	lister := new(MockSubscriptionLister)
+	router := setupAdminTestServer(t, "secret", lister)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "a@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{ID: 2, Email: "b@example.com", City: "Paris", Frequency: "hourly", Confirmed: true},
+	}
+	lister.On("List", 50, 50, true).Return(subscriptions, int64(120), nil)
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions?page=2&pageSize=50&confirmed=true", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Subscriptions []models.Subscription `json:"subscriptions"`
+		Total         int64                 `json:"total"`
+		Page          int                   `json:"page"`
+		PageSize      int                   `json:"page_size"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Subscriptions, 2)
+	assert.Equal(t, int64(120), response.Total)
+	assert.Equal(t, 2, response.Page)
+	assert.Equal(t, 50, response.PageSize)
+
+	lister.AssertExpectations(t)
+}
+
+func TestAdminListSubscriptions_InvalidPagination(t *testing.T) {
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionLister))
+
+	for _, query := range []string{"page=0", "page=abc", "pageSize=0", "pageSize=500"} {
+		req := httptest.NewRequest("GET", "/api/admin/subscriptions?"+query, nil)
+		req.Header.Set(adminAPIKeyHeader, "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "query %q", query)
+	}
+}
+
+// MockTokenCleaner for testing the admin token cleanup endpoint
+type MockTokenCleaner struct {
+	mock.Mock
+}
+
+func (m *MockTokenCleaner) DeleteExpiredTokens() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupTokenCleanupTestServer(t *testing.T, adminKey string, cleaner TokenCleaner) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		TokenCleaner:        cleaner,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestAdminCleanupTokens_MissingKey(t *testing.T) {
+	cleaner := new(MockTokenCleaner)
+	router := setupTokenCleanupTestServer(t, "secret", cleaner)
+
+	req := httptest.NewRequest("POST", "/api/admin/tokens/cleanup", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	cleaner.AssertNotCalled(t, "DeleteExpiredTokens")
+}
+
+func TestAdminCleanupTokens_Success(t *testing.T) {
+	cleaner := new(MockTokenCleaner)
+	cleaner.On("DeleteExpiredTokens").Return(int64(7), nil)
+
+	router := setupTokenCleanupTestServer(t, "secret", cleaner)
+
+	req := httptest.NewRequest("POST", "/api/admin/tokens/cleanup", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]int64
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(7), response["deleted"])
+	cleaner.AssertExpectations(t)
+}
+
+func TestAdminCleanupTokens_DatabaseError(t *testing.T) {
+	cleaner := new(MockTokenCleaner)
+	cleaner.On("DeleteExpiredTokens").Return(int64(0), weathererr.NewDatabaseError("failed to delete expired tokens", nil))
+
+	router := setupTokenCleanupTestServer(t, "secret", cleaner)
+
+	req := httptest.NewRequest("POST", "/api/admin/tokens/cleanup", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// MockFailedEmailLister for testing the admin failed-emails endpoint
+type MockFailedEmailLister struct {
+	mock.Mock
+}
+
+func (m *MockFailedEmailLister) List(offset, limit int) ([]models.FailedEmail, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.FailedEmail), args.Get(1).(int64), args.Error(2)
+}
+
+func setupFailedEmailTestServer(t *testing.T, adminKey string, lister FailedEmailLister) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		FailedEmailLister:   lister,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestAdminListFailedEmails_MissingKey(t *testing.T) {
+	lister := new(MockFailedEmailLister)
+	router := setupFailedEmailTestServer(t, "secret", lister)
+
+	req := httptest.NewRequest("GET", "/api/admin/failed-emails", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	lister.AssertNotCalled(t, "List")
+}
+
+func TestAdminListFailedEmails_Success(t *testing.T) {
+	lister := new(MockFailedEmailLister)
+	router := setupFailedEmailTestServer(t, "secret", lister)
+
+	failedEmails := []models.FailedEmail{
+		{ID: 2, To: "b@example.com", Subject: "Weather Update for Paris", Reason: "smtp down"},
+		{ID: 1, To: "a@example.com", Subject: "Weather Update for London", Reason: "smtp down"},
+	}
+	lister.On("List", 0, 50).Return(failedEmails, int64(2), nil)
+
+	req := httptest.NewRequest("GET", "/api/admin/failed-emails", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		FailedEmails []models.FailedEmail `json:"failed_emails"`
+		Total        int64                `json:"total"`
+		Page         int                  `json:"page"`
+		PageSize     int                  `json:"page_size"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.FailedEmails, 2)
+	assert.Equal(t, int64(2), response.Total)
+	assert.Equal(t, "b@example.com", response.FailedEmails[0].To)
+
+	lister.AssertExpectations(t)
+}
+
+func TestAdminListFailedEmails_InvalidPagination(t *testing.T) {
+	router := setupFailedEmailTestServer(t, "secret", new(MockFailedEmailLister))
+
+	for _, query := range []string{"page=0", "pageSize=abc", "pageSize=500"} {
+		req := httptest.NewRequest("GET", "/api/admin/failed-emails?"+query, nil)
+		req.Header.Set(adminAPIKeyHeader, "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "query %q", query)
+	}
+}
+
+// MockCacheInvalidator is a provider manager that also supports dropping a
+// city's cached weather entry
+type MockCacheInvalidator struct {
+	MockProviderManager
+}
+
+func (m *MockCacheInvalidator) InvalidateCity(_ context.Context, city string) error {
+	args := m.Called(city)
+	return args.Error(0)
+}
+
+func setupCacheInvalidationTestServer(t *testing.T, adminKey string, manager providers.WeatherManager) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     manager,
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestAdminInvalidateWeatherCache_MissingKey(t *testing.T) {
+	invalidator := new(MockCacheInvalidator)
+	router := setupCacheInvalidationTestServer(t, "secret", invalidator)
+
+	req := httptest.NewRequest("DELETE", "/api/admin/cache/weather?city=London", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	invalidator.AssertNotCalled(t, "InvalidateCity")
+}
+
+func TestAdminInvalidateWeatherCache_MissingCity(t *testing.T) {
+	router := setupCacheInvalidationTestServer(t, "secret", new(MockCacheInvalidator))
+
+	req := httptest.NewRequest("DELETE", "/api/admin/cache/weather", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminInvalidateWeatherCache_Success(t *testing.T) {
+	invalidator := new(MockCacheInvalidator)
+	invalidator.On("InvalidateCity", "London").Return(nil)
+
+	router := setupCacheInvalidationTestServer(t, "secret", invalidator)
+
+	req := httptest.NewRequest("DELETE", "/api/admin/cache/weather?city=London", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "London", response["invalidated"])
+	invalidator.AssertExpectations(t)
+}
+
+func TestAdminInvalidateWeatherCache_NotCached(t *testing.T) {
+	invalidator := new(MockCacheInvalidator)
+	invalidator.On("InvalidateCity", "Atlantis").Return(weathererr.NewNotFoundError("no cached weather entry for Atlantis"))
+
+	router := setupCacheInvalidationTestServer(t, "secret", invalidator)
+
+	req := httptest.NewRequest("DELETE", "/api/admin/cache/weather?city=Atlantis", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminInvalidateWeatherCache_NotSupported(t *testing.T) {
+	router := setupCacheInvalidationTestServer(t, "secret", new(MockProviderManager))
+
+	req := httptest.NewRequest("DELETE", "/api/admin/cache/weather?city=London", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// MockStatsLister extends the lister with aggregate counts for the admin
+// stats endpoint
+type MockStatsLister struct {
+	MockSubscriptionLister
+}
+
+func (m *MockStatsLister) CountByFrequency() (map[string]int64, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockStatsLister) CountConfirmed() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAdminStats_MissingKey(t *testing.T) {
+	router := setupAdminTestServer(t, "secret", new(MockStatsLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminStats_Success(t *testing.T) {
+	lister := new(MockStatsLister)
+	router := setupAdminTestServer(t, "secret", lister)
+
+	lister.On("CountByFrequency").Return(map[string]int64{"daily": 7, "hourly": 3}, nil)
+	lister.On("CountConfirmed").Return(int64(8), nil)
+
+	req := httptest.NewRequest("GET", "/api/admin/stats", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Subscriptions struct {
+			Total       int64            `json:"total"`
+			Confirmed   int64            `json:"confirmed"`
+			ByFrequency map[string]int64 `json:"by_frequency"`
+		} `json:"subscriptions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(10), response.Subscriptions.Total)
+	assert.Equal(t, int64(8), response.Subscriptions.Confirmed)
+	assert.Equal(t, int64(7), response.Subscriptions.ByFrequency["daily"])
+
+	lister.AssertExpectations(t)
+}
+
+func TestAdminStats_NotSupported(t *testing.T) {
+	// A lister without the aggregate queries cannot serve statistics
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/stats", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// MockSubscriptionExporter extends the lister with batched streaming for the
+// CSV export endpoint
+type MockSubscriptionExporter struct {
+	MockSubscriptionLister
+	batches [][]models.Subscription
+}
+
+func (m *MockSubscriptionExporter) StreamConfirmed(_ int, fn func([]models.Subscription) error) error {
+	for _, batch := range m.batches {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAdminExportSubscriptions_MissingKey(t *testing.T) {
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionExporter))
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminExportSubscriptions_Success(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	exporter := &MockSubscriptionExporter{batches: [][]models.Subscription{
+		{{ID: 1, Email: "a@example.com", City: "London", Frequency: "daily", Confirmed: true, CreatedAt: created}},
+		{{ID: 2, Email: "b@example.com", City: "Paris", Frequency: "hourly", Confirmed: true, CreatedAt: created}},
+	}}
+	router := setupAdminTestServer(t, "secret", exporter)
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions/export", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment; filename=\"subscriptions-")
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"email", "city", "frequency", "confirmed", "created_at"}, records[0])
+	assert.Equal(t, []string{"a@example.com", "London", "daily", "true", "2026-01-02T03:04:05Z"}, records[1])
+	assert.Equal(t, []string{"b@example.com", "Paris", "hourly", "true", "2026-01-02T03:04:05Z"}, records[2])
+}
+
+func TestAdminExportSubscriptions_NotSupported(t *testing.T) {
+	// A lister without batched streaming cannot serve the export
+	router := setupAdminTestServer(t, "secret", new(MockSubscriptionLister))
+
+	req := httptest.NewRequest("GET", "/api/admin/subscriptions/export", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// setupWeatherTriggerTestServer wires a specific subscription service so the
+// trigger endpoint's dry-run capability discovery can be exercised
+func setupWeatherTriggerTestServer(t *testing.T, adminKey string, subscriptionService service.SubscriptionServiceInterface) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: subscriptionService,
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		SubscriptionLister:  new(MockSubscriptionLister),
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+// MockDryRunSubscriptionService extends the subscription service mock with
+// the dry-run capability
+type MockDryRunSubscriptionService struct {
+	MockSubscriptionService
+}
+
+func (m *MockDryRunSubscriptionService) DryRunWeatherUpdate(frequency string) (*service.WeatherUpdateResult, error) {
+	args := m.Called(frequency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.WeatherUpdateResult), args.Error(1)
+}
+
+func TestAdminTriggerWeatherUpdate_DryRun(t *testing.T) {
+	svc := new(MockDryRunSubscriptionService)
+	router := setupWeatherTriggerTestServer(t, "secret", svc)
+
+	svc.On("DryRunWeatherUpdate", "weekly").Return(&service.WeatherUpdateResult{Sent: 4, Failed: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/api/admin/notifications/weather?frequency=weekly&dry_run=true", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		DryRun bool `json:"dry_run"`
+		Sent   int  `json:"sent"`
+		Failed int  `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 4, response.Sent)
+	assert.Equal(t, 1, response.Failed)
+
+	svc.AssertExpectations(t)
+}
+
+func TestAdminTriggerWeatherUpdate_RealSend(t *testing.T) {
+	svc := new(MockSubscriptionService)
+	router := setupWeatherTriggerTestServer(t, "secret", svc)
+
+	svc.On("SendWeatherUpdate", "daily").Return(&service.WeatherUpdateResult{Sent: 2}, nil)
+
+	req := httptest.NewRequest("POST", "/api/admin/notifications/weather?frequency=daily", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestAdminTriggerWeatherUpdate_DryRunNotSupported(t *testing.T) {
+	// A subscription service without the dry-run capability cannot serve it
+	router := setupWeatherTriggerTestServer(t, "secret", new(MockSubscriptionService))
+
+	req := httptest.NewRequest("POST", "/api/admin/notifications/weather?frequency=daily&dry_run=true", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminTriggerWeatherUpdate_MissingFrequency(t *testing.T) {
+	router := setupWeatherTriggerTestServer(t, "secret", new(MockSubscriptionService))
+
+	req := httptest.NewRequest("POST", "/api/admin/notifications/weather", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func setupEmailPreviewTestServer(t *testing.T, adminKey string, previewer EmailPreviewer) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = adminKey
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		SubscriptionLister:  new(MockSubscriptionLister),
+		EmailPreviewer:      previewer,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestAdminEmailPreview(t *testing.T) {
+	// The real email service renders previews without touching its provider
+	router := setupEmailPreviewTestServer(t, "secret", service.NewEmailService(nil))
+
+	req := httptest.NewRequest("GET", "/api/admin/email-preview?type=welcome&city=London&frequency=daily", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "London")
+}
+
+func TestAdminEmailPreview_MissingType(t *testing.T) {
+	router := setupEmailPreviewTestServer(t, "secret", service.NewEmailService(nil))
+
+	req := httptest.NewRequest("GET", "/api/admin/email-preview?city=London", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminEmailPreview_UnknownType(t *testing.T) {
+	router := setupEmailPreviewTestServer(t, "secret", service.NewEmailService(nil))
+
+	req := httptest.NewRequest("GET", "/api/admin/email-preview?type=invoice", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminEmailPreview_NotConfigured(t *testing.T) {
+	router := setupEmailPreviewTestServer(t, "secret", nil)
+
+	req := httptest.NewRequest("GET", "/api/admin/email-preview?type=welcome", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func setupProvidersTestServer(t *testing.T, metricsService *MockProviderMetricsService) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.AdminAPIKey = "secret"
+	cfg.Weather.APIKey = "weather-key"
+	cfg.Weather.OpenWeatherMapKey = "ow-key"
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     metricsService,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestListProviders_RequiresAdminKey(t *testing.T) {
+	router := setupProvidersTestServer(t, new(MockProviderMetricsService))
+
+	req := httptest.NewRequest("GET", "/api/providers", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestListProviders_Success(t *testing.T) {
+	metricsService := new(MockProviderMetricsService)
+	metricsService.On("GetProviderInfo").Return(map[string]interface{}{
+		"provider_order":   []string{"weatherapi", "openweathermap"},
+		"cache_enabled":    true,
+		"cache_type":       "memory",
+		"logging_enabled":  false,
+		"circuit_breakers": map[string]string{"weatherapi": "closed"},
+	})
+
+	router := setupProvidersTestServer(t, metricsService)
+
+	req := httptest.NewRequest("GET", "/api/providers", nil)
+	req.Header.Set(adminAPIKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	keys, ok := response["keys_configured"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, true, keys["weatherapi"])
+	assert.Equal(t, true, keys["openweathermap"])
+	assert.Equal(t, false, keys["accuweather"])
+
+	assert.Equal(t, true, response["cache_enabled"])
+	assert.Equal(t, "memory", response["cache_type"])
+	assert.NotNil(t, response["circuit_breakers"])
+
+	// Key values themselves must never appear in the response
+	assert.NotContains(t, w.Body.String(), "weather-key")
+	assert.NotContains(t, w.Body.String(), "ow-key")
+
+	metricsService.AssertExpectations(t)
+}
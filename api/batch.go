@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+const (
+	// maxBatchCities bounds how many cities a single batch request may ask for
+	maxBatchCities = 50
+
+	// defaultBatchConcurrency is used when no batch concurrency is configured
+	defaultBatchConcurrency = 5
+)
+
+// batchWeather handles POST /api/weather/batch, fetching weather for multiple
+// cities concurrently. Partial failures are reported per city and do not fail
+// the whole batch.
+func (s *Server) batchWeather(c *gin.Context) {
+	var req models.BatchWeatherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.handleError(c, weathererr.NewValidationError("invalid request format"))
+		return
+	}
+
+	if len(req.Cities) == 0 {
+		s.handleError(c, weathererr.NewValidationError("cities list cannot be empty"))
+		return
+	}
+	if len(req.Cities) > maxBatchCities {
+		s.handleError(c, weathererr.NewValidationError(fmt.Sprintf("cities list cannot exceed %d entries", maxBatchCities)))
+		return
+	}
+
+	concurrency := s.config.Weather.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	requestLogger(c).Debug("Handling batch weather request", "cities", len(req.Cities), "concurrency", concurrency)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]models.BatchWeatherEntry, len(req.Cities))
+		slots   = make(chan struct{}, concurrency)
+	)
+
+	for _, city := range req.Cities {
+		wg.Add(1)
+		go func(city string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			weather, err := s.weatherService.GetWeather(c.Request.Context(), city)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results[city] = models.BatchWeatherEntry{Error: batchErrorMessage(err)}
+				return
+			}
+			results[city] = models.BatchWeatherEntry{Weather: weather}
+		}(city)
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, models.BatchWeatherResponse{Results: results})
+}
+
+// batchErrorMessage maps an error to a client-safe per-city message
+func batchErrorMessage(err error) string {
+	var appErr *weathererr.AppError
+	if !errors.As(err, &appErr) {
+		return "internal error"
+	}
+
+	switch appErr.Type {
+	case weathererr.DatabaseError:
+		return "internal error"
+	case weathererr.ExternalAPIError:
+		return "external service unavailable"
+	default:
+		return appErr.Message
+	}
+}
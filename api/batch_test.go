@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// postBatch sends a batch weather request and returns the recorder
+func postBatch(setup *TestServerSetup, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/api/weather/batch", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchWeather_Success(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	setup.MockWeather.On("GetWeather", "Paris").Return(&models.WeatherResponse{Temperature: 18.0}, nil)
+
+	w := postBatch(setup, models.BatchWeatherRequest{Cities: []string{"London", "Paris"}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.BatchWeatherResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, 15.0, response.Results["London"].Weather.Temperature)
+	assert.Equal(t, 18.0, response.Results["Paris"].Weather.Temperature)
+}
+
+func TestBatchWeather_PartialFailure(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	setup.MockWeather.On("GetWeather", "Nowhere").Return(nil, errors.NewNotFoundError("city not found"))
+
+	w := postBatch(setup, models.BatchWeatherRequest{Cities: []string{"London", "Nowhere"}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.BatchWeatherResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 15.0, response.Results["London"].Weather.Temperature)
+	assert.Nil(t, response.Results["Nowhere"].Weather)
+	assert.Equal(t, "city not found", response.Results["Nowhere"].Error)
+}
+
+func TestBatchWeather_EmptyCities(t *testing.T) {
+	setup := setupTestServer()
+
+	w := postBatch(setup, map[string][]string{"cities": {}})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBatchWeather_TooManyCities(t *testing.T) {
+	setup := setupTestServer()
+
+	cities := make([]string, maxBatchCities+1)
+	for i := range cities {
+		cities[i] = fmt.Sprintf("City%d", i)
+	}
+
+	w := postBatch(setup, models.BatchWeatherRequest{Cities: cities})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResponse models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.Contains(t, errorResponse.Error, "cannot exceed 50")
+}
+
+func TestBatchWeather_InvalidBody(t *testing.T) {
+	setup := setupTestServer()
+
+	req := httptest.NewRequest("POST", "/api/weather/batch", bytes.NewReader([]byte("not json")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePinger verifies cache backend connectivity for readiness checks
+type CachePinger interface {
+	PingCache(ctx context.Context) error
+}
+
+// livenessProbe reports that the process is running; it never fails so
+// orchestrators only restart the pod when the process itself is gone
+func (s *Server) livenessProbe(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readinessProbe reports whether the server can serve traffic, checking each
+// configured dependency and naming the first one that fails
+func (s *Server) readinessProbe(c *gin.Context) {
+	logger := requestLogger(c)
+	if s.db != nil {
+		if err := s.pingDatabase(c.Request.Context()); err != nil {
+			logger.Error("Readiness check failed", "component", "database", "error", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "component": "database"})
+			return
+		}
+	}
+
+	if s.cachePinger != nil {
+		if err := s.cachePinger.PingCache(c.Request.Context()); err != nil {
+			logger.Error("Readiness check failed", "component", "cache", "error", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "component": "cache"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+func (s *Server) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+// stubCachePinger fakes cache connectivity for readiness probe tests
+type stubCachePinger struct {
+	err error
+}
+
+func (s *stubCachePinger) PingCache(_ context.Context) error {
+	return s.err
+}
+
+func setupHealthTestServer(t *testing.T, cachePinger CachePinger) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server, err := NewServer(ServerOptions{
+		Config:              &config.Config{AppBaseURL: "http://localhost:8080"},
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		CachePinger:         cachePinger,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestLivenessProbe(t *testing.T) {
+	router := setupHealthTestServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/health/live", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadinessProbe_AllHealthy(t *testing.T) {
+	router := setupHealthTestServer(t, &stubCachePinger{})
+
+	req := httptest.NewRequest("GET", "/health/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ready", response["status"])
+}
+
+func TestReadinessProbe_CacheUnavailable(t *testing.T) {
+	router := setupHealthTestServer(t, &stubCachePinger{err: errors.New("connection refused")})
+
+	req := httptest.NewRequest("GET", "/health/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "cache", response["component"])
+}
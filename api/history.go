@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+const (
+	defaultHistoryHours = 24
+	maxHistoryHours     = 168
+)
+
+// WeatherHistoryReader lists stored weather observations for a city; used by
+// the history endpoint when observation storage is enabled
+type WeatherHistoryReader interface {
+	FindByCity(city string, since time.Time) ([]models.WeatherObservation, error)
+}
+
+// getWeatherHistory returns the stored observation series for a city over
+// the requested window, most recent observations last
+func (s *Server) getWeatherHistory(c *gin.Context) {
+	if s.weatherHistory == nil {
+		s.handleError(c, weathererr.NewNotFoundError("weather history is not enabled"))
+		return
+	}
+
+	city := c.Query("city")
+	if city == "" {
+		s.handleError(c, weathererr.NewValidationError("city parameter is required"))
+		return
+	}
+
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", strconv.Itoa(defaultHistoryHours)))
+	if err != nil || hours < 1 || hours > maxHistoryHours {
+		s.handleError(c, weathererr.NewValidationError("hours must be between 1 and 168"))
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	observations, err := s.weatherHistory.FindByCity(city, since)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"city":         city,
+		"hours":        hours,
+		"observations": observations,
+	})
+}
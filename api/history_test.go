@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/models"
+)
+
+// MockWeatherHistoryReader for testing the weather history endpoint
+type MockWeatherHistoryReader struct {
+	mock.Mock
+}
+
+func (m *MockWeatherHistoryReader) FindByCity(city string, since time.Time) ([]models.WeatherObservation, error) {
+	args := m.Called(city, since)
+	return args.Get(0).([]models.WeatherObservation), args.Error(1)
+}
+
+func setupHistoryTestServer(t *testing.T, reader WeatherHistoryReader) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server, err := NewServer(ServerOptions{
+		Config:               &config.Config{AppBaseURL: "http://localhost:8080"},
+		WeatherService:       new(MockWeatherService),
+		SubscriptionService:  new(MockSubscriptionService),
+		ProviderManager:      new(MockProviderManager),
+		ProviderMetrics:      new(MockProviderMetricsService),
+		WeatherHistoryReader: reader,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter()
+}
+
+func TestWeatherHistory_Success(t *testing.T) {
+	reader := new(MockWeatherHistoryReader)
+	router := setupHistoryTestServer(t, reader)
+
+	observations := []models.WeatherObservation{
+		{ID: 1, City: "London", Temperature: 14.0, Description: "Cloudy"},
+		{ID: 2, City: "London", Temperature: 16.0, Description: "Sunny"},
+	}
+	reader.On("FindByCity", "London", mock.AnythingOfType("time.Time")).Return(observations, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather/history?city=London&hours=24", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		City         string                      `json:"city"`
+		Hours        int                         `json:"hours"`
+		Observations []models.WeatherObservation `json:"observations"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "London", response.City)
+	assert.Equal(t, 24, response.Hours)
+	assert.Len(t, response.Observations, 2)
+
+	reader.AssertExpectations(t)
+}
+
+func TestWeatherHistory_MissingCity(t *testing.T) {
+	router := setupHistoryTestServer(t, new(MockWeatherHistoryReader))
+
+	req := httptest.NewRequest("GET", "/api/weather/history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWeatherHistory_InvalidHours(t *testing.T) {
+	router := setupHistoryTestServer(t, new(MockWeatherHistoryReader))
+
+	for _, query := range []string{"hours=0", "hours=abc", "hours=500"} {
+		req := httptest.NewRequest("GET", "/api/weather/history?city=London&"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "query %q", query)
+	}
+}
+
+func TestWeatherHistory_NotEnabled(t *testing.T) {
+	router := setupHistoryTestServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather/history?city=London", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"weatherapi.app/models"
+)
+
+// idempotencyKeyHeader carries the client-supplied key that makes subscribe
+// requests safe to retry
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyStore records processed subscribe responses keyed by an
+// Idempotency-Key header scoped to email+city
+type IdempotencyStore interface {
+	Find(key, email, city string) (*models.IdempotencyKey, error)
+	Save(key, email, city string, statusCode int, body string) error
+}
+
+// replaySubscribe answers a retried subscribe request with the stored
+// response so no second confirmation email goes out; it reports whether the
+// request was handled. Lookup failures fall through to normal processing
+func (s *Server) replaySubscribe(c *gin.Context, key string, req *models.SubscriptionRequest) bool {
+	if key == "" || s.idempotencyStore == nil {
+		return false
+	}
+
+	record, err := s.idempotencyStore.Find(key, req.Email, req.City)
+	if err != nil {
+		requestLogger(c).Error("Idempotency key lookup error", "error", err, "key", key)
+		return false
+	}
+	if record == nil {
+		return false
+	}
+
+	requestLogger(c).Debug("Replaying stored subscribe response", "key", key, "email", req.Email, "city", req.City)
+	c.Data(record.StatusCode, "application/json; charset=utf-8", []byte(record.Body))
+	return true
+}
+
+// recordSubscribeResponse stores a successful subscribe response under the
+// client's idempotency key. Only successes are recorded so a failed attempt
+// can still be retried for real
+func (s *Server) recordSubscribeResponse(c *gin.Context, key string, req *models.SubscriptionRequest, response models.SubscribeResponse) {
+	if key == "" || s.idempotencyStore == nil {
+		return
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		requestLogger(c).Error("Marshal subscribe response for idempotency store", "error", err, "key", key)
+		return
+	}
+
+	if err := s.idempotencyStore.Save(key, req.Email, req.City, http.StatusOK, string(body)); err != nil {
+		requestLogger(c).Error("Store idempotency key", "error", err, "key", key)
+	}
+}
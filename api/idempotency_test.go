@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// MockIdempotencyStore for testing subscribe replay behavior
+type MockIdempotencyStore struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyStore) Find(key, email, city string) (*models.IdempotencyKey, error) {
+	args := m.Called(key, email, city)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyStore) Save(key, email, city string, statusCode int, body string) error {
+	args := m.Called(key, email, city, statusCode, body)
+	return args.Error(0)
+}
+
+func setupIdempotencyTestServer(t *testing.T, store IdempotencyStore) (*gin.Engine, *MockSubscriptionService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mockSubscription := new(MockSubscriptionService)
+	server, err := NewServer(ServerOptions{
+		Config:              &config.Config{AppBaseURL: "http://localhost:8080"},
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: mockSubscription,
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		IdempotencyStore:    store,
+	})
+	require.NoError(t, err)
+
+	return server.GetRouter(), mockSubscription
+}
+
+func subscribeRequest(idempotencyKey string) *http.Request {
+	formData := "email=test%40example.com&city=London&frequency=daily"
+	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+	return req
+}
+
+func TestSubscribe_IdempotentReplay(t *testing.T) {
+	store := new(MockIdempotencyStore)
+	router, mockSubscription := setupIdempotencyTestServer(t, store)
+
+	storedBody := `{"message":"Subscription successful. Confirmation email sent.","city":"London","frequency":"daily","confirmation_pending":true}`
+	store.On("Find", "retry-1", "test@example.com", "London").Return(&models.IdempotencyKey{
+		Key:        "retry-1",
+		Email:      "test@example.com",
+		City:       "London",
+		StatusCode: http.StatusOK,
+		Body:       storedBody,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscribeRequest("retry-1"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, storedBody, w.Body.String())
+
+	// The service must not run again, so no second confirmation email is sent
+	mockSubscription.AssertNotCalled(t, "Subscribe", mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestSubscribe_RecordsResponseUnderKey(t *testing.T) {
+	store := new(MockIdempotencyStore)
+	router, mockSubscription := setupIdempotencyTestServer(t, store)
+
+	store.On("Find", "fresh-1", "test@example.com", "London").Return(nil, nil)
+	mockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(&models.Subscription{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+	}, nil)
+	store.On("Save", "fresh-1", "test@example.com", "London", http.StatusOK, mock.AnythingOfType("string")).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscribeRequest("fresh-1"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSubscription.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestSubscribe_NoIdempotencyKeySkipsStore(t *testing.T) {
+	store := new(MockIdempotencyStore)
+	router, mockSubscription := setupIdempotencyTestServer(t, store)
+
+	mockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(&models.Subscription{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscribeRequest(""))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	store.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscribe_FailedAttemptNotRecorded(t *testing.T) {
+	store := new(MockIdempotencyStore)
+	router, mockSubscription := setupIdempotencyTestServer(t, store)
+
+	store.On("Find", "retry-2", "test@example.com", "London").Return(nil, nil)
+	mockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(nil, errors.NewAlreadyExistsError("email already subscribed"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, subscribeRequest("retry-2"))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
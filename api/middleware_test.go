@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"weatherapi.app/config"
+	"weatherapi.app/logging"
+	"weatherapi.app/models"
+)
+
+// setupTimeoutTestServer builds a server whose /api/weather route times out quickly
+func setupTimeoutTestServer(timeout time.Duration) (*gin.Engine, *MockWeatherService) {
+	gin.SetMode(gin.TestMode)
+
+	mockWeather := new(MockWeatherService)
+
+	server, err := NewServer(ServerOptions{
+		Config: &config.Config{
+			AppBaseURL: "http://localhost:8080",
+			Server: config.ServerConfig{
+				Port:             8080,
+				EndpointTimeouts: map[string]time.Duration{"/api/weather": timeout},
+			},
+		},
+		WeatherService:      mockWeather,
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	if err != nil {
+		panic("Failed to create test server: " + err.Error())
+	}
+
+	return server.GetRouter(), mockWeather
+}
+
+func TestTimeoutMiddleware_SlowHandlerReturns504(t *testing.T) {
+	router, mockWeather := setupTimeoutTestServer(20 * time.Millisecond)
+
+	// A context-aware service gives up once the deadline passes and returns
+	// the context error, which the server maps to a 504
+	mockWeather.On("GetWeather", "London").Run(func(args mock.Arguments) {
+		time.Sleep(100 * time.Millisecond)
+	}).Return(nil, context.DeadlineExceeded)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "request timed out")
+}
+
+func TestTimeoutMiddleware_FastHandlerUnaffected(t *testing.T) {
+	router, mockWeather := setupTimeoutTestServer(time.Second)
+
+	mockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// setupConcurrencyTestServer builds a server with a global in-flight request limit
+func setupConcurrencyTestServer(limit int) (*gin.Engine, *MockWeatherService) {
+	gin.SetMode(gin.TestMode)
+
+	mockWeather := new(MockWeatherService)
+
+	server, err := NewServer(ServerOptions{
+		Config: &config.Config{
+			AppBaseURL: "http://localhost:8080",
+			Server: config.ServerConfig{
+				Port:                  8080,
+				MaxConcurrentRequests: limit,
+			},
+		},
+		WeatherService:      mockWeather,
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	if err != nil {
+		panic("Failed to create test server: " + err.Error())
+	}
+
+	return server.GetRouter(), mockWeather
+}
+
+func TestConcurrencyLimitMiddleware_SaturatedReturns503(t *testing.T) {
+	router, mockWeather := setupConcurrencyTestServer(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockWeather.On("GetWeather", "London").Run(func(args mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}()
+
+	<-started
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "server is busy")
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimitMiddleware_ProbeExemptWhenSaturated(t *testing.T) {
+	router, mockWeather := setupConcurrencyTestServer(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockWeather.On("GetWeather", "London").Run(func(args mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+		router.ServeHTTP(w, req)
+	}()
+
+	<-started
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimitMiddleware_ZeroLimitUnlimited(t *testing.T) {
+	router, mockWeather := setupConcurrencyTestServer(0)
+
+	mockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTimeoutMiddleware_UnconfiguredRouteUnaffected(t *testing.T) {
+	router, _ := setupTimeoutTestServer(time.Millisecond)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	router.ServeHTTP(w, req)
+
+	// The route is not in the timeout map, so it must not be aborted with 504
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	router, mockWeather := setupConcurrencyTestServer(0)
+
+	mockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=London", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDMiddleware_EchoesProvidedID(t *testing.T) {
+	router, _ := setupConcurrencyTestServer(0)
+
+	// Extra route to observe the request ID stored in the request context
+	router.GET("/request-id-check", func(c *gin.Context) {
+		c.String(http.StatusOK, logging.RequestIDFromContext(c.Request.Context()))
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/request-id-check", nil)
+	req.Header.Set("X-Request-ID", "client-supplied-id")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "client-supplied-id", w.Header().Get("X-Request-ID"))
+	assert.Equal(t, "client-supplied-id", w.Body.String())
+}
@@ -1,21 +1,50 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
+	"hash/fnv"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/gorm"
 	"weatherapi.app/config"
 	weathererr "weatherapi.app/errors"
+	"weatherapi.app/metrics"
 	"weatherapi.app/models"
 	"weatherapi.app/providers"
+	"weatherapi.app/providers/cache"
 	"weatherapi.app/service"
 )
 
+// CityAccessChecker reports whether a city has at least one confirmed
+// subscription; used when weather queries are restricted to subscribers
+type CityAccessChecker interface {
+	HasConfirmedSubscriptionForCity(city string) (bool, error)
+}
+
+// ProviderSelector restricts a weather lookup to one named provider,
+// bypassing the fallback chain; discovered on the provider manager via type
+// assertion so mocks without it keep working
+type ProviderSelector interface {
+	GetWeatherFrom(ctx context.Context, providerName, city string) (*models.WeatherResponse, error)
+}
+
+// CachePoolStatsReader reports Redis connection pool counters; discovered on
+// the provider metrics source via type assertion so cache backends without a
+// connection pool keep working
+type CachePoolStatsReader interface {
+	GetCachePoolStats() (cache.PoolStats, bool)
+}
+
 // Server represents the HTTP server and API handler
 type Server struct {
 	router              *gin.Engine
@@ -25,16 +54,38 @@ type Server struct {
 	subscriptionService service.SubscriptionServiceInterface
 	providerManager     providers.WeatherManager
 	providerMetrics     providers.WeatherProviderMetrics
+	featureFlags        *config.FeatureFlags
+	cityAccess          CityAccessChecker
+	cachePinger         CachePinger
+	subscriptionLister  SubscriptionLister
+	idempotencyStore    IdempotencyStore
+	tokenCleaner        TokenCleaner
+	failedEmailLister   FailedEmailLister
+	weatherHistory      WeatherHistoryReader
+	emailPreviewer      EmailPreviewer
+
+	mu         sync.Mutex
+	httpServer *http.Server
+	listenAddr string
 }
 
 // ServerOptions contains all dependencies needed to create a new server
 type ServerOptions struct {
-	DB                  *gorm.DB
-	Config              *config.Config
-	WeatherService      service.WeatherServiceInterface
-	SubscriptionService service.SubscriptionServiceInterface
-	ProviderManager     providers.WeatherManager
-	ProviderMetrics     providers.WeatherProviderMetrics
+	DB                   *gorm.DB
+	Config               *config.Config
+	WeatherService       service.WeatherServiceInterface
+	SubscriptionService  service.SubscriptionServiceInterface
+	ProviderManager      providers.WeatherManager
+	ProviderMetrics      providers.WeatherProviderMetrics
+	FeatureFlags         *config.FeatureFlags
+	CityAccess           CityAccessChecker
+	CachePinger          CachePinger
+	SubscriptionLister   SubscriptionLister
+	IdempotencyStore     IdempotencyStore
+	TokenCleaner         TokenCleaner
+	FailedEmailLister    FailedEmailLister
+	WeatherHistoryReader WeatherHistoryReader
+	EmailPreviewer       EmailPreviewer
 }
 
 // Validate checks if all required dependencies are provided
@@ -102,6 +153,65 @@ func (b *ServerOptionsBuilder) WithProviderMetrics(providerMetrics providers.Wea
 	return b
 }
 
+// WithFeatureFlags sets the feature flag provider
+func (b *ServerOptionsBuilder) WithFeatureFlags(featureFlags *config.FeatureFlags) *ServerOptionsBuilder {
+	b.opts.FeatureFlags = featureFlags
+	return b
+}
+
+// WithCityAccess sets the checker used when weather queries are restricted
+// to subscribed cities
+func (b *ServerOptionsBuilder) WithCityAccess(cityAccess CityAccessChecker) *ServerOptionsBuilder {
+	b.opts.CityAccess = cityAccess
+	return b
+}
+
+// WithCachePinger sets the cache connectivity checker for readiness probes
+func (b *ServerOptionsBuilder) WithCachePinger(cachePinger CachePinger) *ServerOptionsBuilder {
+	b.opts.CachePinger = cachePinger
+	return b
+}
+
+// WithSubscriptionLister sets the lister backing the admin subscriptions endpoint
+func (b *ServerOptionsBuilder) WithSubscriptionLister(lister SubscriptionLister) *ServerOptionsBuilder {
+	b.opts.SubscriptionLister = lister
+	return b
+}
+
+// WithIdempotencyStore sets the store that lets subscribe requests be
+// retried safely with an Idempotency-Key header
+func (b *ServerOptionsBuilder) WithIdempotencyStore(store IdempotencyStore) *ServerOptionsBuilder {
+	b.opts.IdempotencyStore = store
+	return b
+}
+
+// WithTokenCleaner sets the cleaner backing the admin token cleanup endpoint
+func (b *ServerOptionsBuilder) WithTokenCleaner(cleaner TokenCleaner) *ServerOptionsBuilder {
+	b.opts.TokenCleaner = cleaner
+	return b
+}
+
+// WithFailedEmailLister sets the dead-letter log backing the admin
+// failed-emails endpoint
+func (b *ServerOptionsBuilder) WithFailedEmailLister(lister FailedEmailLister) *ServerOptionsBuilder {
+	b.opts.FailedEmailLister = lister
+	return b
+}
+
+// WithEmailPreviewer sets the renderer backing the admin email-preview
+// endpoint
+func (b *ServerOptionsBuilder) WithEmailPreviewer(previewer EmailPreviewer) *ServerOptionsBuilder {
+	b.opts.EmailPreviewer = previewer
+	return b
+}
+
+// WithWeatherHistoryReader sets the observation store backing the weather
+// history endpoint
+func (b *ServerOptionsBuilder) WithWeatherHistoryReader(reader WeatherHistoryReader) *ServerOptionsBuilder {
+	b.opts.WeatherHistoryReader = reader
+	return b
+}
+
 // Build creates the ServerOptions
 func (b *ServerOptionsBuilder) Build() ServerOptions {
 	return b.opts
@@ -115,6 +225,12 @@ func NewServer(opts ServerOptions) (*Server, error) {
 
 	router := gin.Default()
 
+	// Default flags from config when not provided explicitly (keeps tests simple)
+	featureFlags := opts.FeatureFlags
+	if featureFlags == nil {
+		featureFlags = config.NewFeatureFlags(opts.Config.Features)
+	}
+
 	server := &Server{
 		router:              router,
 		db:                  opts.DB,
@@ -123,6 +239,15 @@ func NewServer(opts ServerOptions) (*Server, error) {
 		subscriptionService: opts.SubscriptionService,
 		providerManager:     opts.ProviderManager,
 		providerMetrics:     opts.ProviderMetrics,
+		featureFlags:        featureFlags,
+		cityAccess:          opts.CityAccess,
+		cachePinger:         opts.CachePinger,
+		subscriptionLister:  opts.SubscriptionLister,
+		idempotencyStore:    opts.IdempotencyStore,
+		tokenCleaner:        opts.TokenCleaner,
+		failedEmailLister:   opts.FailedEmailLister,
+		emailPreviewer:      opts.EmailPreviewer,
+		weatherHistory:      opts.WeatherHistoryReader,
 	}
 
 	server.setupRoutes()
@@ -130,24 +255,82 @@ func NewServer(opts ServerOptions) (*Server, error) {
 }
 
 func (s *Server) setupRoutes() {
+	s.router.Use(requestIDMiddleware())
+	s.router.Use(concurrencyLimitMiddleware(s.config.Server.MaxConcurrentRequests))
+	s.router.Use(s.timeoutMiddleware())
+
 	api := s.router.Group("/api")
 	{
 		api.GET("/weather", s.getWeather)
+		api.POST("/weather/batch", s.batchWeather)
+		api.GET("/weather/history", s.getWeatherHistory)
+		api.GET("/forecast", s.getForecast)
 		api.POST("/subscribe", s.subscribe)
 		api.GET("/confirm/:token", s.confirmSubscription)
 		api.GET("/unsubscribe/:token", s.unsubscribe)
+		api.POST("/unsubscribe-all", s.requestUnsubscribeAll)
+		api.GET("/unsubscribe-all/:token", s.unsubscribeAll)
+		api.GET("/cancel/:token", s.cancelSubscription)
 		api.GET("/debug", s.debugEndpoint)
 		api.GET("/metrics", s.metricsEndpoint)
+		api.GET("/providers", s.adminAuth, s.listProviders)
+
+		admin := api.Group("/admin", s.adminAuth)
+		admin.GET("/subscriptions", s.listSubscriptions)
+		admin.POST("/tokens/cleanup", s.cleanupTokens)
+		admin.GET("/failed-emails", s.listFailedEmails)
+		admin.DELETE("/cache/weather", s.invalidateWeatherCache)
+		admin.GET("/stats", s.adminStats)
+		admin.GET("/subscriptions/export", s.exportSubscriptions)
+		admin.POST("/notifications/weather", s.triggerWeatherUpdate)
+		admin.GET("/email-preview", s.emailPreview)
 	}
 
 	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	s.router.GET("/health/live", s.livenessProbe)
+	s.router.GET("/health/ready", s.readinessProbe)
 
 	s.ServeStaticFiles()
 }
 
-// Start begins the HTTP server
+// Start begins the HTTP server and blocks until it stops. A server stopped
+// via Shutdown returns nil
 func (s *Server) Start() error {
-	return s.router.Run(fmt.Sprintf(":%d", s.config.Server.Port))
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Server.Port))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.httpServer = &http.Server{Handler: s.router}
+	s.listenAddr = listener.Addr().String()
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Addr returns the address the server is listening on, empty until started
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listenAddr
+}
+
+// Shutdown stops accepting new requests and waits for in-flight ones to
+// finish, up to the context deadline
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+	return httpServer.Shutdown(ctx)
 }
 
 // GetRouter returns the router for testing purposes
@@ -156,91 +339,291 @@ func (s *Server) GetRouter() *gin.Engine {
 }
 
 func (s *Server) getWeather(c *gin.Context) {
+	logger := requestLogger(c)
+	city := c.Query("city")
+	if city == "" {
+		s.handleError(c, weathererr.NewValidationError("city parameter is required"))
+		return
+	}
+
+	units, ok := models.ParseUnits(c.Query("units"))
+	if !ok {
+		s.handleError(c, weathererr.NewValidationError("units must be either 'metric' or 'imperial'"))
+		return
+	}
+
+	if s.config.Weather.RestrictToSubscribed {
+		allowed, err := s.cityAllowed(city)
+		if err != nil {
+			logger.Error("City access check error", "error", err, "city", city)
+			s.handleError(c, err)
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "weather queries are restricted to subscribed cities"})
+			return
+		}
+	}
+
+	logger.Debug("Getting weather for city", "city", city, "units", units)
+	var weather *models.WeatherResponse
+	var err error
+	if providerName := c.Query("provider"); providerName != "" {
+		// Debugging aid: pin the lookup to one provider instead of the chain
+		selector, ok := s.providerManager.(ProviderSelector)
+		if !ok {
+			s.handleError(c, weathererr.NewValidationError("provider selection is not supported"))
+			return
+		}
+		weather, err = selector.GetWeatherFrom(c.Request.Context(), providerName, city)
+	} else {
+		weather, err = s.weatherService.GetWeather(c.Request.Context(), city)
+	}
+	if err != nil {
+		logger.Error("Weather service error", "error", err, "city", city)
+		s.handleError(c, err)
+		return
+	}
+
+	logger.Debug("Weather result", "weather", weather, "city", city)
+
+	// Weak ETag lets polling clients skip re-downloading unchanged weather
+	body, err := json.Marshal(weather.InUnits(units))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	// Successful responses may be cached downstream for as long as our own
+	// provider cache would serve them; errors stay uncacheable. Degraded
+	// responses carry a staleness marker instead and must not be re-cached
+	if weather.Stale {
+		c.Header("X-Weather-Stale", "true")
+		c.Header("Cache-Control", "no-store")
+	} else if maxAge := s.config.Weather.CacheTTLMinutes * 60; maxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	}
+
+	etag := weakETag(body)
+	c.Header("ETag", etag)
+	if ifNoneMatchSatisfied(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// weakETag derives a weak validator from a response body; identical bodies
+// produce identical tags
+func weakETag(body []byte) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write(body)
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64())
+}
+
+// ifNoneMatchSatisfied reports whether an If-None-Match header matches the
+// given ETag, using weak comparison
+func ifNoneMatchSatisfied(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag || candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cityAllowed checks whether the city has a confirmed subscription; servers
+// without a city access checker stay open
+func (s *Server) cityAllowed(city string) (bool, error) {
+	if s.cityAccess == nil {
+		return true, nil
+	}
+	return s.cityAccess.HasConfirmedSubscriptionForCity(city)
+}
+
+func (s *Server) getForecast(c *gin.Context) {
+	logger := requestLogger(c)
+	if !s.featureFlags.ForecastEnabled() {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "forecast is not enabled"})
+		return
+	}
+
 	city := c.Query("city")
 	if city == "" {
 		s.handleError(c, weathererr.NewValidationError("city parameter is required"))
 		return
 	}
 
-	slog.Debug("Getting weather for city", "city", city)
-	weather, err := s.weatherService.GetWeather(city)
+	days, err := strconv.Atoi(c.DefaultQuery("days", "3"))
 	if err != nil {
-		slog.Error("Weather service error", "error", err, "city", city)
+		s.handleError(c, weathererr.NewValidationError("days parameter must be a number"))
+		return
+	}
+
+	logger.Debug("Getting forecast for city", "city", city, "days", days)
+	forecast, err := s.weatherService.GetForecast(city, days)
+	if err != nil {
+		logger.Error("Forecast service error", "error", err, "city", city)
 		s.handleError(c, err)
 		return
 	}
 
-	slog.Debug("Weather result", "weather", weather, "city", city)
-	c.JSON(http.StatusOK, weather)
+	c.JSON(http.StatusOK, forecast)
 }
 
 func (s *Server) subscribe(c *gin.Context) {
+	logger := requestLogger(c)
 	var req models.SubscriptionRequest
-	slog.Debug("Handling subscription request")
+	logger.Debug("Handling subscription request")
 
 	if err := c.ShouldBind(&req); err != nil {
-		slog.Error("Request binding error", "error", err)
+		logger.Error("Request binding error", "error", err)
 		s.handleError(c, weathererr.NewValidationError("invalid request format"))
 		return
 	}
 
-	slog.Debug("Subscription request received", "email", req.Email, "city", req.City, "frequency", req.Frequency)
+	logger.Debug("Subscription request received", "email", req.Email, "city", req.City, "frequency", req.Frequency)
+
+	idempotencyKey := c.GetHeader(idempotencyKeyHeader)
+	if s.replaySubscribe(c, idempotencyKey, &req) {
+		return
+	}
 
-	if err := s.subscriptionService.Subscribe(&req); err != nil {
-		slog.Error("Subscription error", "error", err, "email", req.Email, "city", req.City)
+	subscription, err := s.subscriptionService.Subscribe(&req)
+	if err != nil {
+		logger.Error("Subscription error", "error", err, "email", req.Email, "city", req.City)
 		s.handleError(c, err)
 		return
 	}
 
-	slog.Debug("Subscription created successfully", "email", req.Email, "city", req.City)
-	c.JSON(http.StatusOK, gin.H{"message": "Subscription successful. Confirmation email sent."})
+	logger.Debug("Subscription created successfully", "email", req.Email, "city", req.City)
+	response := models.SubscribeResponse{
+		Message:             "Subscription successful. Confirmation email sent.",
+		City:                subscription.City,
+		Frequency:           subscription.Frequency,
+		ConfirmationPending: !subscription.Confirmed,
+	}
+	s.recordSubscribeResponse(c, idempotencyKey, &req, response)
+	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) confirmSubscription(c *gin.Context) {
+	logger := requestLogger(c)
 	token := c.Param("token")
 	if token == "" {
 		s.handleError(c, weathererr.NewValidationError("token parameter is required"))
 		return
 	}
 
-	slog.Debug("Confirming subscription", "token", token)
+	logger.Debug("Confirming subscription", "token", token)
 
 	if err := s.subscriptionService.ConfirmSubscription(token); err != nil {
-		slog.Error("Confirmation error", "error", err, "token", token)
+		logger.Error("Confirmation error", "error", err, "token", token)
 		s.handleError(c, err)
 		return
 	}
 
-	slog.Debug("Subscription confirmed successfully", "token", token)
+	logger.Debug("Subscription confirmed successfully", "token", token)
 	c.JSON(http.StatusOK, gin.H{"message": "Subscription confirmed successfully"})
 }
 
 func (s *Server) unsubscribe(c *gin.Context) {
+	logger := requestLogger(c)
 	token := c.Param("token")
 	if token == "" {
 		s.handleError(c, weathererr.NewValidationError("token parameter is required"))
 		return
 	}
 
-	slog.Debug("Unsubscribing", "token", token)
+	logger.Debug("Unsubscribing", "token", token)
 
 	if err := s.subscriptionService.Unsubscribe(token); err != nil {
-		slog.Error("Unsubscribe error", "error", err, "token", token)
+		logger.Error("Unsubscribe error", "error", err, "token", token)
 		s.handleError(c, err)
 		return
 	}
 
-	slog.Debug("Unsubscribed successfully", "token", token)
+	logger.Debug("Unsubscribed successfully", "token", token)
 	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
 }
 
+// requestUnsubscribeAll sends a confirmation link that removes every
+// subscription for an email. It always responds 200 with the same message so
+// callers cannot probe which addresses are subscribed
+func (s *Server) requestUnsubscribeAll(c *gin.Context) {
+	logger := requestLogger(c)
+	var req models.UnsubscribeAllRequest
+	if err := c.ShouldBind(&req); err != nil {
+		logger.Error("Request binding error", "error", err)
+		s.handleError(c, weathererr.NewValidationError("invalid request format"))
+		return
+	}
+
+	logger.Debug("Unsubscribe-all requested", "email", req.Email)
+
+	if err := s.subscriptionService.RequestUnsubscribeAll(req.Email); err != nil {
+		// Deliberately swallowed: a failure here only happens when the email
+		// has subscriptions, so surfacing it would leak existence
+		logger.Error("Unsubscribe-all request error", "error", err, "email", req.Email)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If that email has subscriptions, a confirmation link has been sent."})
+}
+
+func (s *Server) unsubscribeAll(c *gin.Context) {
+	logger := requestLogger(c)
+	token := c.Param("token")
+	if token == "" {
+		s.handleError(c, weathererr.NewValidationError("token parameter is required"))
+		return
+	}
+
+	logger.Debug("Unsubscribing from all", "token", token)
+
+	if err := s.subscriptionService.UnsubscribeAll(token); err != nil {
+		logger.Error("Unsubscribe-all error", "error", err, "token", token)
+		s.handleError(c, err)
+		return
+	}
+
+	logger.Debug("Unsubscribed from all successfully", "token", token)
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed from all weather updates"})
+}
+
+func (s *Server) cancelSubscription(c *gin.Context) {
+	logger := requestLogger(c)
+	token := c.Param("token")
+	if token == "" {
+		s.handleError(c, weathererr.NewValidationError("token parameter is required"))
+		return
+	}
+
+	logger.Debug("Cancelling pending subscription", "token", token)
+
+	if err := s.subscriptionService.CancelSubscription(token); err != nil {
+		logger.Error("Cancel error", "error", err, "token", token)
+		s.handleError(c, err)
+		return
+	}
+
+	logger.Debug("Pending subscription cancelled successfully", "token", token)
+	c.JSON(http.StatusOK, gin.H{"message": "Signup cancelled successfully"})
+}
+
 func (s *Server) debugEndpoint(c *gin.Context) {
-	slog.Debug("Debug endpoint called")
+	logger := requestLogger(c)
+	logger.Debug("Debug endpoint called")
 
 	var subscriptionCount int64
 	dbErr := s.db.Model(&models.Subscription{}).Count(&subscriptionCount).Error
 
-	weatherResponse, weatherErr := s.weatherService.GetWeather("London")
+	weatherResponse, weatherErr := s.weatherService.GetWeather(c.Request.Context(), "London")
 
 	smtpConfig := map[string]string{
 		"host":        s.config.Email.SMTPHost,
@@ -265,17 +648,19 @@ func (s *Server) debugEndpoint(c *gin.Context) {
 		"config": map[string]string{
 			"appBaseURL": s.config.AppBaseURL,
 		},
+		"features": s.featureFlags.States(),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) metricsEndpoint(c *gin.Context) {
-	slog.Debug("Metrics endpoint called")
+	logger := requestLogger(c)
+	logger.Debug("Metrics endpoint called")
 
 	cacheMetrics, err := s.providerMetrics.GetCacheMetrics()
 	if err != nil {
-		slog.Error("Error getting cache metrics", "error", err)
+		logger.Error("Error getting cache metrics", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "cache metrics unavailable"})
 		return
 	}
@@ -285,12 +670,19 @@ func (s *Server) metricsEndpoint(c *gin.Context) {
 	response := gin.H{
 		"cache":         cacheMetrics,
 		"provider_info": providerInfo,
+		"subscriptions": metrics.Subscriptions().GetStats(),
 		"endpoints": gin.H{
 			"prometheus_metrics": "/metrics",
 			"cache_metrics":      "/api/metrics",
 		},
 	}
 
+	if reader, ok := s.providerMetrics.(CachePoolStatsReader); ok {
+		if poolStats, available := reader.GetCachePoolStats(); available {
+			response["cache_pool"] = poolStats
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -300,6 +692,13 @@ func (s *Server) handleError(c *gin.Context, err error) {
 	var statusCode int
 	var message string
 
+	// A request that outlives its per-route deadline surfaces as a wrapped
+	// context.DeadlineExceeded from context-aware handlers and providers
+	if errors.Is(err, context.DeadlineExceeded) {
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, models.ErrorResponse{Error: "request timed out"})
+		return
+	}
+
 	if !errors.As(err, &appErr) {
 		statusCode = http.StatusInternalServerError
 		message = "Internal server error"
@@ -317,9 +716,20 @@ func (s *Server) handleError(c *gin.Context, err error) {
 	case weathererr.AlreadyExistsError:
 		statusCode = http.StatusConflict
 		message = appErr.Message
+	case weathererr.ConflictError:
+		statusCode = http.StatusConflict
+		message = appErr.Message
 	case weathererr.ExternalAPIError:
 		statusCode = http.StatusServiceUnavailable
 		message = "External service unavailable"
+	case weathererr.RateLimitError:
+		statusCode = http.StatusServiceUnavailable
+		message = "External service unavailable"
+		if appErr.RetryAfter > 0 {
+			// Round up so clients never retry inside the window
+			seconds := (appErr.RetryAfter + time.Second - 1) / time.Second
+			c.Header("Retry-After", strconv.FormatInt(int64(seconds), 10))
+		}
 	case weathererr.DatabaseError:
 		statusCode = http.StatusInternalServerError
 		message = "Internal server error"
@@ -334,5 +744,10 @@ func (s *Server) handleError(c *gin.Context, err error) {
 		message = "Internal server error"
 	}
 
-	c.JSON(statusCode, models.ErrorResponse{Error: message})
+	response := models.ErrorResponse{Error: message}
+	for _, field := range appErr.Fields {
+		response.Details = append(response.Details, models.ErrorDetail{Field: field.Field, Code: field.Code})
+	}
+
+	c.JSON(statusCode, response)
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,6 +16,8 @@ import (
 	"weatherapi.app/errors"
 	"weatherapi.app/metrics"
 	"weatherapi.app/models"
+	"weatherapi.app/providers/cache"
+	"weatherapi.app/service"
 )
 
 // MockWeatherService for testing
@@ -22,7 +25,7 @@ type MockWeatherService struct {
 	mock.Mock
 }
 
-func (m *MockWeatherService) GetWeather(city string) (*models.WeatherResponse, error) {
+func (m *MockWeatherService) GetWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
 	args := m.Called(city)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,14 +33,25 @@ func (m *MockWeatherService) GetWeather(city string) (*models.WeatherResponse, e
 	return args.Get(0).(*models.WeatherResponse), args.Error(1)
 }
 
+func (m *MockWeatherService) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	args := m.Called(city, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResponse), args.Error(1)
+}
+
 // MockSubscriptionService for testing
 type MockSubscriptionService struct {
 	mock.Mock
 }
 
-func (m *MockSubscriptionService) Subscribe(req *models.SubscriptionRequest) error {
+func (m *MockSubscriptionService) Subscribe(req *models.SubscriptionRequest) (*models.Subscription, error) {
 	args := m.Called(req)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Subscription), args.Error(1)
 }
 
 func (m *MockSubscriptionService) ConfirmSubscription(token string) error {
@@ -50,17 +64,35 @@ func (m *MockSubscriptionService) Unsubscribe(token string) error {
 	return args.Error(0)
 }
 
-func (m *MockSubscriptionService) SendWeatherUpdate(frequency string) error {
-	args := m.Called(frequency)
+func (m *MockSubscriptionService) CancelSubscription(token string) error {
+	args := m.Called(token)
 	return args.Error(0)
 }
 
+func (m *MockSubscriptionService) RequestUnsubscribeAll(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionService) UnsubscribeAll(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionService) SendWeatherUpdate(frequency string) (*service.WeatherUpdateResult, error) {
+	args := m.Called(frequency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.WeatherUpdateResult), args.Error(1)
+}
+
 // MockProviderManager for testing
 type MockProviderManager struct {
 	mock.Mock
 }
 
-func (m *MockProviderManager) GetWeather(city string) (*models.WeatherResponse, error) {
+func (m *MockProviderManager) GetWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
 	args := m.Called(city)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -68,6 +100,14 @@ func (m *MockProviderManager) GetWeather(city string) (*models.WeatherResponse,
 	return args.Get(0).(*models.WeatherResponse), args.Error(1)
 }
 
+func (m *MockProviderManager) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	args := m.Called(city, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResponse), args.Error(1)
+}
+
 // MockProviderMetricsService for testing
 type MockProviderMetricsService struct {
 	mock.Mock
@@ -83,6 +123,16 @@ func (m *MockProviderMetricsService) GetCacheMetrics() (metrics.CacheStats, erro
 	return args.Get(0).(metrics.CacheStats), args.Error(1)
 }
 
+// MockCityAccess for testing restricted weather queries
+type MockCityAccess struct {
+	mock.Mock
+}
+
+func (m *MockCityAccess) HasConfirmedSubscriptionForCity(city string) (bool, error) {
+	args := m.Called(city)
+	return args.Bool(0), args.Error(1)
+}
+
 // TestServerSetup contains all the components needed for testing
 type TestServerSetup struct {
 	Router              *gin.Engine
@@ -187,6 +237,193 @@ func TestGetWeather_MissingCity(t *testing.T) {
 	assert.Equal(t, "city parameter is required", errorResponse.Error)
 }
 
+func TestGetWeather_ImperialUnits(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{
+		Temperature: 20.0,
+		Humidity:    76.0,
+		Description: "Partly cloudy",
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London&units=imperial", nil)
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.WeatherResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 68.0, response.Temperature)
+	assert.Equal(t, "imperial", response.Unit)
+
+	setup.MockWeather.AssertExpectations(t)
+}
+
+func TestGetWeather_InvalidUnits(t *testing.T) {
+	setup := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London&units=kelvin", nil)
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResponse models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Contains(t, errorResponse.Error, "units")
+}
+
+// setupRestrictedTestServer builds a server that only serves subscribed cities
+func setupRestrictedTestServer() (*gin.Engine, *MockWeatherService, *MockCityAccess) {
+	gin.SetMode(gin.TestMode)
+
+	mockWeather := new(MockWeatherService)
+	mockCityAccess := new(MockCityAccess)
+
+	server, err := NewServer(ServerOptions{
+		Config: &config.Config{
+			AppBaseURL: "http://localhost:8080",
+			Weather:    config.WeatherConfig{RestrictToSubscribed: true},
+		},
+		WeatherService:      mockWeather,
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+		CityAccess:          mockCityAccess,
+	})
+	if err != nil {
+		panic("Failed to create test server: " + err.Error())
+	}
+
+	return server.GetRouter(), mockWeather, mockCityAccess
+}
+
+func TestGetWeather_RestrictedCityForbidden(t *testing.T) {
+	router, _, mockCityAccess := setupRestrictedTestServer()
+
+	mockCityAccess.On("HasConfirmedSubscriptionForCity", "London").Return(false, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var errorResponse models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Contains(t, errorResponse.Error, "restricted to subscribed cities")
+}
+
+func TestGetWeather_RestrictedCityAllowed(t *testing.T) {
+	router, mockWeather, mockCityAccess := setupRestrictedTestServer()
+
+	mockCityAccess.On("HasConfirmedSubscriptionForCity", "London").Return(true, nil)
+	mockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetWeather_ETagRoundTrip(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{
+		Temperature: 15.0,
+		Humidity:    76.0,
+		Description: "Partly cloudy",
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	w := httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), "expected weak ETag, got %q", etag)
+
+	// An unchanged response is answered with 304 and no body
+	req = httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+}
+
+func TestGetWeather_CacheControlHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockWeather := new(MockWeatherService)
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Weather.CacheTTLMinutes = 10
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      mockWeather,
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	if err != nil {
+		panic("Failed to create test server: " + err.Error())
+	}
+	router := server.GetRouter()
+
+	mockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil).Once()
+	mockWeather.On("GetWeather", "Atlantis").Return(nil, errors.NewNotFoundError("city not found")).Once()
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=600", w.Header().Get("Cache-Control"))
+
+	// Error responses carry no caching header
+	req = httptest.NewRequest("GET", "/api/weather?city=Atlantis", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestGetWeather_ETagChangesWithWeather(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil).Once()
+	setup.MockWeather.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 16.5}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	w := httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	firstETag := w.Header().Get("ETag")
+
+	// The weather changed, so the stale validator no longer matches
+	req = httptest.NewRequest("GET", "/api/weather?city=London", nil)
+	req.Header.Set("If-None-Match", firstETag)
+	w = httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, firstETag, w.Header().Get("ETag"))
+	assert.NotEmpty(t, w.Body.String())
+}
+
 func TestGetWeather_ExternalAPIError(t *testing.T) {
 	setup := setupTestServer()
 
@@ -207,10 +444,94 @@ func TestGetWeather_ExternalAPIError(t *testing.T) {
 	setup.MockWeather.AssertExpectations(t)
 }
 
+// MockProviderSelector is a provider manager that also supports pinning a
+// lookup to one named provider
+type MockProviderSelector struct {
+	MockProviderManager
+}
+
+func (m *MockProviderSelector) GetWeatherFrom(_ context.Context, providerName, city string) (*models.WeatherResponse, error) {
+	args := m.Called(providerName, city)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WeatherResponse), args.Error(1)
+}
+
+func setupProviderSelectorTestServer() (*gin.Engine, *MockProviderSelector) {
+	gin.SetMode(gin.TestMode)
+
+	selector := new(MockProviderSelector)
+
+	server, err := NewServer(ServerOptions{
+		Config:              &config.Config{AppBaseURL: "http://localhost:8080"},
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     selector,
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	if err != nil {
+		panic("Failed to create test server: " + err.Error())
+	}
+
+	return server.GetRouter(), selector
+}
+
+func TestGetWeather_ProviderOverride(t *testing.T) {
+	router, selector := setupProviderSelectorTestServer()
+
+	selector.On("GetWeatherFrom", "weatherapi", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London&provider=weatherapi", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.WeatherResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 15.0, response.Temperature)
+
+	selector.AssertExpectations(t)
+}
+
+func TestGetWeather_ProviderOverrideUnknownProvider(t *testing.T) {
+	router, selector := setupProviderSelectorTestServer()
+
+	selector.On("GetWeatherFrom", "bogus", "London").Return(nil, errors.NewValidationError("unknown weather provider: bogus"))
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London&provider=bogus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResponse models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown weather provider: bogus", errorResponse.Error)
+}
+
+func TestGetWeather_ProviderOverrideUnsupported(t *testing.T) {
+	// The plain mock manager does not implement ProviderSelector
+	setup := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/api/weather?city=London&provider=weatherapi", nil)
+	w := httptest.NewRecorder()
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestSubscribe_Success(t *testing.T) {
 	setup := setupTestServer()
 
-	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(nil)
+	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(&models.Subscription{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+	}, nil)
 
 	formData := "email=test%40example.com&city=London&frequency=daily"
 	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
@@ -221,11 +542,13 @@ func TestSubscribe_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response map[string]string
+	var response models.SubscribeResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response, "message")
-	assert.Contains(t, response["message"], "Subscription successful")
+	assert.Contains(t, response.Message, "Subscription successful")
+	assert.Equal(t, "London", response.City)
+	assert.Equal(t, "daily", response.Frequency)
+	assert.True(t, response.ConfirmationPending)
 
 	setup.MockSubscription.AssertExpectations(t)
 }
@@ -233,7 +556,7 @@ func TestSubscribe_Success(t *testing.T) {
 func TestSubscribe_AlreadySubscribed(t *testing.T) {
 	setup := setupTestServer()
 
-	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(errors.NewAlreadyExistsError("email already subscribed"))
+	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(nil, errors.NewAlreadyExistsError("email already subscribed"))
 
 	formData := "email=test%40example.com&city=London&frequency=daily"
 	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
@@ -255,7 +578,7 @@ func TestSubscribe_AlreadySubscribed(t *testing.T) {
 func TestSubscribe_ServiceValidationError(t *testing.T) {
 	setup := setupTestServer()
 
-	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(errors.NewValidationError("city not supported"))
+	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(nil, errors.NewValidationError("city not supported"))
 
 	formData := "email=test%40example.com&city=London&frequency=daily" // Valid form data
 	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
@@ -274,6 +597,30 @@ func TestSubscribe_ServiceValidationError(t *testing.T) {
 	setup.MockSubscription.AssertExpectations(t)
 }
 
+func TestSubscribe_FieldValidationError(t *testing.T) {
+	setup := setupTestServer()
+
+	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).
+		Return(nil, errors.NewFieldValidationError("frequency must be 'hourly', 'daily', or 'weekly'", "frequency", "invalid_value"))
+
+	formData := "email=test%40example.com&city=London&frequency=sometimes"
+	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errorResponse models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "frequency must be 'hourly', 'daily', or 'weekly'", errorResponse.Error)
+	assert.Equal(t, []models.ErrorDetail{{Field: "frequency", Code: "invalid_value"}}, errorResponse.Details)
+
+	setup.MockSubscription.AssertExpectations(t)
+}
+
 func TestSubscribe_BindingValidationError(t *testing.T) {
 	setup := setupTestServer()
 
@@ -297,7 +644,7 @@ func TestSubscribe_BindingValidationError(t *testing.T) {
 func TestSubscribe_EmailError(t *testing.T) {
 	setup := setupTestServer()
 
-	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(errors.NewEmailError("failed to send email", nil))
+	setup.MockSubscription.On("Subscribe", mock.AnythingOfType("*models.SubscriptionRequest")).Return(nil, errors.NewEmailError("failed to send email", nil))
 
 	formData := "email=test%40example.com&city=London&frequency=daily"
 	req := httptest.NewRequest("POST", "/api/subscribe", strings.NewReader(formData))
@@ -444,6 +791,67 @@ func TestUnsubscribe_NotFound(t *testing.T) {
 	setup.MockSubscription.AssertExpectations(t)
 }
 
+func TestRequestUnsubscribeAll_AlwaysOK(t *testing.T) {
+	setup := setupTestServer()
+
+	// Even when the service fails the handler must answer 200 so callers
+	// cannot probe which addresses are subscribed
+	setup.MockSubscription.On("RequestUnsubscribeAll", "test@example.com").Return(errors.NewEmailError("smtp down", nil))
+
+	formData := "email=test%40example.com"
+	req := httptest.NewRequest("POST", "/api/unsubscribe-all", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response["message"], "confirmation link")
+
+	setup.MockSubscription.AssertExpectations(t)
+}
+
+func TestRequestUnsubscribeAll_InvalidEmail(t *testing.T) {
+	setup := setupTestServer()
+
+	// No mock expectation because the service should NOT be called when binding fails
+
+	formData := "email=not-an-email"
+	req := httptest.NewRequest("POST", "/api/unsubscribe-all", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	setup.MockSubscription.AssertNotCalled(t, "RequestUnsubscribeAll", mock.Anything)
+}
+
+func TestUnsubscribeAll_Success(t *testing.T) {
+	setup := setupTestServer()
+
+	token := "valid-unsubscribe-all-token"
+	setup.MockSubscription.On("UnsubscribeAll", token).Return(nil)
+
+	req := httptest.NewRequest("GET", "/api/unsubscribe-all/"+token, nil)
+	w := httptest.NewRecorder()
+
+	setup.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response["message"], "Unsubscribed from all")
+
+	setup.MockSubscription.AssertExpectations(t)
+}
+
 // Test validation for empty token parameter
 func TestConfirmSubscription_EmptyToken(t *testing.T) {
 	setup := setupTestServer()
@@ -635,6 +1043,51 @@ func TestMetricsEndpoint_CacheError(t *testing.T) {
 	setup.MockProviderMetrics.AssertExpectations(t)
 }
 
+// MockPoolStatsMetrics extends the provider metrics mock with Redis
+// connection pool counters
+type MockPoolStatsMetrics struct {
+	MockProviderMetricsService
+}
+
+func (m *MockPoolStatsMetrics) GetCachePoolStats() (cache.PoolStats, bool) {
+	args := m.Called()
+	return args.Get(0).(cache.PoolStats), args.Bool(1)
+}
+
+func TestMetricsEndpoint_IncludesPoolStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	providerMetrics := new(MockPoolStatsMetrics)
+	providerMetrics.On("GetCacheMetrics").Return(metrics.CacheStats{CacheType: "redis"}, nil)
+	providerMetrics.On("GetProviderInfo").Return(map[string]interface{}{"cache_enabled": true})
+	providerMetrics.On("GetCachePoolStats").Return(cache.PoolStats{TotalConns: 5, IdleConns: 3}, true)
+
+	server, err := NewServer(ServerOptions{
+		Config:              &config.Config{AppBaseURL: "http://localhost:8080"},
+		WeatherService:      new(MockWeatherService),
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     providerMetrics,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/api/metrics", nil)
+	w := httptest.NewRecorder()
+	server.GetRouter().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	poolStats, ok := response["cache_pool"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(5), poolStats["total_conns"])
+	assert.Equal(t, float64(3), poolStats["idle_conns"])
+
+	providerMetrics.AssertExpectations(t)
+}
+
 func TestUnsubscribe_EmptyToken(t *testing.T) {
 	setup := setupTestServer()
 
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/models"
+)
+
+func TestServer_ShutdownDrainsInFlightRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	release := make(chan struct{})
+	mockWeather := new(MockWeatherService)
+	mockWeather.On("GetWeather", "London").
+		Run(func(mock.Arguments) { <-release }).
+		Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Server.Port = 0 // pick a free port
+
+	server, err := NewServer(ServerOptions{
+		Config:              cfg,
+		WeatherService:      mockWeather,
+		SubscriptionService: new(MockSubscriptionService),
+		ProviderManager:     new(MockProviderManager),
+		ProviderMetrics:     new(MockProviderMetricsService),
+	})
+	require.NoError(t, err)
+
+	startErr := make(chan error, 1)
+	go func() { startErr <- server.Start() }()
+
+	require.Eventually(t, func() bool { return server.Addr() != "" }, 2*time.Second, 5*time.Millisecond)
+
+	_, port, err := net.SplitHostPort(server.Addr())
+	require.NoError(t, err)
+	baseURL := "http://" + net.JoinHostPort("127.0.0.1", port)
+
+	// Issue a request that blocks inside the handler
+	statusCh := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/api/weather?city=London")
+		if err != nil {
+			statusCh <- 0
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		statusCh <- resp.StatusCode
+	}()
+
+	// Give the request time to reach the blocked handler, then shut down
+	time.Sleep(100 * time.Millisecond)
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- server.Shutdown(ctx)
+	}()
+
+	// Unblock the handler while shutdown is draining
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, http.StatusOK, <-statusCh)
+	assert.NoError(t, <-shutdownErr)
+	assert.NoError(t, <-startErr)
+}
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,14 +15,20 @@ import (
 	"weatherapi.app/repository"
 	"weatherapi.app/scheduler"
 	"weatherapi.app/service"
+	"weatherapi.app/tracing"
 )
 
 // Application represents the main application with all its dependencies
 type Application struct {
-	config    *config.Config
-	db        *gorm.DB
-	server    *api.Server
-	scheduler *scheduler.Scheduler
+	config           *config.Config
+	db               *gorm.DB
+	replicaDB        *gorm.DB
+	server           *api.Server
+	scheduler        *scheduler.Scheduler
+	emailProvider    *providers.SMTPEmailProvider
+	emailQueue       *service.EmailQueue
+	providerManager  *providers.ProviderManager
+	subscriptionRepo *repository.SubscriptionRepository
 }
 
 // NewApplication creates and initializes a new application instance
@@ -31,6 +39,10 @@ func NewApplication() (*Application, error) {
 		return nil, err
 	}
 
+	// Configure the global logger before any other component logs so every
+	// line comes out in the configured format and level
+	app.configureLogging()
+
 	if err := app.initializeDatabase(); err != nil {
 		return nil, err
 	}
@@ -56,6 +68,22 @@ func (app *Application) loadConfiguration() error {
 	return nil
 }
 
+// configureLogging installs the global slog handler according to LOG_FORMAT
+// and LOG_LEVEL; JSON output suits production log aggregation while text
+// stays readable in local development
+func (app *Application) configureLogging() {
+	opts := &slog.HandlerOptions{Level: app.config.Logging.SlogLevel()}
+
+	var handler slog.Handler
+	if app.config.Logging.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
 func (app *Application) initializeDatabase() error {
 	slog.Info("Initializing database...")
 
@@ -71,6 +99,18 @@ func (app *Application) initializeDatabase() error {
 	}
 
 	app.db = db
+
+	// Optional read replica; nil keeps every query on the primary
+	replicaDB, err := database.InitReplicaDB(app.config.Database)
+	if err != nil {
+		slog.Error("Failed to initialize read replica", "error", err)
+		return fmt.Errorf("initialize read replica connection: %w", err)
+	}
+	if replicaDB != nil {
+		app.replicaDB = replicaDB
+		slog.Info("Read replica initialized", "host", app.config.Database.ReplicaHost)
+	}
+
 	slog.Info("Database initialized successfully")
 	return nil
 }
@@ -78,44 +118,91 @@ func (app *Application) initializeDatabase() error {
 func (app *Application) initializeServices() error {
 	slog.Info("Initializing services...")
 
+	// Optional tracing: spans around weather lookups and database operations
+	tracer, err := app.initializeTracing()
+	if err != nil {
+		return fmt.Errorf("initialize tracing: %w", err)
+	}
+
 	// Create provider manager with all patterns
-	providerManager, err := app.createProviderManager()
+	providerManager, err := app.createProviderManager(tracer)
 	if err != nil {
 		return fmt.Errorf("create provider manager: %w", err)
 	}
 
 	// Create email provider
-	emailProvider := providers.NewSMTPEmailProvider(&app.config.Email)
+	emailProvider, err := providers.NewSMTPEmailProvider(&app.config.Email)
+	if err != nil {
+		return fmt.Errorf("create email provider: %w", err)
+	}
+	app.emailProvider = emailProvider
 
-	// Create services
+	// Create services; observation storage is opt-in so the default path
+	// stays free of per-request writes
 	weatherService := service.NewWeatherService(providerManager)
-	emailService := service.NewEmailService(emailProvider)
+	var observationRepo *repository.ObservationRepository
+	if app.config.Weather.StoreHistory {
+		observationRepo = repository.NewObservationRepository(app.db)
+		weatherService = service.NewWeatherServiceWithObservations(providerManager, observationRepo)
+	}
+	weatherService = weatherService.WithCityMaxLength(app.config.Weather.CityMaxLength)
+	templateRenderer, err := service.NewTemplateRenderer(app.config.Email.TemplatesDir)
+	if err != nil {
+		return fmt.Errorf("load email templates: %w", err)
+	}
+	emailService := service.NewEmailServiceWithRenderer(emailProvider, templateRenderer)
+
+	// Weather update emails go through a worker queue so bulk runs are not
+	// serialized behind SMTP latency; drained on shutdown. Terminal failures
+	// are dead-lettered for operator inspection
+	failedEmailRepo := repository.NewFailedEmailRepository(app.db)
+	emailQueue := service.NewEmailQueueWithRecorder(emailService, failedEmailRepo, app.config.Email.Workers)
+	app.emailQueue = emailQueue
 
-	// Create repositories
+	// Create repositories; subscription reads go to the replica when one
+	// is configured
 	subscriptionRepo := repository.NewSubscriptionRepository(app.db)
+	if app.replicaDB != nil {
+		subscriptionRepo = repository.NewSubscriptionRepositoryWithReplica(app.db, app.replicaDB)
+	}
 	tokenRepo := repository.NewTokenRepository(app.db)
 
+	// Kept for startup tasks like cache warm-up
+	app.providerManager = providerManager
+	app.subscriptionRepo = subscriptionRepo
+
 	// Create subscription service
-	subscriptionService := service.NewSubscriptionService(
+	subscriptionService := service.NewSubscriptionServiceWithEmailQueue(
 		app.db,
 		subscriptionRepo,
 		tokenRepo,
 		emailService,
+		emailQueue,
 		weatherService,
 		app.config,
 	)
 
 	// Create server and scheduler
-	server, err := api.NewServer(
-		api.NewServerOptionsBuilder().
-			WithDB(app.db).
-			WithConfig(app.config).
-			WithWeatherService(weatherService).
-			WithSubscriptionService(subscriptionService).
-			WithProviderManager(providerManager).
-			WithProviderMetrics(providerManager).
-			Build(),
-	)
+	serverOptions := api.NewServerOptionsBuilder().
+		WithDB(app.db).
+		WithConfig(app.config).
+		WithWeatherService(weatherService).
+		WithSubscriptionService(subscriptionService).
+		WithProviderManager(providerManager).
+		WithProviderMetrics(providerManager).
+		WithFeatureFlags(config.NewFeatureFlags(app.config.Features)).
+		WithCityAccess(subscriptionRepo).
+		WithCachePinger(providerManager).
+		WithSubscriptionLister(subscriptionRepo).
+		WithIdempotencyStore(repository.NewIdempotencyKeyRepository(app.db)).
+		WithTokenCleaner(tokenRepo).
+		WithFailedEmailLister(failedEmailRepo).
+		WithEmailPreviewer(emailService)
+	if observationRepo != nil {
+		serverOptions = serverOptions.WithWeatherHistoryReader(observationRepo)
+	}
+
+	server, err := api.NewServer(serverOptions.Build())
 	if err != nil {
 		return fmt.Errorf("create server: %w", err)
 	}
@@ -126,9 +213,25 @@ func (app *Application) initializeServices() error {
 	return nil
 }
 
+// initializeTracing returns the tracer to instrument services with, wiring
+// the database callbacks when tracing is enabled
+func (app *Application) initializeTracing() (tracing.Tracer, error) {
+	if !app.config.Tracing.Enabled {
+		return tracing.NewNoopTracer(), nil
+	}
+
+	tracer := tracing.NewSlogTracer()
+	if err := repository.RegisterTracing(app.db, tracer); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Tracing enabled")
+	return tracer, nil
+}
+
 // createProviderManager creates and configures the weather provider manager
 // Follows Factory Method pattern: creates complex configured object
-func (app *Application) createProviderManager() (*providers.ProviderManager, error) {
+func (app *Application) createProviderManager(tracer tracing.Tracer) (*providers.ProviderManager, error) {
 	slog.Debug("Creating weather provider manager...")
 
 	// Create provider manager using builder pattern
@@ -139,15 +242,55 @@ func (app *Application) createProviderManager() (*providers.ProviderManager, err
 		WithOpenWeatherMapBaseURL(app.config.Weather.OpenWeatherMapBaseURL).
 		WithAccuWeatherKey(app.config.Weather.AccuWeatherKey).
 		WithAccuWeatherBaseURL(app.config.Weather.AccuWeatherBaseURL).
+		WithAccuWeatherMockMode(app.config.Weather.AccuWeatherMockMode).
 		WithCacheType(providers.CacheTypeFromString(app.config.Cache.Type)).
 		WithCacheTTL(time.Duration(app.config.Weather.CacheTTLMinutes) * time.Minute).
 		WithLogFilePath(app.config.Weather.LogFilePath).
 		WithLoggingEnabled(app.config.Weather.EnableLogging).
-		WithProviderOrder(app.config.Weather.ProviderOrder)
+		WithLogLevel(app.config.Logging.SlogLevel()).
+		WithProviderOrder(app.config.Weather.ProviderOrder).
+		WithProviderTimeout(time.Duration(app.config.Weather.ProviderTimeoutSeconds) * time.Second).
+		WithUserAgent(app.config.Weather.UserAgent)
+
+	// A flat WEATHER_EXTRA_HEADERS map applies to every configured provider
+	if len(app.config.Weather.ExtraHeaders) > 0 {
+		for _, provider := range app.config.Weather.ProviderOrder {
+			builder = builder.WithProviderExtraHeaders(provider, app.config.Weather.ExtraHeaders)
+		}
+	}
+
+	if app.config.Tracing.Enabled {
+		builder = builder.WithTracer(tracer)
+	}
 
 	// Presence-based caching: only provide CacheConfig if caching is enabled
 	if app.config.Weather.EnableCache {
-		builder = builder.WithCacheConfig(&app.config.Cache)
+		builder = builder.WithCacheConfig(&app.config.Cache).
+			WithCacheMode(providers.CacheModeFromString(app.config.Weather.CacheMode)).
+			WithCacheMaxStaleness(time.Duration(app.config.Weather.CacheMaxStalenessSeconds) * time.Second).
+			WithServeStaleOnError(app.config.Weather.ServeStaleOnError).
+			WithMemoryCacheMaxEntries(app.config.Weather.MemoryCacheMaxEntries)
+
+		// Optional negative caching for bad-city lookups
+		if app.config.Weather.NegativeCacheTTLSeconds > 0 {
+			builder = builder.WithNegativeCacheTTL(time.Duration(app.config.Weather.NegativeCacheTTLSeconds) * time.Second)
+		}
+
+		// Optional distributed fetch locking for cross-replica stampede protection
+		if app.config.Weather.FetchLockTTLSeconds > 0 {
+			builder = builder.WithFetchLock(
+				time.Duration(app.config.Weather.FetchLockTTLSeconds)*time.Second,
+				time.Duration(app.config.Weather.FetchLockWaitMS)*time.Millisecond,
+			)
+		}
+	}
+
+	// Optional response transformation: only provide rules if configured
+	if app.config.Weather.TransformRoundPrecision >= 0 || app.config.Weather.TransformTempOffset != 0 {
+		builder = builder.WithTransformRules(&providers.TransformRules{
+			RoundPrecision:    app.config.Weather.TransformRoundPrecision,
+			TemperatureOffset: app.config.Weather.TransformTempOffset,
+		})
 	}
 
 	providerManager, err := builder.Build()
@@ -166,14 +309,63 @@ func (app *Application) Start() error {
 	slog.Info("Starting scheduler...")
 	go app.scheduler.Start()
 
+	// Warm the weather cache for subscribed cities without blocking startup
+	go app.warmCache()
+
 	slog.Info("Starting HTTP server", "port", app.config.Server.Port)
 	return app.server.Start()
 }
 
+// warmCache pre-fetches weather for every subscribed city so the first
+// request after startup is served from cache
+func (app *Application) warmCache() {
+	cities, err := app.subscriptionRepo.DistinctCities()
+	if err != nil {
+		slog.Warn("Cache warm-up skipped: list cities", "error", err)
+		return
+	}
+	if len(cities) == 0 {
+		return
+	}
+
+	if err := app.providerManager.WarmCache(context.Background(), cities); err != nil {
+		slog.Warn("Cache warm-up interrupted", "error", err)
+	}
+}
+
+// shutdownTimeout bounds how long shutdown waits for in-flight HTTP
+// requests to finish
+const shutdownTimeout = 30 * time.Second
+
 // Shutdown gracefully shuts down the application
 func (app *Application) Shutdown() error {
 	slog.Info("Shutting down application...")
 
+	// Stop accepting requests and drain in-flight ones before tearing down
+	// the resources they depend on
+	if app.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := app.server.Shutdown(ctx); err != nil {
+			slog.Warn("Error shutting down HTTP server", "error", err)
+		}
+	}
+
+	// Drain queued emails before the provider's connections go away
+	if app.emailQueue != nil {
+		app.emailQueue.Close()
+	}
+
+	if app.emailProvider != nil {
+		app.emailProvider.Close()
+	}
+
+	if app.replicaDB != nil {
+		if err := database.CloseDB(app.replicaDB); err != nil {
+			slog.Warn("Error closing read replica", "error", err)
+		}
+	}
+
 	if app.db != nil {
 		if err := database.CloseDB(app.db); err != nil {
 			slog.Warn("Error closing database", "error", err)
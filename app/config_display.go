@@ -42,6 +42,7 @@ func (cd *ConfigDisplayer) PrintConfig(cfg *config.Config) {
 		"email_from_address", cfg.Email.FromAddress,
 		"scheduler_hourly_interval", cfg.Scheduler.HourlyInterval,
 		"scheduler_daily_interval", cfg.Scheduler.DailyInterval,
+		"scheduler_weekly_interval", cfg.Scheduler.WeeklyInterval,
 		"app_base_url", cfg.AppBaseURL,
 	)
 }
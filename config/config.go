@@ -1,8 +1,15 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"weatherapi.app/errors"
@@ -12,7 +19,19 @@ const (
 	maxRedisDB         = 15
 	maxCacheTTLMinutes = 1440
 	maxDailyInterval   = 10080
-	maxPortNumber      = 65535
+
+	// maxJitterPercent caps run-time jitter at ±50% so a jittered delay can
+	// never reach zero
+	maxJitterPercent  = 50
+	maxPortNumber     = 65535
+	maxRoundPrecision = 6
+
+	minProviderTimeoutSeconds = 1
+	maxProviderTimeoutSeconds = 120
+	maxBatchConcurrency       = 50
+
+	defaultConfirmationTokenTTLHours = 24
+	maxConfirmationTokenTTLHours     = 168
 )
 
 // Config represents the application configuration structure
@@ -23,35 +42,113 @@ type Config struct {
 	Email      EmailConfig     `split_words:"true"`
 	Scheduler  SchedulerConfig `split_words:"true"`
 	Cache      CacheConfig     `split_words:"true"`
+	Features   FeaturesConfig  `split_words:"true"`
+	Tracing    TracingConfig   `split_words:"true"`
+	Logging    LoggingConfig   `split_words:"true"`
 	AppBaseURL string          `envconfig:"APP_URL" default:"http://localhost:8080"`
+
+	// ConfirmationTokenTTLHours is how long confirmation (and cancel) tokens
+	// stay valid; the unconfirmed-subscription cleanup uses the same window
+	// so signups are not purged while their token could still be used
+	ConfirmationTokenTTLHours int `envconfig:"CONFIRMATION_TOKEN_TTL_HOURS" default:"24"`
+
+	// MaxSubscriptionsPerEmail caps how many city subscriptions a single
+	// email address can hold, as an abuse guard; zero disables the cap
+	MaxSubscriptionsPerEmail int `envconfig:"MAX_SUBSCRIPTIONS_PER_EMAIL" default:"10"`
 }
 
 // ServerConfig contains HTTP server configuration
 type ServerConfig struct {
 	Port int `envconfig:"SERVER_PORT" default:"8080"`
+
+	// EndpointTimeouts maps route paths to request timeouts,
+	// e.g. "/api/weather:15s,/api/confirm/:token:5s"
+	EndpointTimeouts map[string]time.Duration `envconfig:"SERVER_ENDPOINT_TIMEOUTS"`
+
+	// MaxConcurrentRequests caps in-flight requests; zero means unlimited
+	MaxConcurrentRequests int `envconfig:"MAX_CONCURRENT_REQUESTS" default:"0"`
+
+	// AdminAPIKey protects the admin endpoints; when empty they always
+	// respond 401
+	AdminAPIKey string `envconfig:"ADMIN_API_KEY"`
 }
 
 // DatabaseConfig contains database connection settings
 type DatabaseConfig struct {
+	// URL, when set, is used as the connection string verbatim and takes
+	// precedence over the individual DB_* fields; platforms like Heroku
+	// and Render hand out connection details as a single URL
+	URL      string `envconfig:"DATABASE_URL"`
 	Host     string `envconfig:"DB_HOST" default:"localhost"`
 	Port     int    `envconfig:"DB_PORT" default:"5432"`
 	User     string `envconfig:"DB_USER" default:"postgres"`
 	Password string `envconfig:"DB_PASSWORD" default:"postgres"`
 	Name     string `envconfig:"DB_NAME" default:"weatherapi"`
 	SSLMode  string `envconfig:"DB_SSL_MODE" default:"disable"`
+
+	// ReplicaHost, when set, routes read-only subscription queries to a
+	// Postgres read replica. The remaining Replica* fields fall back to
+	// the primary's values, so a replica sharing credentials and database
+	// name only needs the host configured
+	ReplicaHost     string `envconfig:"DB_REPLICA_HOST"`
+	ReplicaPort     int    `envconfig:"DB_REPLICA_PORT"`
+	ReplicaUser     string `envconfig:"DB_REPLICA_USER"`
+	ReplicaPassword string `envconfig:"DB_REPLICA_PASSWORD"`
+	ReplicaName     string `envconfig:"DB_REPLICA_NAME"`
+	ReplicaSSLMode  string `envconfig:"DB_REPLICA_SSL_MODE"`
+}
+
+// HasReplica reports whether a read replica is configured
+func (c DatabaseConfig) HasReplica() bool {
+	return c.ReplicaHost != ""
 }
 
-// GetDSN returns a formatted database connection string
+// GetReplicaDSN returns the connection string for the read replica, using
+// the primary's settings for any replica field left unset
+func (c DatabaseConfig) GetReplicaDSN() string {
+	port := c.ReplicaPort
+	if port == 0 {
+		port = c.Port
+	}
+	user := c.ReplicaUser
+	if user == "" {
+		user = c.User
+	}
+	password := c.ReplicaPassword
+	if password == "" {
+		password = c.Password
+	}
+	name := c.ReplicaName
+	if name == "" {
+		name = c.Name
+	}
+	sslMode := c.ReplicaSSLMode
+	if sslMode == "" {
+		sslMode = c.SSLMode
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.ReplicaHost, port, user, password, name, sslMode)
+}
+
+// GetDSN returns a formatted database connection string. A configured
+// DATABASE_URL passes through unchanged; the postgres driver accepts both
+// the URL and the keyword form
 func (c DatabaseConfig) GetDSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
 // WeatherConfig contains settings for weather API services
 type WeatherConfig struct {
-	// Primary WeatherAPI.com settings (existing)
-	APIKey  string `envconfig:"WEATHER_API_KEY"`
-	BaseURL string `envconfig:"WEATHER_API_BASE_URL" default:"https://api.weatherapi.com/v1"`
+	// Primary WeatherAPI.com settings (existing). APIKey accepts a single
+	// key or a comma-separated list; multiple keys are rotated across
+	// requests to spread per-key rate limits. APIKeys holds the parsed list
+	APIKey  string   `envconfig:"WEATHER_API_KEY"`
+	APIKeys []string `envconfig:"WEATHER_API_KEY"`
+	BaseURL string   `envconfig:"WEATHER_API_BASE_URL" default:"https://api.weatherapi.com/v1"`
 
 	// Additional provider settings
 	OpenWeatherMapKey     string `envconfig:"OPENWEATHERMAP_API_KEY"`
@@ -59,17 +156,88 @@ type WeatherConfig struct {
 	AccuWeatherKey        string `envconfig:"ACCUWEATHER_API_KEY"`
 	AccuWeatherBaseURL    string `envconfig:"ACCUWEATHER_API_BASE_URL" default:"http://dataservice.accuweather.com/currentconditions/v1"`
 
+	// AccuWeatherMockMode serves canned AccuWeather responses instead of
+	// calling the real location-key and conditions endpoints, for tests and
+	// offline development
+	AccuWeatherMockMode bool `envconfig:"ACCUWEATHER_MOCK_MODE" default:"false"`
+
 	// Provider ordering and features
-	ProviderOrder   []string `envconfig:"WEATHER_PROVIDER_ORDER" default:"weatherapi,openweathermap,accuweather"`
-	EnableCache     bool     `envconfig:"WEATHER_ENABLE_CACHE" default:"true"`
-	EnableLogging   bool     `envconfig:"WEATHER_ENABLE_LOGGING" default:"true"`
-	CacheTTLMinutes int      `envconfig:"WEATHER_CACHE_TTL_MINUTES" default:"10"`
-	LogFilePath     string   `envconfig:"WEATHER_LOG_FILE_PATH" default:"logs/weather_providers.log"`
+	ProviderOrder []string `envconfig:"WEATHER_PROVIDER_ORDER" default:"weatherapi,openweathermap,accuweather"`
+	EnableCache   bool     `envconfig:"WEATHER_ENABLE_CACHE" default:"true"`
+
+	// ProviderOrderStrict turns mismatches between ProviderOrder and the
+	// configured API keys into configuration errors instead of warnings
+	ProviderOrderStrict bool `envconfig:"WEATHER_PROVIDER_ORDER_STRICT" default:"false"`
+
+	// UserAgent identifies outbound provider requests; when unset a default
+	// of the form "weatherapi.app/<version>" is used. ExtraHeaders adds
+	// custom headers ("Name:Value,...") to every provider request
+	UserAgent    string            `envconfig:"WEATHER_USER_AGENT"`
+	ExtraHeaders map[string]string `envconfig:"WEATHER_EXTRA_HEADERS"`
+
+	// CacheMode selects strict or stale-while-revalidate ("swr") caching;
+	// in swr mode an entry past its TTL is served immediately, up to
+	// CacheMaxStalenessSeconds old, while a background refresh runs
+	CacheMode                string `envconfig:"WEATHER_CACHE_MODE" default:"strict"`
+	CacheMaxStalenessSeconds int    `envconfig:"WEATHER_CACHE_MAX_STALENESS_SECONDS" default:"300"`
+
+	EnableLogging   bool   `envconfig:"WEATHER_ENABLE_LOGGING" default:"true"`
+	CacheTTLMinutes int    `envconfig:"WEATHER_CACHE_TTL_MINUTES" default:"10"`
+	LogFilePath     string `envconfig:"WEATHER_LOG_FILE_PATH" default:"logs/weather_providers.log"`
+
+	// ServeStaleOnError degrades to the last cached value, regardless of its
+	// TTL, when every provider fails; responses served this way are flagged
+	// as stale. Off by default so chain failures keep surfacing as errors
+	ServeStaleOnError bool `envconfig:"WEATHER_SERVE_STALE_ON_ERROR" default:"false"`
+
+	// NegativeCacheTTLSeconds caches "city not found" results for a short
+	// period; zero disables negative caching
+	NegativeCacheTTLSeconds int `envconfig:"WEATHER_NEGATIVE_CACHE_TTL_SECONDS" default:"0"`
+
+	// MemoryCacheMaxEntries bounds the in-memory cache with LRU eviction so
+	// it cannot grow without limit between TTL cleanups; zero leaves the
+	// cache TTL-only
+	MemoryCacheMaxEntries int `envconfig:"WEATHER_MEMORY_CACHE_MAX_ENTRIES" default:"0"`
+
+	// ProviderTimeoutSeconds bounds each HTTP request to an external provider
+	ProviderTimeoutSeconds int `envconfig:"WEATHER_PROVIDER_TIMEOUT_SECONDS" default:"10"`
+
+	// FetchLockTTLSeconds enables a Redis-backed lock around provider
+	// fetches so a cold cache key is fetched by one replica at a time;
+	// zero disables locking. FetchLockWaitMS bounds how long a replica
+	// waits for another replica's fetch before fetching itself
+	FetchLockTTLSeconds int `envconfig:"WEATHER_FETCH_LOCK_TTL_SECONDS" default:"0"`
+	FetchLockWaitMS     int `envconfig:"WEATHER_FETCH_LOCK_WAIT_MS" default:"500"`
+
+	// RestrictToSubscribed limits weather queries to cities with at least
+	// one confirmed subscription; off by default
+	RestrictToSubscribed bool `envconfig:"WEATHER_RESTRICT_TO_SUBSCRIBED" default:"false"`
+
+	// CityMaxLength bounds accepted city-name length, in characters, for
+	// subscribe requests and weather lookups
+	CityMaxLength int `envconfig:"WEATHER_CITY_MAX_LENGTH" default:"100"`
+
+	// BatchConcurrency caps concurrent provider lookups for batch requests
+	BatchConcurrency int `envconfig:"WEATHER_BATCH_CONCURRENCY" default:"5"`
+
+	// StoreHistory persists each successful weather fetch so recent history
+	// can be queried through the API; off by default
+	StoreHistory bool `envconfig:"WEATHER_STORE_HISTORY" default:"false"`
+
+	// Optional response transformation (disabled by default)
+	TransformRoundPrecision int     `envconfig:"WEATHER_TRANSFORM_ROUND_PRECISION" default:"-1"`
+	TransformTempOffset     float64 `envconfig:"WEATHER_TRANSFORM_TEMP_OFFSET" default:"0"`
 }
 
 // CacheConfig contains cache-specific settings
 type CacheConfig struct {
-	Type  string `envconfig:"CACHE_TYPE" default:"memory"`
+	Type string `envconfig:"CACHE_TYPE" default:"memory"`
+
+	// FallbackToMemory keeps the application starting when the Redis cache
+	// is unavailable at boot by serving from a memory cache instead; without
+	// it cache construction failures are fatal
+	FallbackToMemory bool `envconfig:"CACHE_FALLBACK_TO_MEMORY" default:"false"`
+
 	Redis RedisConfig
 }
 
@@ -81,6 +249,16 @@ type RedisConfig struct {
 	DialTimeout  int    `envconfig:"REDIS_DIAL_TIMEOUT" default:"5"`
 	ReadTimeout  int    `envconfig:"REDIS_READ_TIMEOUT" default:"3"`
 	WriteTimeout int    `envconfig:"REDIS_WRITE_TIMEOUT" default:"3"`
+
+	// Connection pool tuning; zero values keep the go-redis defaults so
+	// existing configurations behave the same
+	PoolSize     int `envconfig:"REDIS_POOL_SIZE" default:"0"`
+	MinIdleConns int `envconfig:"REDIS_MIN_IDLE_CONNS" default:"0"`
+	MaxConnAge   int `envconfig:"REDIS_MAX_CONN_AGE" default:"0"`
+
+	// ClusterAddrs lists Redis Cluster node addresses; only used when
+	// CACHE_TYPE is "redis-cluster"
+	ClusterAddrs []string `envconfig:"REDIS_CLUSTER_ADDRS"`
 }
 
 // EmailConfig contains email server and sending settings
@@ -91,12 +269,132 @@ type EmailConfig struct {
 	SMTPPassword string `envconfig:"EMAIL_SMTP_PASSWORD"`
 	FromName     string `envconfig:"EMAIL_FROM_NAME" default:"Weather API"`
 	FromAddress  string `envconfig:"EMAIL_FROM_ADDRESS" default:"no-reply@weatherapi.app"`
+
+	// IncludeCancelLink adds a "cancel this signup" link to confirmation emails
+	IncludeCancelLink bool `envconfig:"EMAIL_INCLUDE_CANCEL_LINK" default:"false"`
+
+	// AllowedDomains, when set, restricts signups to these email domains;
+	// BlockedDomains rejects listed domains (e.g. disposable providers).
+	// Both lists are comma-separated, case-insensitive, and cover
+	// subdomains. Empty lists impose no restriction
+	AllowedDomains []string `envconfig:"EMAIL_ALLOWED_DOMAINS"`
+	BlockedDomains []string `envconfig:"EMAIL_BLOCKED_DOMAINS"`
+
+	// VerifyMX rejects signups whose email domain has no MX records. Off by
+	// default since the DNS lookup adds latency to subscribe requests
+	VerifyMX bool `envconfig:"EMAIL_VERIFY_MX" default:"false"`
+
+	// SubjectPrefix is prepended to every outgoing subject line, e.g.
+	// "[STAGING]" to make test emails unmistakable. Empty adds nothing
+	SubjectPrefix string `envconfig:"EMAIL_SUBJECT_PREFIX"`
+
+	// AggregateDigest combines all of a subscriber's cities into one digest
+	// email per update run instead of one email per city. Off by default so
+	// existing subscribers keep receiving per-city emails
+	AggregateDigest bool `envconfig:"EMAIL_AGGREGATE_DIGEST" default:"false"`
+
+	// TemplatesDir holds optional HTML body templates per email type; emails
+	// without a template file use the embedded defaults
+	TemplatesDir string `envconfig:"EMAIL_TEMPLATES_DIR" default:"email/templates"`
+
+	// DKIM signing of outgoing messages is enabled when all three settings
+	// are provided; leaving DKIMPrivateKeyPath empty disables it
+	DKIMPrivateKeyPath string `envconfig:"EMAIL_DKIM_PRIVATE_KEY_PATH"`
+	DKIMDomain         string `envconfig:"EMAIL_DKIM_DOMAIN"`
+	DKIMSelector       string `envconfig:"EMAIL_DKIM_SELECTOR"`
+
+	// Workers sizes the in-process queue that delivers weather update emails
+	// concurrently during bulk runs
+	Workers int `envconfig:"EMAIL_WORKERS" default:"4"`
+
+	// MaxRetries bounds how many times a transiently failing send (connection
+	// refused, timeout, 4xx server reply) is retried with backoff; permanent
+	// rejections are never retried
+	MaxRetries int `envconfig:"EMAIL_MAX_RETRIES" default:"3"`
+
+	// PoolSize enables SMTP connection pooling for bulk sends; zero keeps
+	// the one-connection-per-send behavior. PoolIdleTimeoutSeconds discards
+	// pooled connections that sat unused for too long
+	PoolSize               int `envconfig:"EMAIL_SMTP_POOL_SIZE" default:"0"`
+	PoolIdleTimeoutSeconds int `envconfig:"EMAIL_SMTP_POOL_IDLE_TIMEOUT_SECONDS" default:"60"`
+}
+
+// TracingConfig controls optional span recording around weather lookups and
+// database operations
+type TracingConfig struct {
+	// Enabled turns on span recording; spans are emitted as structured
+	// debug logs correlated by trace ID
+	Enabled bool `envconfig:"TRACING_ENABLED" default:"false"`
+}
+
+// LoggingConfig controls the application's own stdout logging; distinct
+// from the file logger used for provider request logs
+type LoggingConfig struct {
+	// Format selects the global slog handler: "text" for local development,
+	// "json" for production log aggregation
+	Format string `envconfig:"LOG_FORMAT" default:"text"`
+
+	// Level is the minimum level emitted: debug, info, warn, or error
+	Level string `envconfig:"LOG_LEVEL" default:"info"`
+}
+
+// Validate checks logging configuration
+func (l *LoggingConfig) Validate() error {
+	switch l.Format {
+	case "", "text", "json":
+	default:
+		return errors.NewConfigurationError("LOG_FORMAT must be either 'text' or 'json'", nil)
+	}
+	switch strings.ToLower(l.Level) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return errors.NewConfigurationError("LOG_LEVEL must be one of: debug, info, warn, error", nil)
+	}
+	return nil
+}
+
+// SlogLevel maps the configured level onto slog's levels, defaulting to info
+func (l *LoggingConfig) SlogLevel() slog.Level {
+	switch strings.ToLower(l.Level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
 }
 
 // SchedulerConfig contains settings for the background task scheduler
 type SchedulerConfig struct {
 	HourlyInterval int `envconfig:"HOURLY_INTERVAL" default:"60"`
 	DailyInterval  int `envconfig:"DAILY_INTERVAL" default:"1440"`
+	WeeklyInterval int `envconfig:"WEEKLY_INTERVAL" default:"10080"`
+
+	// CleanupIntervalMinutes controls how often stale unconfirmed
+	// subscriptions are purged
+	CleanupIntervalMinutes int `envconfig:"CLEANUP_INTERVAL_MINUTES" default:"60"`
+
+	// AlertIntervalMinutes controls how often alert subscriptions are
+	// checked against current conditions
+	AlertIntervalMinutes int `envconfig:"ALERT_INTERVAL_MINUTES" default:"30"`
+
+	// JitterPercent randomizes each scheduled run's delay by up to ± this
+	// percentage of the interval, so identically configured replicas do not
+	// all fire at the same wall-clock moment; zero keeps runs deterministic
+	JitterPercent int `envconfig:"SCHEDULER_JITTER_PERCENT" default:"0"`
+
+	// BatchStaggerMS pauses this many milliseconds between consecutive
+	// subscriber sends in an update run, spreading load on the weather
+	// providers and SMTP server; zero sends back to back
+	BatchStaggerMS int `envconfig:"SCHEDULER_BATCH_STAGGER_MS" default:"0"`
+
+	// LeaderElection makes replicas contend on a Postgres advisory lock so
+	// only one instance triggers weather update and alert runs; off by
+	// default for single-instance development
+	LeaderElection bool `envconfig:"SCHEDULER_LEADER_ELECTION" default:"false"`
 }
 
 // LoadConfig loads and validates application configuration from environment variables
@@ -113,6 +411,61 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// LoadConfigFromFile loads configuration from a JSON file, with environment
+// variables taking precedence over file values and envconfig defaults filling
+// whatever neither provides. Callers that do not use a file keep going
+// through LoadConfig unchanged
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewConfigurationError(fmt.Sprintf("read config file %s", path), err)
+	}
+
+	var fileConfig Config
+	if err := json.Unmarshal(data, &fileConfig); err != nil {
+		return nil, errors.NewConfigurationError(fmt.Sprintf("parse config file %s", path), err)
+	}
+
+	var config Config
+	if err := envconfig.Process("", &config); err != nil {
+		return nil, errors.NewConfigurationError("error processing config", err)
+	}
+
+	overlayFileValues(reflect.ValueOf(&config).Elem(), reflect.ValueOf(&fileConfig).Elem())
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// overlayFileValues copies non-zero file values over the env-derived config
+// for every field whose environment variable is not set, giving the
+// precedence env > file > default
+func overlayFileValues(dst, src reflect.Value) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			overlayFileValues(dst.Field(i), src.Field(i))
+			continue
+		}
+
+		key := field.Tag.Get("envconfig")
+		if key == "" {
+			key = strings.ToUpper(field.Name)
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if src.Field(i).IsZero() {
+			continue
+		}
+		dst.Field(i).Set(src.Field(i))
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if err := c.Server.Validate(); err != nil {
@@ -133,22 +486,38 @@ func (c *Config) Validate() error {
 	if err := c.Cache.Validate(); err != nil {
 		return err
 	}
+	if err := c.Logging.Validate(); err != nil {
+		return err
+	}
 	if err := c.validateAppBaseURL(); err != nil {
 		return err
 	}
+	if err := c.validateConfirmationTokenTTL(); err != nil {
+		return err
+	}
+	if c.MaxSubscriptionsPerEmail < 0 {
+		return errors.NewConfigurationError("MAX_SUBSCRIPTIONS_PER_EMAIL cannot be negative", nil)
+	}
 	return nil
 }
 
 // Validate checks cache configuration
 func (c *CacheConfig) Validate() error {
-	if c.Type != "memory" && c.Type != "redis" {
-		return errors.NewConfigurationError("CACHE_TYPE must be one of: memory, redis", nil)
+	if c.Type != "memory" && c.Type != "redis" && c.Type != "redis-cluster" {
+		return errors.NewConfigurationError("CACHE_TYPE must be one of: memory, redis, redis-cluster", nil)
 	}
 
 	if c.Type == "redis" {
 		return c.Redis.Validate()
 	}
 
+	if c.Type == "redis-cluster" {
+		if len(c.Redis.ClusterAddrs) == 0 {
+			return errors.NewConfigurationError("REDIS_CLUSTER_ADDRS cannot be empty when using Redis cluster cache", nil)
+		}
+		return c.Redis.validateTimeouts()
+	}
+
 	return nil
 }
 
@@ -160,6 +529,15 @@ func (r *RedisConfig) Validate() error {
 	if r.DB < 0 || r.DB > maxRedisDB {
 		return errors.NewConfigurationError("REDIS_DB must be between 0 and 15", nil)
 	}
+	if err := r.validateTimeouts(); err != nil {
+		return err
+	}
+	return r.validatePool()
+}
+
+// validateTimeouts checks the Redis timeout settings shared by single-node
+// and cluster modes
+func (r *RedisConfig) validateTimeouts() error {
 	if r.DialTimeout < 1 {
 		return errors.NewConfigurationError("REDIS_DIAL_TIMEOUT must be at least 1 second", nil)
 	}
@@ -172,6 +550,21 @@ func (r *RedisConfig) Validate() error {
 	return nil
 }
 
+// validatePool checks the connection pool tunables; zero values defer to the
+// go-redis defaults
+func (r *RedisConfig) validatePool() error {
+	if r.PoolSize < 0 {
+		return errors.NewConfigurationError("REDIS_POOL_SIZE cannot be negative", nil)
+	}
+	if r.MinIdleConns < 0 {
+		return errors.NewConfigurationError("REDIS_MIN_IDLE_CONNS cannot be negative", nil)
+	}
+	if r.MaxConnAge < 0 {
+		return errors.NewConfigurationError("REDIS_MAX_CONN_AGE cannot be negative", nil)
+	}
+	return nil
+}
+
 func (c *Config) validateAppBaseURL() error {
 	if c.AppBaseURL == "" {
 		return errors.NewConfigurationError("APP_URL cannot be empty", nil)
@@ -182,11 +575,40 @@ func (c *Config) validateAppBaseURL() error {
 	return nil
 }
 
+func (c *Config) validateConfirmationTokenTTL() error {
+	// Zero means unset; LoadConfig applies the 24-hour default via envconfig
+	if c.ConfirmationTokenTTLHours == 0 {
+		return nil
+	}
+	if c.ConfirmationTokenTTLHours < 1 || c.ConfirmationTokenTTLHours > maxConfirmationTokenTTLHours {
+		return errors.NewConfigurationError("CONFIRMATION_TOKEN_TTL_HOURS must be between 1 and 168", nil)
+	}
+	return nil
+}
+
+// ConfirmationTokenTTL returns the confirmation token lifetime, falling back
+// to 24 hours when the value is unset
+func (c *Config) ConfirmationTokenTTL() time.Duration {
+	if c.ConfirmationTokenTTLHours < 1 {
+		return defaultConfirmationTokenTTLHours * time.Hour
+	}
+	return time.Duration(c.ConfirmationTokenTTLHours) * time.Hour
+}
+
 // Validate checks server configuration
 func (s *ServerConfig) Validate() error {
 	if s.Port < 1 || s.Port > maxPortNumber {
 		return errors.NewConfigurationError("SERVER_PORT must be between 1 and 65535", nil)
 	}
+	for path, timeout := range s.EndpointTimeouts {
+		if timeout <= 0 {
+			return errors.NewConfigurationError(
+				fmt.Sprintf("SERVER_ENDPOINT_TIMEOUTS entry for %s must be positive", path), nil)
+		}
+	}
+	if s.MaxConcurrentRequests < 0 {
+		return errors.NewConfigurationError("MAX_CONCURRENT_REQUESTS cannot be negative", nil)
+	}
 	return nil
 }
 
@@ -202,8 +624,56 @@ func (d *DatabaseConfig) ValidateSSLMode() error {
 		fmt.Sprintf("DB_SSL_MODE must be one of: %s", strings.Join(validSSLModes, ", ")), nil)
 }
 
+// applyURL parses DATABASE_URL, when set, into the individual connection
+// fields so validation and diagnostics see the same values regardless of
+// which form configured the database; components the URL leaves out keep
+// their DB_* values
+func (d *DatabaseConfig) applyURL() error {
+	if d.URL == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(d.URL)
+	if err != nil {
+		return errors.NewConfigurationError("DATABASE_URL is not a valid URL", err)
+	}
+	if parsed.Scheme != "postgres" && parsed.Scheme != "postgresql" {
+		return errors.NewConfigurationError("DATABASE_URL scheme must be postgres or postgresql", nil)
+	}
+
+	if host := parsed.Hostname(); host != "" {
+		d.Host = host
+	}
+	if port := parsed.Port(); port != "" {
+		portNumber, err := strconv.Atoi(port)
+		if err != nil {
+			return errors.NewConfigurationError("DATABASE_URL port must be a number", err)
+		}
+		d.Port = portNumber
+	}
+	if parsed.User != nil {
+		if user := parsed.User.Username(); user != "" {
+			d.User = user
+		}
+		if password, ok := parsed.User.Password(); ok {
+			d.Password = password
+		}
+	}
+	if name := strings.TrimPrefix(parsed.Path, "/"); name != "" {
+		d.Name = name
+	}
+	if sslMode := parsed.Query().Get("sslmode"); sslMode != "" {
+		d.SSLMode = sslMode
+	}
+
+	return nil
+}
+
 // Validate checks database configuration
 func (d *DatabaseConfig) Validate() error {
+	if err := d.applyURL(); err != nil {
+		return err
+	}
 	if d.Host == "" {
 		return errors.NewConfigurationError("DB_HOST cannot be empty", nil)
 	}
@@ -219,6 +689,9 @@ func (d *DatabaseConfig) Validate() error {
 	if err := d.ValidateSSLMode(); err != nil {
 		return err
 	}
+	if d.ReplicaPort != 0 && (d.ReplicaPort < 1 || d.ReplicaPort > maxPortNumber) {
+		return errors.NewConfigurationError("DB_REPLICA_PORT must be between 1 and 65535", nil)
+	}
 	return nil
 }
 
@@ -244,6 +717,14 @@ func (w *WeatherConfig) Validate() error {
 		return errors.NewConfigurationError("WEATHER_CACHE_TTL_MINUTES must be between 1 and 1440 minutes", nil)
 	}
 
+	// Validate cache mode
+	if w.CacheMode != "" && w.CacheMode != "strict" && w.CacheMode != "swr" {
+		return errors.NewConfigurationError("WEATHER_CACHE_MODE must be either 'strict' or 'swr'", nil)
+	}
+	if w.CacheMaxStalenessSeconds < 0 {
+		return errors.NewConfigurationError("WEATHER_CACHE_MAX_STALENESS_SECONDS cannot be negative", nil)
+	}
+
 	// Validate provider order contains valid providers
 	validProviders := map[string]bool{
 		"weatherapi":     true,
@@ -257,9 +738,115 @@ func (w *WeatherConfig) Validate() error {
 		}
 	}
 
+	if err := w.validateProviderOrderCoverage(); err != nil {
+		return err
+	}
+
+	// Validate negative cache TTL: optional, but must stay shorter than the positive TTL
+	if w.NegativeCacheTTLSeconds < 0 {
+		return errors.NewConfigurationError("WEATHER_NEGATIVE_CACHE_TTL_SECONDS cannot be negative", nil)
+	}
+	if w.NegativeCacheTTLSeconds >= w.CacheTTLMinutes*60 && w.NegativeCacheTTLSeconds > 0 {
+		return errors.NewConfigurationError("WEATHER_NEGATIVE_CACHE_TTL_SECONDS must be shorter than the weather cache TTL", nil)
+	}
+
+	// Fetch lock settings are optional but cannot be negative
+	if w.FetchLockTTLSeconds < 0 {
+		return errors.NewConfigurationError("WEATHER_FETCH_LOCK_TTL_SECONDS cannot be negative", nil)
+	}
+	if w.FetchLockWaitMS < 0 {
+		return errors.NewConfigurationError("WEATHER_FETCH_LOCK_WAIT_MS cannot be negative", nil)
+	}
+
+	// City length bound: zero falls back to the built-in default
+	if w.CityMaxLength < 0 {
+		return errors.NewConfigurationError("WEATHER_CITY_MAX_LENGTH cannot be negative", nil)
+	}
+
+	// Memory cache bound: zero keeps the cache TTL-only
+	if w.MemoryCacheMaxEntries < 0 {
+		return errors.NewConfigurationError("WEATHER_MEMORY_CACHE_MAX_ENTRIES cannot be negative", nil)
+	}
+
+	// Validate batch concurrency
+	if w.BatchConcurrency < 1 || w.BatchConcurrency > maxBatchConcurrency {
+		return errors.NewConfigurationError("WEATHER_BATCH_CONCURRENCY must be between 1 and 50", nil)
+	}
+
+	// Validate provider timeout
+	if w.ProviderTimeoutSeconds < minProviderTimeoutSeconds || w.ProviderTimeoutSeconds > maxProviderTimeoutSeconds {
+		return errors.NewConfigurationError("WEATHER_PROVIDER_TIMEOUT_SECONDS must be between 1 and 120 seconds", nil)
+	}
+
+	// Validate transform settings (negative precision disables rounding)
+	if w.TransformRoundPrecision > maxRoundPrecision {
+		return errors.NewConfigurationError("WEATHER_TRANSFORM_ROUND_PRECISION cannot exceed 6 decimal places", nil)
+	}
+
+	return nil
+}
+
+// validateProviderOrderCoverage cross-checks ProviderOrder against the
+// configured API keys: a key whose provider is missing from the order is
+// silently disabled, and an ordered provider without a key can never serve
+// requests. Strict mode turns either mismatch into an error; the default
+// keeps the lenient behavior and only logs a warning
+func (w *WeatherConfig) validateProviderOrderCoverage() error {
+	keys := []struct {
+		provider string
+		key      string
+	}{
+		{"weatherapi", w.APIKey},
+		{"openweathermap", w.OpenWeatherMapKey},
+		{"accuweather", w.AccuWeatherKey},
+	}
+
+	ordered := make(map[string]bool, len(w.ProviderOrder))
+	for _, provider := range w.ProviderOrder {
+		ordered[provider] = true
+	}
+
+	for _, entry := range keys {
+		if entry.key != "" && !ordered[entry.provider] {
+			message := fmt.Sprintf("provider %s has an API key configured but is missing from WEATHER_PROVIDER_ORDER", entry.provider)
+			if w.ProviderOrderStrict {
+				return errors.NewConfigurationError(message, nil)
+			}
+			slog.Warn("Weather provider configuration mismatch", "detail", message)
+		}
+		if entry.key == "" && ordered[entry.provider] {
+			message := fmt.Sprintf("provider %s is listed in WEATHER_PROVIDER_ORDER but has no API key configured", entry.provider)
+			if w.ProviderOrderStrict {
+				return errors.NewConfigurationError(message, nil)
+			}
+			slog.Warn("Weather provider configuration mismatch", "detail", message)
+		}
+	}
+
 	return nil
 }
 
+// ParseAPIKeyList splits a comma-separated API key value into individual
+// keys, dropping empty entries and surrounding whitespace
+func ParseAPIKeyList(raw string) []string {
+	var keys []string
+	for _, key := range strings.Split(raw, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// WeatherAPIKeys returns the configured WeatherAPI.com keys, parsing APIKey
+// when the list form was not populated (e.g. configs built in code)
+func (w *WeatherConfig) WeatherAPIKeys() []string {
+	if len(w.APIKeys) > 0 {
+		return ParseAPIKeyList(strings.Join(w.APIKeys, ","))
+	}
+	return ParseAPIKeyList(w.APIKey)
+}
+
 // Validate checks email configuration
 func (e *EmailConfig) Validate() error {
 	if e.SMTPHost == "" {
@@ -281,9 +868,63 @@ func (e *EmailConfig) Validate() error {
 	if !strings.Contains(e.FromAddress, "@") {
 		return errors.NewConfigurationError("EMAIL_FROM_ADDRESS must be a valid email address", nil)
 	}
+	dkimSettings := 0
+	for _, value := range []string{e.DKIMPrivateKeyPath, e.DKIMDomain, e.DKIMSelector} {
+		if value != "" {
+			dkimSettings++
+		}
+	}
+	if dkimSettings != 0 && dkimSettings != 3 {
+		return errors.NewConfigurationError(
+			"EMAIL_DKIM_PRIVATE_KEY_PATH, EMAIL_DKIM_DOMAIN and EMAIL_DKIM_SELECTOR must all be provided to enable DKIM signing", nil)
+	}
+	if e.Workers < 0 {
+		return errors.NewConfigurationError("EMAIL_WORKERS cannot be negative", nil)
+	}
+	if e.MaxRetries < 0 {
+		return errors.NewConfigurationError("EMAIL_MAX_RETRIES cannot be negative", nil)
+	}
+	if e.PoolSize < 0 {
+		return errors.NewConfigurationError("EMAIL_SMTP_POOL_SIZE cannot be negative", nil)
+	}
+	if e.PoolSize > 0 && e.PoolIdleTimeoutSeconds < 1 {
+		return errors.NewConfigurationError("EMAIL_SMTP_POOL_IDLE_TIMEOUT_SECONDS must be at least 1 second when pooling is enabled", nil)
+	}
 	return nil
 }
 
+// DomainAllowed reports whether the address's domain passes the configured
+// allow/block lists. Matching is case-insensitive and a listed "example.com"
+// also covers "mail.example.com". With neither list set every domain passes
+func (e *EmailConfig) DomainAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		// Malformed addresses are the format check's problem, not ours
+		return true
+	}
+	domain := strings.ToLower(email[at+1:])
+
+	if len(e.AllowedDomains) > 0 && !domainMatchesAny(domain, e.AllowedDomains) {
+		return false
+	}
+	return !domainMatchesAny(domain, e.BlockedDomains)
+}
+
+// domainMatchesAny reports whether the domain equals, or is a subdomain of,
+// any entry in the list
+func domainMatchesAny(domain string, list []string) bool {
+	for _, entry := range list {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if domain == entry || strings.HasSuffix(domain, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks scheduler configuration
 func (s *SchedulerConfig) Validate() error {
 	if s.HourlyInterval < 1 {
@@ -298,5 +939,23 @@ func (s *SchedulerConfig) Validate() error {
 	if s.DailyInterval > maxDailyInterval {
 		return errors.NewConfigurationError("DAILY_INTERVAL cannot exceed 10080 minutes (7 days)", nil)
 	}
+	if s.WeeklyInterval < 1 {
+		return errors.NewConfigurationError("WEEKLY_INTERVAL must be at least 1 minute", nil)
+	}
+	if s.WeeklyInterval > maxDailyInterval {
+		return errors.NewConfigurationError("WEEKLY_INTERVAL cannot exceed 10080 minutes (7 days)", nil)
+	}
+	if s.CleanupIntervalMinutes < 1 {
+		return errors.NewConfigurationError("CLEANUP_INTERVAL_MINUTES must be at least 1 minute", nil)
+	}
+	if s.CleanupIntervalMinutes > maxDailyInterval {
+		return errors.NewConfigurationError("CLEANUP_INTERVAL_MINUTES cannot exceed 10080 minutes (7 days)", nil)
+	}
+	if s.JitterPercent < 0 || s.JitterPercent > maxJitterPercent {
+		return errors.NewConfigurationError("SCHEDULER_JITTER_PERCENT must be between 0 and 50", nil)
+	}
+	if s.BatchStaggerMS < 0 {
+		return errors.NewConfigurationError("SCHEDULER_BATCH_STAGGER_MS cannot be negative", nil)
+	}
 	return nil
 }
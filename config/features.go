@@ -0,0 +1,29 @@
+package config
+
+// FeaturesConfig contains feature flags used for gradual rollout of new
+// capabilities. Experimental flags default to off.
+type FeaturesConfig struct {
+	Forecast bool `envconfig:"FEATURE_FORECAST" default:"false"`
+}
+
+// FeatureFlags exposes the current flag states to handlers and use cases
+type FeatureFlags struct {
+	features FeaturesConfig
+}
+
+// NewFeatureFlags creates a feature flag provider from the loaded configuration
+func NewFeatureFlags(features FeaturesConfig) *FeatureFlags {
+	return &FeatureFlags{features: features}
+}
+
+// ForecastEnabled reports whether the forecast feature is enabled
+func (f *FeatureFlags) ForecastEnabled() bool {
+	return f.features.Forecast
+}
+
+// States returns the current value of every flag for introspection
+func (f *FeatureFlags) States() map[string]bool {
+	return map[string]bool{
+		"forecast": f.features.Forecast,
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlags_DefaultOff(t *testing.T) {
+	flags := NewFeatureFlags(FeaturesConfig{})
+
+	assert.False(t, flags.ForecastEnabled())
+}
+
+func TestFeatureFlags_States(t *testing.T) {
+	flags := NewFeatureFlags(FeaturesConfig{Forecast: true})
+
+	assert.True(t, flags.ForecastEnabled())
+	assert.Equal(t, map[string]bool{"forecast": true}, flags.States())
+}
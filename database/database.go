@@ -20,11 +20,31 @@ func InitDB(config config.DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// InitReplicaDB opens a connection to the configured read replica,
+// returning nil when none is configured so callers fall back to the
+// primary for everything
+func InitReplicaDB(config config.DatabaseConfig) (*gorm.DB, error) {
+	if !config.HasReplica() {
+		return nil, nil
+	}
+
+	db, err := gorm.Open(postgres.Open(config.GetReplicaDSN()), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("connect to read replica: %w", err)
+	}
+
+	return db, nil
+}
+
 // RunMigrations executes database schema migrations
 func RunMigrations(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&models.Subscription{},
 		&models.Token{},
+		&models.IdempotencyKey{},
+		&models.FailedEmail{},
+		&models.NotificationRun{},
+		&models.WeatherObservation{},
 	)
 }
 
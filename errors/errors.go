@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Application error types organized by category for better error handling
 
@@ -11,6 +14,7 @@ const (
 	ValidationError    ErrorType = "VALIDATION_ERROR"
 	NotFoundError      ErrorType = "NOT_FOUND_ERROR"
 	AlreadyExistsError ErrorType = "ALREADY_EXISTS_ERROR"
+	ConflictError      ErrorType = "CONFLICT_ERROR"
 	TokenError         ErrorType = "TOKEN_ERROR"
 )
 
@@ -19,6 +23,7 @@ const (
 	DatabaseError    ErrorType = "DATABASE_ERROR"
 	ExternalAPIError ErrorType = "EXTERNAL_API_ERROR"
 	EmailError       ErrorType = "EMAIL_ERROR"
+	RateLimitError   ErrorType = "RATE_LIMIT_ERROR"
 )
 
 // System/Configuration Errors - errors related to system setup and configuration
@@ -26,10 +31,22 @@ const (
 	ConfigurationError ErrorType = "CONFIGURATION_ERROR"
 )
 
+// FieldError identifies the request field a validation error refers to,
+// with a machine-readable code so clients do not have to parse messages
+type FieldError struct {
+	Field string
+	Code  string
+}
+
 type AppError struct {
 	Type    ErrorType
 	Message string
 	Cause   error
+	Fields  []FieldError
+
+	// RetryAfter, set on RateLimitError, is how long the upstream asked
+	// callers to wait before retrying; zero when it did not say
+	RetryAfter time.Duration
 }
 
 func (e *AppError) Error() string {
@@ -63,6 +80,14 @@ func NewValidationError(message string) *AppError {
 	return New(ValidationError, message)
 }
 
+// NewFieldValidationError builds a validation error that names the offending
+// field alongside the human-readable message
+func NewFieldValidationError(message, field, code string) *AppError {
+	err := New(ValidationError, message)
+	err.Fields = []FieldError{{Field: field, Code: code}}
+	return err
+}
+
 func NewNotFoundError(message string) *AppError {
 	return New(NotFoundError, message)
 }
@@ -71,6 +96,12 @@ func NewAlreadyExistsError(message string) *AppError {
 	return New(AlreadyExistsError, message)
 }
 
+// NewConflictError reports that a write lost an optimistic-concurrency race
+// and can be retried with fresh data
+func NewConflictError(message string) *AppError {
+	return New(ConflictError, message)
+}
+
 func NewTokenError(message string) *AppError {
 	return New(TokenError, message)
 }
@@ -88,6 +119,14 @@ func NewEmailError(message string, cause error) *AppError {
 	return Wrap(EmailError, message, cause)
 }
 
+// NewRateLimitError reports that an upstream rejected the request with a
+// rate limit; retryAfter carries the upstream's Retry-After hint when given
+func NewRateLimitError(message string, retryAfter time.Duration) *AppError {
+	err := New(RateLimitError, message)
+	err.RetryAfter = retryAfter
+	return err
+}
+
 // System/Configuration Error Constructors
 func NewConfigurationError(message string, cause error) *AppError {
 	return Wrap(ConfigurationError, message, cause)
@@ -189,6 +189,15 @@ func TestSpecificErrorConstructors(t *testing.T) {
 	}
 }
 
+func TestNewFieldValidationError(t *testing.T) {
+	err := NewFieldValidationError("email is required", "email", "required")
+
+	assert.Equal(t, ValidationError, err.Type)
+	assert.Equal(t, "email is required", err.Message)
+	assert.Equal(t, []FieldError{{Field: "email", Code: "required"}}, err.Fields)
+	assert.Nil(t, err.Cause)
+}
+
 func TestErrorTypes(t *testing.T) {
 	tests := []struct {
 		name      string
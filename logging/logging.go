@@ -0,0 +1,31 @@
+// Package logging carries request-scoped logging context between layers so
+// the log lines of one HTTP request can be correlated by its request ID.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty
+// string when none is set
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext returns a logger annotated with the request ID carried by ctx,
+// falling back to the default logger when none is set
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return slog.Default().With("request_id", requestID)
+	}
+	return slog.Default()
+}
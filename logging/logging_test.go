@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDRoundTrip(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	assert.Equal(t, "req-123", RequestIDFromContext(ctx))
+	assert.Empty(t, RequestIDFromContext(context.Background()))
+}
+
+func TestFromContext_AnnotatesWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(original)
+
+	ctx := ContextWithRequestID(context.Background(), "req-456")
+	FromContext(ctx).Info("hello")
+
+	assert.Contains(t, buf.String(), "request_id=req-456")
+}
+
+func TestFromContext_FallsBackToDefault(t *testing.T) {
+	var buf bytes.Buffer
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(original)
+
+	FromContext(context.Background()).Info("hello")
+
+	assert.NotContains(t, buf.String(), "request_id")
+}
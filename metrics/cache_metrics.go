@@ -21,6 +21,11 @@ type CacheStats struct {
 	Misses    int64   `json:"misses"`
 	Total     int64   `json:"total"`
 	HitRatio  float64 `json:"hit_ratio"`
+
+	// Entries and ApproxBytes describe the cache footprint when the backend
+	// can measure it; both stay zero when it cannot
+	Entries     int64 `json:"entries"`
+	ApproxBytes int64 `json:"approx_bytes"`
 }
 
 var globalCollector *CacheMetricsCollector
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Email delivery counters, labeled by email type (confirmation, welcome,
+// unsubscribe, unsubscribe_all, weather_update, weather_alert). They are
+// incremented around every send by the email service so a spike in failures
+// is visible in Prometheus.
+var (
+	EmailsSentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_emails_sent_total",
+			Help: "The total number of emails sent successfully per email type",
+		},
+		[]string{"type"},
+	)
+
+	EmailsFailedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_emails_failed_total",
+			Help: "The total number of failed email sends per email type",
+		},
+		[]string{"type"},
+	)
+
+	EmailSendDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "weather_email_send_duration_seconds",
+			Help:    "Duration of email sends in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 11), // 10ms up to ~10s
+		},
+		[]string{"type"},
+	)
+)
+
+// ObserveEmailSend records the duration and outcome of one email send
+// attempt for the given email type
+func ObserveEmailSend(emailType string, start time.Time, err error) {
+	EmailSendDurationSeconds.WithLabelValues(emailType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		EmailsFailedTotal.WithLabelValues(emailType).Inc()
+		return
+	}
+	EmailsSentTotal.WithLabelValues(emailType).Inc()
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"weatherapi.app/errors"
+)
+
+func TestObserveEmailSend(t *testing.T) {
+	sentBefore := testutil.ToFloat64(EmailsSentTotal.WithLabelValues("weather_update"))
+	failedBefore := testutil.ToFloat64(EmailsFailedTotal.WithLabelValues("weather_update"))
+
+	ObserveEmailSend("weather_update", time.Now(), nil)
+	ObserveEmailSend("weather_update", time.Now(), nil)
+	ObserveEmailSend("weather_update", time.Now(), errors.NewEmailError("smtp down", nil))
+
+	assert.Equal(t, sentBefore+2, testutil.ToFloat64(EmailsSentTotal.WithLabelValues("weather_update")))
+	assert.Equal(t, failedBefore+1, testutil.ToFloat64(EmailsFailedTotal.WithLabelValues("weather_update")))
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(EmailSendDurationSeconds), 1)
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Per-provider request counters, labeled by provider name. They are
+// incremented by the chain handlers so fallback behavior is visible in
+// Prometheus.
+var (
+	ProviderRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_provider_requests_total",
+			Help: "The total number of requests per weather provider",
+		},
+		[]string{"provider"},
+	)
+
+	ProviderFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_provider_failures_total",
+			Help: "The total number of failed requests per weather provider",
+		},
+		[]string{"provider"},
+	)
+
+	ProviderLatencySeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "weather_provider_latency_seconds",
+			Help:    "Latency of weather provider requests in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 11), // 10ms up to ~10s
+		},
+		[]string{"provider"},
+	)
+)
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderCounters(t *testing.T) {
+	requestsBefore := testutil.ToFloat64(ProviderRequestsTotal.WithLabelValues("TestProvider"))
+	failuresBefore := testutil.ToFloat64(ProviderFailuresTotal.WithLabelValues("TestProvider"))
+
+	ProviderRequestsTotal.WithLabelValues("TestProvider").Inc()
+	ProviderRequestsTotal.WithLabelValues("TestProvider").Inc()
+	ProviderFailuresTotal.WithLabelValues("TestProvider").Inc()
+
+	assert.Equal(t, requestsBefore+2, testutil.ToFloat64(ProviderRequestsTotal.WithLabelValues("TestProvider")))
+	assert.Equal(t, failuresBefore+1, testutil.ToFloat64(ProviderFailuresTotal.WithLabelValues("TestProvider")))
+}
+
+func TestProviderLatencyHistogram(t *testing.T) {
+	ProviderLatencySeconds.WithLabelValues("TestProvider").Observe(0.05)
+
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(ProviderLatencySeconds), 1)
+}
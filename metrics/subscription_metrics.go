@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SubscriptionMetricsCollector holds Prometheus counters for the subscription funnel
+type SubscriptionMetricsCollector struct {
+	Created       prometheus.Counter
+	Updated       prometheus.Counter
+	Confirmed     prometheus.Counter
+	ExpiredTokens prometheus.Counter
+}
+
+// SubscriptionStats summarizes the subscription funnel, including the
+// subscribe-to-confirm conversion ratio
+type SubscriptionStats struct {
+	Created         int64   `json:"created"`
+	Updated         int64   `json:"updated"`
+	Confirmed       int64   `json:"confirmed"`
+	ExpiredTokens   int64   `json:"expired_tokens"`
+	ConversionRatio float64 `json:"conversion_ratio"`
+}
+
+var (
+	subscriptionMetrics     *SubscriptionMetrics
+	subscriptionMetricsOnce sync.Once
+)
+
+// Subscriptions returns the process-wide subscription funnel metrics, shared
+// by the subscribe, confirm, and cleanup flows. Initialization is guarded by
+// a sync.Once because the first call can come concurrently from an HTTP
+// handler and the scheduler, and promauto panics on double registration
+func Subscriptions() *SubscriptionMetrics {
+	subscriptionMetricsOnce.Do(func() {
+		subscriptionMetrics = &SubscriptionMetrics{
+			collector: &SubscriptionMetricsCollector{
+				Created: promauto.NewCounter(prometheus.CounterOpts{
+					Name: "weather_subscriptions_created_total",
+					Help: "The total number of new subscriptions created",
+				}),
+				Updated: promauto.NewCounter(prometheus.CounterOpts{
+					Name: "weather_subscriptions_updated_total",
+					Help: "The total number of existing subscriptions updated by re-subscribes",
+				}),
+				Confirmed: promauto.NewCounter(prometheus.CounterOpts{
+					Name: "weather_subscriptions_confirmed_total",
+					Help: "The total number of subscriptions confirmed",
+				}),
+				ExpiredTokens: promauto.NewCounter(prometheus.CounterOpts{
+					Name: "weather_subscription_tokens_expired_total",
+					Help: "The total number of expired subscription tokens cleaned up",
+				}),
+			},
+		}
+	})
+	return subscriptionMetrics
+}
+
+// SubscriptionMetrics tracks subscription funnel counters both in memory and
+// as Prometheus counters
+type SubscriptionMetrics struct {
+	created       int64
+	updated       int64
+	confirmed     int64
+	expiredTokens int64
+	collector     *SubscriptionMetricsCollector
+	mu            sync.RWMutex
+}
+
+// RecordCreated counts a distinct new signup
+func (m *SubscriptionMetrics) RecordCreated() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.created++
+	m.collector.Created.Inc()
+}
+
+// RecordUpdated counts a re-subscribe that updated an existing pending
+// subscription, kept separate so conversion isn't skewed
+func (m *SubscriptionMetrics) RecordUpdated() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.updated++
+	m.collector.Updated.Inc()
+}
+
+// RecordConfirmed counts a completed confirmation
+func (m *SubscriptionMetrics) RecordConfirmed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.confirmed++
+	m.collector.Confirmed.Inc()
+}
+
+// RecordExpiredTokens counts tokens removed by the cleanup job
+func (m *SubscriptionMetrics) RecordExpiredTokens(count int64) {
+	if count <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expiredTokens += count
+	m.collector.ExpiredTokens.Add(float64(count))
+}
+
+// GetStats returns a snapshot of the funnel counters
+func (m *SubscriptionMetrics) GetStats() SubscriptionStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var conversionRatio float64
+	if m.created > 0 {
+		conversionRatio = float64(m.confirmed) / float64(m.created)
+	}
+
+	return SubscriptionStats{
+		Created:         m.created,
+		Updated:         m.updated,
+		Confirmed:       m.confirmed,
+		ExpiredTokens:   m.expiredTokens,
+		ConversionRatio: conversionRatio,
+	}
+}
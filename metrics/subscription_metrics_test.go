@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionMetrics(t *testing.T) {
+	metrics := Subscriptions()
+	baseline := metrics.GetStats()
+
+	t.Run("Singleton instance", func(t *testing.T) {
+		assert.Same(t, metrics, Subscriptions())
+	})
+
+	t.Run("Funnel counters", func(t *testing.T) {
+		metrics.RecordCreated()
+		metrics.RecordCreated()
+		metrics.RecordUpdated()
+		metrics.RecordConfirmed()
+		metrics.RecordExpiredTokens(3)
+
+		stats := metrics.GetStats()
+		assert.Equal(t, baseline.Created+2, stats.Created)
+		assert.Equal(t, baseline.Updated+1, stats.Updated)
+		assert.Equal(t, baseline.Confirmed+1, stats.Confirmed)
+		assert.Equal(t, baseline.ExpiredTokens+3, stats.ExpiredTokens)
+		assert.Equal(t, float64(stats.Confirmed)/float64(stats.Created), stats.ConversionRatio)
+	})
+
+	t.Run("Non-positive expired counts ignored", func(t *testing.T) {
+		before := metrics.GetStats()
+		metrics.RecordExpiredTokens(0)
+		metrics.RecordExpiredTokens(-5)
+		assert.Equal(t, before.ExpiredTokens, metrics.GetStats().ExpiredTokens)
+	})
+}
@@ -2,30 +2,97 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
 )
 
 // Subscription represents a user's weather notification subscription
 type Subscription struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"index;not null"`
-	City      string         `json:"city" gorm:"not null"`
-	Frequency string         `json:"frequency" gorm:"not null"`
-	Confirmed bool           `json:"confirmed" gorm:"default:false"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	Email string `json:"email" gorm:"index;uniqueIndex:idx_email_city;not null"`
+
+	// City is stored in the canonical form produced by NormalizeCity, so
+	// the composite unique index on (email, city) rejects duplicates even
+	// when they arrive through spelling variants or a subscribe race that
+	// slips past the FindByEmail check. Unsubscribing removes the row for
+	// real (the model deliberately has no DeletedAt), so previously removed
+	// subscriptions never block re-subscribing
+	City      string `json:"city" gorm:"uniqueIndex:idx_email_city;not null"`
+	Frequency string `json:"frequency" gorm:"not null"`
+	Confirmed bool   `json:"confirmed" gorm:"default:false"`
+
+	// SendHour is the preferred local hour (0-23) for updates; nil keeps
+	// the default behavior of sending whenever the scheduler fires.
+	// Timezone names the IANA location SendHour is local to
+	SendHour *int   `json:"send_hour,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+
+	// Alert subscriptions (frequency "alert") are notified when conditions
+	// cross a threshold rather than on a schedule. AlertTempBelow and
+	// AlertTempAbove are °C bounds; AlertCondition matches a substring of
+	// the weather description (e.g. "storm")
+	AlertTempBelow *float64 `json:"alert_temp_below,omitempty"`
+	AlertTempAbove *float64 `json:"alert_temp_above,omitempty"`
+	AlertCondition string   `json:"alert_condition,omitempty"`
+
+	// Language is the subscriber's preferred email language ("en", "uk"
+	// or "de"); weather descriptions from providers stay untranslated
+	Language string `json:"lang,omitempty"`
+
+	// WebhookSecret keys the HMAC signature on webhook deliveries for this
+	// subscription (see providers.SignWebhookPayload); it is never serialized
+	WebhookSecret string `json:"-"`
+
+	// LastNotifiedAt records when the subscriber last received an update or
+	// alert email, for debugging delivery questions
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+
+	// Version implements optimistic locking: updates only apply when the
+	// row still carries the version the caller read
+	Version int `json:"-" gorm:"default:1"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsAlert reports whether this subscription is threshold-based rather than
+// a periodic digest
+func (s *Subscription) IsAlert() bool {
+	return s.Frequency == FrequencyAlert.String()
+}
+
+// AlertReason describes the first alert threshold the given weather crosses,
+// reporting false when none are crossed
+func (s *Subscription) AlertReason(weather *WeatherResponse) (string, bool) {
+	if weather == nil {
+		return "", false
+	}
+	if s.AlertTempBelow != nil && weather.Temperature < *s.AlertTempBelow {
+		return fmt.Sprintf("temperature %.1f°C is below your %.1f°C threshold", weather.Temperature, *s.AlertTempBelow), true
+	}
+	if s.AlertTempAbove != nil && weather.Temperature > *s.AlertTempAbove {
+		return fmt.Sprintf("temperature %.1f°C is above your %.1f°C threshold", weather.Temperature, *s.AlertTempAbove), true
+	}
+	if s.AlertCondition != "" && strings.Contains(strings.ToLower(weather.Description), strings.ToLower(s.AlertCondition)) {
+		return fmt.Sprintf("conditions match %q: %s", s.AlertCondition, weather.Description), true
+	}
+	return "", false
 }
 
-// Token represents an authentication or verification token
+// Token represents an authentication or verification token. Most tokens are
+// scoped to a single subscription via SubscriptionID; "unsubscribe_all"
+// tokens are instead scoped to every subscription for an email address, so
+// exactly one of SubscriptionID or Email is set
 type Token struct {
 	ID             uint           `json:"id" gorm:"primaryKey"`
 	Token          string         `json:"token" gorm:"uniqueIndex;not null"`
-	SubscriptionID uint           `json:"subscription_id" gorm:"index;not null"`
-	Subscription   Subscription   `json:"-" gorm:"foreignKey:SubscriptionID"`
-	Type           string         `json:"type" gorm:"not null"` // "confirmation" or "unsubscribe"
+	SubscriptionID uint           `json:"subscription_id,omitempty" gorm:"index"`
+	Email          string         `json:"email,omitempty" gorm:"index"`
+	Type           string         `json:"type" gorm:"not null"` // "confirmation", "unsubscribe", "cancel", or "unsubscribe_all"
 	ExpiresAt      time.Time      `json:"expires_at"`
 	CreatedAt      time.Time      `json:"created_at"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
@@ -36,16 +103,252 @@ type WeatherResponse struct {
 	Temperature float64 `json:"temperature"`
 	Humidity    float64 `json:"humidity"`
 	Description string  `json:"description"`
+	Unit        string  `json:"unit,omitempty"`
+
+	// ProviderObservedAt is when the upstream provider observed these
+	// conditions; nil when the provider does not report it
+	ProviderObservedAt *time.Time `json:"provider_observed_at,omitempty"`
+
+	// Stale marks a response served from an expired cache entry because
+	// every provider failed; fresh responses leave it unset
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Frequency represents how often a subscriber receives weather updates
+type Frequency string
+
+const (
+	FrequencyHourly Frequency = "hourly"
+	FrequencyDaily  Frequency = "daily"
+	FrequencyWeekly Frequency = "weekly"
+
+	// FrequencyAlert subscribers are notified when conditions cross their
+	// thresholds instead of on a fixed schedule
+	FrequencyAlert Frequency = "alert"
+)
+
+// IsValid reports whether the frequency is a supported cadence
+func (f Frequency) IsValid() bool {
+	switch f {
+	case FrequencyHourly, FrequencyDaily, FrequencyWeekly, FrequencyAlert:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f Frequency) String() string {
+	return string(f)
+}
+
+// FrequencyFromString parses a frequency value, reporting whether it is valid
+func FrequencyFromString(s string) (Frequency, bool) {
+	f := Frequency(s)
+	return f, f.IsValid()
+}
+
+// NormalizeCity canonicalizes a user-entered city name so "london",
+// " London " and "LONDON" refer to the same city: surrounding whitespace is
+// trimmed, internal runs of whitespace collapse to a single space, and each
+// word is title-cased. Works on unicode names ("київ" becomes "Київ")
+func NormalizeCity(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToTitle(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// Language represents a subscriber's preferred email language
+type Language string
+
+const (
+	LanguageEnglish   Language = "en"
+	LanguageUkrainian Language = "uk"
+	LanguageGerman    Language = "de"
+)
+
+func (l Language) String() string {
+	return string(l)
+}
+
+// ParseLanguage validates a language value; an empty value defaults to English
+func ParseLanguage(s string) (Language, bool) {
+	switch s {
+	case "", string(LanguageEnglish):
+		return LanguageEnglish, true
+	case string(LanguageUkrainian):
+		return LanguageUkrainian, true
+	case string(LanguageGerman):
+		return LanguageGerman, true
+	default:
+		return "", false
+	}
+}
+
+// Units represents the measurement system for weather responses
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+)
+
+// ParseUnits validates a units query value; an empty value defaults to metric
+func ParseUnits(s string) (Units, bool) {
+	switch s {
+	case "", string(UnitsMetric):
+		return UnitsMetric, true
+	case string(UnitsImperial):
+		return UnitsImperial, true
+	default:
+		return "", false
+	}
+}
+
+// InUnits returns a copy of the response converted to the requested units,
+// with the chosen unit recorded so clients know what they got
+func (w WeatherResponse) InUnits(u Units) WeatherResponse {
+	converted := w
+	converted.Unit = string(u)
+	if u == UnitsImperial {
+		converted.Temperature = w.Temperature*9/5 + 32
+	}
+	return converted
+}
+
+// ForecastDay represents a single day's forecast entry
+type ForecastDay struct {
+	Date        string  `json:"date"`
+	MinTemp     float64 `json:"min_temp"`
+	MaxTemp     float64 `json:"max_temp"`
+	Humidity    float64 `json:"humidity"`
+	Description string  `json:"description"`
+}
+
+// ForecastResponse represents multi-day forecast data returned from the API
+type ForecastResponse struct {
+	City string        `json:"city"`
+	Days []ForecastDay `json:"days"`
 }
 
 // SubscriptionRequest represents data required to create a subscription
 type SubscriptionRequest struct {
 	Email     string `json:"email" form:"email" binding:"required,email"`
 	City      string `json:"city" form:"city" binding:"required"`
-	Frequency string `json:"frequency" form:"frequency" binding:"required,oneof=hourly daily"`
+	Frequency string `json:"frequency" form:"frequency" binding:"required,oneof=hourly daily weekly alert"`
+
+	// SendHour optionally restricts updates to a preferred local hour
+	// (0-23) in the given timezone
+	SendHour *int   `json:"send_hour" form:"send_hour"`
+	Timezone string `json:"timezone" form:"timezone"`
+
+	// Alert thresholds; at least one is required when frequency is "alert"
+	AlertTempBelow *float64 `json:"alert_temp_below" form:"alert_temp_below"`
+	AlertTempAbove *float64 `json:"alert_temp_above" form:"alert_temp_above"`
+	AlertCondition string   `json:"alert_condition" form:"alert_condition"`
+
+	// Lang is the preferred email language; empty defaults to English
+	Lang string `json:"lang" form:"lang"`
+}
+
+// HasAlertThreshold reports whether at least one alert threshold is set
+func (r *SubscriptionRequest) HasAlertThreshold() bool {
+	return r.AlertTempBelow != nil || r.AlertTempAbove != nil || r.AlertCondition != ""
+}
+
+// IdempotencyKey records the response to a processed subscribe request so
+// client retries carrying the same key can be answered with the original
+// response instead of re-running the subscription. Keys are scoped per
+// email+city to avoid collisions between unrelated requests
+type IdempotencyKey struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	Key        string         `json:"key" gorm:"uniqueIndex:idx_idempotency_scope;not null"`
+	Email      string         `json:"email" gorm:"uniqueIndex:idx_idempotency_scope;not null"`
+	City       string         `json:"city" gorm:"uniqueIndex:idx_idempotency_scope;not null"`
+	StatusCode int            `json:"status_code" gorm:"not null"`
+	Body       string         `json:"body" gorm:"not null"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// FailedEmail is a dead-letter record of an email that failed all retries,
+// kept so operators can inspect and re-drive failures
+type FailedEmail struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	To        string         `json:"to" gorm:"column:to_address;index;not null"`
+	Subject   string         `json:"subject" gorm:"not null"`
+	Reason    string         `json:"reason" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// NotificationRun records that a weather update run started for a frequency
+// within a send window. The unique (frequency, window_start) pair lets a
+// restarted scheduler recognize a window that is already in progress
+type NotificationRun struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Frequency   string    `json:"frequency" gorm:"uniqueIndex:idx_notification_runs_window;not null"`
+	WindowStart time.Time `json:"window_start" gorm:"uniqueIndex:idx_notification_runs_window;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WeatherObservation is a stored snapshot of a successful weather fetch,
+// recorded when observation history is enabled and served by the history
+// endpoint
+type WeatherObservation struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	City        string    `json:"city" gorm:"index;not null"`
+	Temperature float64   `json:"temperature"`
+	Humidity    float64   `json:"humidity"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"observed_at"`
+}
+
+// UnsubscribeAllRequest represents a request to unsubscribe an email address
+// from every city it is subscribed to
+type UnsubscribeAllRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email"`
+}
+
+// BatchWeatherRequest represents a request for weather in multiple cities
+type BatchWeatherRequest struct {
+	Cities []string `json:"cities" binding:"required"`
+}
+
+// BatchWeatherEntry holds the outcome for a single city in a batch request;
+// exactly one of Weather or Error is set
+type BatchWeatherEntry struct {
+	Weather *WeatherResponse `json:"weather,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// BatchWeatherResponse maps each requested city to its result
+type BatchWeatherResponse struct {
+	Results map[string]BatchWeatherEntry `json:"results"`
+}
+
+// ErrorDetail names the request field a validation error refers to, with a
+// machine-readable code so clients do not have to parse the message
+type ErrorDetail struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
 }
 
 // ErrorResponse represents an error message structure for API responses
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string        `json:"error"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// SubscribeResponse represents the subscribe endpoint response, echoing the
+// created or updated subscription so clients can render confirmation state
+type SubscribeResponse struct {
+	Message             string `json:"message"`
+	City                string `json:"city"`
+	Frequency           string `json:"frequency"`
+	ConfirmationPending bool   `json:"confirmation_pending"`
 }
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnits(t *testing.T) {
+	units, ok := ParseUnits("")
+	assert.True(t, ok)
+	assert.Equal(t, UnitsMetric, units)
+
+	units, ok = ParseUnits("metric")
+	assert.True(t, ok)
+	assert.Equal(t, UnitsMetric, units)
+
+	units, ok = ParseUnits("imperial")
+	assert.True(t, ok)
+	assert.Equal(t, UnitsImperial, units)
+
+	_, ok = ParseUnits("kelvin")
+	assert.False(t, ok)
+}
+
+func TestWeatherResponse_InUnits(t *testing.T) {
+	weather := WeatherResponse{Temperature: 20.0, Humidity: 50.0, Description: "Sunny"}
+
+	metric := weather.InUnits(UnitsMetric)
+	assert.Equal(t, 20.0, metric.Temperature)
+	assert.Equal(t, "metric", metric.Unit)
+
+	imperial := weather.InUnits(UnitsImperial)
+	assert.Equal(t, 68.0, imperial.Temperature)
+	assert.Equal(t, 50.0, imperial.Humidity)
+	assert.Equal(t, "imperial", imperial.Unit)
+
+	// Original response must not be mutated
+	assert.Equal(t, 20.0, weather.Temperature)
+	assert.Empty(t, weather.Unit)
+}
+
+func TestNormalizeCity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"london", "London"},
+		{" London ", "London"},
+		{"LONDON", "London"},
+		{"new   york", "New York"},
+		{"  київ  ", "Київ"},
+		{"ЛЬВІВ", "Львів"},
+		{"são paulo", "São Paulo"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeCity(tt.input), "input %q", tt.input)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	lang, ok := ParseLanguage("")
+	assert.True(t, ok)
+	assert.Equal(t, LanguageEnglish, lang)
+
+	for _, valid := range []string{"en", "uk", "de"} {
+		lang, ok = ParseLanguage(valid)
+		assert.True(t, ok, "language %q should be valid", valid)
+		assert.Equal(t, valid, lang.String())
+	}
+
+	_, ok = ParseLanguage("fr")
+	assert.False(t, ok)
+}
+
+func TestFrequencyFromString(t *testing.T) {
+	for _, valid := range []string{"hourly", "daily", "weekly", "alert"} {
+		frequency, ok := FrequencyFromString(valid)
+		assert.True(t, ok, "frequency %q should be valid", valid)
+		assert.Equal(t, valid, frequency.String())
+	}
+
+	for _, invalid := range []string{"", "monthly", "Daily"} {
+		_, ok := FrequencyFromString(invalid)
+		assert.False(t, ok, "frequency %q should be invalid", invalid)
+	}
+}
+
+func TestSubscription_AlertReason(t *testing.T) {
+	below := -2.0
+	above := 30.0
+	weather := func(temp float64, description string) *WeatherResponse {
+		return &WeatherResponse{Temperature: temp, Description: description}
+	}
+
+	t.Run("TempBelowThreshold", func(t *testing.T) {
+		subscription := &Subscription{AlertTempBelow: &below}
+		reason, ok := subscription.AlertReason(weather(-5.0, "Snow"))
+		assert.True(t, ok)
+		assert.Contains(t, reason, "below")
+	})
+
+	t.Run("TempAboveThreshold", func(t *testing.T) {
+		subscription := &Subscription{AlertTempAbove: &above}
+		reason, ok := subscription.AlertReason(weather(35.0, "Sunny"))
+		assert.True(t, ok)
+		assert.Contains(t, reason, "above")
+	})
+
+	t.Run("ConditionMatch", func(t *testing.T) {
+		subscription := &Subscription{AlertCondition: "storm"}
+		reason, ok := subscription.AlertReason(weather(20.0, "Thunderstorm approaching"))
+		assert.True(t, ok)
+		assert.Contains(t, reason, "storm")
+	})
+
+	t.Run("NoThresholdCrossed", func(t *testing.T) {
+		subscription := &Subscription{AlertTempBelow: &below, AlertTempAbove: &above, AlertCondition: "storm"}
+		_, ok := subscription.AlertReason(weather(15.0, "Partly cloudy"))
+		assert.False(t, ok)
+	})
+
+	t.Run("NilWeather", func(t *testing.T) {
+		subscription := &Subscription{AlertTempBelow: &below}
+		_, ok := subscription.AlertReason(nil)
+		assert.False(t, ok)
+	})
+}
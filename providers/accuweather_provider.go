@@ -1,10 +1,17 @@
 package providers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"weatherapi.app/errors"
 	"weatherapi.app/models"
 )
 
@@ -12,10 +19,18 @@ const (
 	defaultHTTPTimeout = 10 * time.Second
 )
 
+// AccuWeatherProvider integrates AccuWeather's two-step API: a city is first
+// resolved to a location key via the location search endpoint, then current
+// conditions are fetched for that key. Resolved keys are cached in memory so
+// repeat lookups skip the extra search request
 type AccuWeatherProvider struct {
 	apiKey     string
-	baseURL    string
+	baseURL    string // API root; the two endpoint paths are appended
 	httpClient *http.Client
+	mockMode   bool
+
+	mu           sync.Mutex
+	locationKeys map[string]string
 }
 
 // MetricTemperature represents temperature value in metric units
@@ -30,40 +45,204 @@ type Temperature struct {
 
 // AccuWeatherResponse represents the response structure from AccuWeather API
 type AccuWeatherResponse struct {
-	Temperature      Temperature `json:"Temperature"`
-	RelativeHumidity float64     `json:"RelativeHumidity"`
-	WeatherText      string      `json:"WeatherText"`
-	Message          string      `json:"message,omitempty"`
+	Temperature      Temperature   `json:"Temperature"`
+	RelativeHumidity FlexibleFloat `json:"RelativeHumidity"`
+	WeatherText      string        `json:"WeatherText"`
+	EpochTime        int64         `json:"EpochTime"`
+	Message          string        `json:"message,omitempty"`
+}
+
+// accuWeatherLocation is the subset of the location search response the
+// provider needs
+type accuWeatherLocation struct {
+	Key string `json:"Key"`
 }
 
 func NewAccuWeatherProvider(apiKey, baseURL string) WeatherProvider {
+	return NewAccuWeatherProviderWithTimeout(apiKey, baseURL, defaultHTTPTimeout)
+}
+
+// NewAccuWeatherProviderWithTimeout creates a provider with an explicit HTTP
+// timeout. A base URL pointing directly at the currentconditions endpoint,
+// as older configurations do, is trimmed back to the API root
+func NewAccuWeatherProviderWithTimeout(apiKey, baseURL string, timeout time.Duration) WeatherProvider {
+	root := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/currentconditions/v1")
 	return &AccuWeatherProvider{
 		apiKey:  apiKey,
-		baseURL: baseURL,
+		baseURL: root,
 		httpClient: &http.Client{
-			Timeout: defaultHTTPTimeout,
+			Timeout: timeout,
 		},
+		locationKeys: make(map[string]string),
 	}
 }
 
-func (p *AccuWeatherProvider) GetCurrentWeather(city string) (*models.WeatherResponse, error) {
+// NewAccuWeatherProviderMock returns a provider serving canned data
+// regardless of city, for tests and offline development
+func NewAccuWeatherProviderMock() WeatherProvider {
+	return &AccuWeatherProvider{mockMode: true}
+}
+
+func (p *AccuWeatherProvider) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	if city == "" {
-		return nil, fmt.Errorf("accuweather: city cannot be empty")
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+
+	if p.mockMode {
+		mockResponse := &AccuWeatherResponse{
+			RelativeHumidity: 65,
+			WeatherText:      "Partly cloudy",
+		}
+		mockResponse.Temperature.Metric.Value = 22.5
+
+		return p.convert(mockResponse), nil
+	}
+
+	locationKey, err := p.locationKey(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.currentConditions(ctx, locationKey)
+}
+
+// GetForecast is not supported by the AccuWeather integration; the chain
+// falls back to the next provider
+func (p *AccuWeatherProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	return nil, ErrForecastNotSupported
+}
+
+// Capabilities reports that the AccuWeather integration serves current
+// conditions only
+func (p *AccuWeatherProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Current: true}
+}
+
+// locationKey resolves a city to its AccuWeather location key, serving
+// repeat lookups from the in-memory cache
+func (p *AccuWeatherProvider) locationKey(ctx context.Context, city string) (string, error) {
+	cacheKey := strings.ToLower(strings.TrimSpace(city))
+
+	p.mu.Lock()
+	if key, ok := p.locationKeys[cacheKey]; ok {
+		p.mu.Unlock()
+		return key, nil
+	}
+	p.mu.Unlock()
+
+	searchURL := fmt.Sprintf("%s/locations/v1/cities/search?apikey=%s&q=%s", p.baseURL, p.apiKey, url.QueryEscape(city))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, searchURL, nil)
+	if err != nil {
+		return "", errors.NewExternalAPIError("failed to build location search request", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", errors.NewExternalAPIError("failed to search location", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("close response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", p.handleHTTPError(resp)
 	}
 
-	mockResponse := &AccuWeatherResponse{
-		RelativeHumidity: 65,
-		WeatherText:      "Partly cloudy",
+	var locations []accuWeatherLocation
+	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
+		return "", errors.NewExternalAPIError("failed to decode location search response", err)
+	}
+	if len(locations) == 0 || locations[0].Key == "" {
+		return "", errors.NewNotFoundError("city not found")
 	}
-	mockResponse.Temperature.Metric.Value = 22.5
 
-	return p.convert(mockResponse), nil
+	p.mu.Lock()
+	p.locationKeys[cacheKey] = locations[0].Key
+	p.mu.Unlock()
+
+	return locations[0].Key, nil
+}
+
+// currentConditions fetches and converts the conditions for a location key
+func (p *AccuWeatherProvider) currentConditions(ctx context.Context, locationKey string) (*models.WeatherResponse, error) {
+	conditionsURL := fmt.Sprintf("%s/currentconditions/v1/%s?apikey=%s&details=true", p.baseURL, locationKey, p.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, conditionsURL, nil)
+	if err != nil {
+		return nil, errors.NewExternalAPIError("failed to build weather request", err)
+	}
+
+	cf := conditionalFetchFrom(ctx)
+	if cf != nil {
+		cf.applyTo(req)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.NewExternalAPIError("failed to get weather data", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("close response body", "error", closeErr)
+		}
+	}()
+
+	// 304 means the validators we sent still match; the caller keeps its
+	// cached response
+	if cf != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.handleHTTPError(resp)
+	}
+
+	if cf != nil {
+		cf.capture(resp)
+	}
+
+	var conditions []AccuWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&conditions); err != nil {
+		return nil, errors.NewExternalAPIError("failed to decode weather data", err)
+	}
+	if len(conditions) == 0 {
+		return nil, errors.NewExternalAPIError("accuweather returned no current conditions", nil)
+	}
+
+	return p.convert(&conditions[0]), nil
+}
+
+// handleHTTPError maps AccuWeather status codes onto the same error types
+// the other providers use. AccuWeather signals an exhausted quota with 503,
+// so it is treated like 429
+func (p *AccuWeatherProvider) handleHTTPError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return errors.NewNotFoundError("city not found")
+	case http.StatusUnauthorized:
+		return errors.NewExternalAPIError("accuweather: invalid API key", nil)
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return errors.NewRateLimitError("accuweather: rate limit exceeded", parseRetryAfter(resp))
+	default:
+		return errors.NewExternalAPIError(fmt.Sprintf("accuweather returned status code %d", resp.StatusCode), nil)
+	}
 }
 
 func (p *AccuWeatherProvider) convert(apiResp *AccuWeatherResponse) *models.WeatherResponse {
-	return &models.WeatherResponse{
+	response := &models.WeatherResponse{
 		Temperature: apiResp.Temperature.Metric.Value,
-		Humidity:    apiResp.RelativeHumidity,
+		Humidity:    float64(apiResp.RelativeHumidity),
 		Description: apiResp.WeatherText,
 	}
+
+	// EpochTime reports when the provider observed these conditions
+	if apiResp.EpochTime > 0 {
+		observedAt := time.Unix(apiResp.EpochTime, 0).UTC()
+		response.ProviderObservedAt = &observedAt
+	}
+
+	return response
 }
@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+// newAccuWeatherTestServer serves the two-step flow: the location search
+// resolves London to a fixed key and the conditions endpoint serves data for
+// that key. Search hits are counted so tests can assert the key cache works
+func newAccuWeatherTestServer(t *testing.T, searchHits *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/locations/v1/cities/search"):
+			*searchHits++
+			if r.URL.Query().Get("q") == "London" {
+				fmt.Fprint(w, `[{"Key":"328328","LocalizedName":"London"}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case r.URL.Path == "/currentconditions/v1/328328":
+			fmt.Fprint(w, `[{"Temperature":{"Metric":{"Value":18.5}},"RelativeHumidity":70,"WeatherText":"Cloudy","EpochTime":1700000000}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestAccuWeatherProvider_GetCurrentWeather(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		searchHits := 0
+		server := newAccuWeatherTestServer(t, &searchHits)
+		defer server.Close()
+
+		provider := NewAccuWeatherProvider("test-key", server.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		require.NoError(t, err)
+		assert.Equal(t, 18.5, weather.Temperature)
+		assert.Equal(t, 70.0, weather.Humidity)
+		assert.Equal(t, "Cloudy", weather.Description)
+		require.NotNil(t, weather.ProviderObservedAt)
+	})
+
+	t.Run("LocationKeyCached", func(t *testing.T) {
+		searchHits := 0
+		server := newAccuWeatherTestServer(t, &searchHits)
+		defer server.Close()
+
+		provider := NewAccuWeatherProvider("test-key", server.URL)
+
+		for i := 0; i < 3; i++ {
+			_, err := provider.GetCurrentWeather(context.Background(), "London")
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, searchHits, "repeat lookups should reuse the cached location key")
+	})
+
+	t.Run("CityNotFound", func(t *testing.T) {
+		searchHits := 0
+		server := newAccuWeatherTestServer(t, &searchHits)
+		defer server.Close()
+
+		provider := NewAccuWeatherProvider("test-key", server.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "Atlantis")
+
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+	})
+
+	t.Run("QuotaExceeded", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		provider := NewAccuWeatherProvider("test-key", server.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.RateLimitError, appErr.Type)
+		assert.Greater(t, int64(appErr.RetryAfter), int64(0))
+	})
+
+	t.Run("LegacyConditionsBaseURL", func(t *testing.T) {
+		searchHits := 0
+		server := newAccuWeatherTestServer(t, &searchHits)
+		defer server.Close()
+
+		// Older configurations point straight at the conditions endpoint
+		provider := NewAccuWeatherProvider("test-key", server.URL+"/currentconditions/v1")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		require.NoError(t, err)
+		assert.Equal(t, 18.5, weather.Temperature)
+	})
+
+	t.Run("MockMode", func(t *testing.T) {
+		provider := NewAccuWeatherProviderMock()
+		weather, err := provider.GetCurrentWeather(context.Background(), "Anywhere")
+
+		require.NoError(t, err)
+		assert.Equal(t, 22.5, weather.Temperature)
+		assert.Equal(t, 65.0, weather.Humidity)
+		assert.Equal(t, "Partly cloudy", weather.Description)
+	})
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -77,6 +78,14 @@ func TestRedisCacheBasicOperations(t *testing.T) {
 			return !found
 		}, 500*time.Millisecond, 10*time.Millisecond)
 	})
+
+	t.Run("Pool stats", func(t *testing.T) {
+		statser, ok := genericCache.(PoolStatser)
+		require.True(t, ok)
+
+		stats := statser.PoolStats()
+		assert.Greater(t, stats.TotalConns, uint32(0))
+	})
 }
 
 func TestMemoryCache(t *testing.T) {
@@ -104,3 +113,114 @@ func TestMemoryCache(t *testing.T) {
 		assert.False(t, found)
 	})
 }
+
+func TestMemoryCache_SizeStats(t *testing.T) {
+	memCache, ok := NewMemoryCache().(*MemoryCache)
+	require.True(t, ok)
+
+	ctx := context.Background()
+	memCache.Set(ctx, "city:london", []byte("payload-one"), time.Minute)
+	memCache.Set(ctx, "city:paris", []byte("pp"), time.Minute)
+
+	assert.Equal(t, 2, memCache.Len())
+
+	stats, ok := memCache.SizeStats(ctx)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), stats.Entries)
+	expected := int64(len("city:london") + len("payload-one") + len("city:paris") + len("pp"))
+	assert.Equal(t, expected, stats.ApproxBytes)
+
+	// Overwriting replaces the old entry's size instead of accumulating
+	memCache.Set(ctx, "city:london", []byte("x"), time.Minute)
+	stats, _ = memCache.SizeStats(ctx)
+	assert.Equal(t, int64(len("city:london")+1+len("city:paris")+len("pp")), stats.ApproxBytes)
+
+	memCache.Delete(ctx, "city:paris")
+	assert.Equal(t, 1, memCache.Len())
+
+	memCache.Clear(ctx)
+	assert.Equal(t, 0, memCache.Len())
+	stats, _ = memCache.SizeStats(ctx)
+	assert.Zero(t, stats.Entries)
+	assert.Zero(t, stats.ApproxBytes)
+}
+
+func TestMemoryCache_LRUEviction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("InsertingBeyondCapEvictsLeastRecentlyUsed", func(t *testing.T) {
+		memCache := NewMemoryCacheWithMaxEntries(2).(*MemoryCache)
+
+		memCache.Set(ctx, "city:london", []byte("a"), time.Minute)
+		memCache.Set(ctx, "city:paris", []byte("b"), time.Minute)
+		memCache.Set(ctx, "city:kyiv", []byte("c"), time.Minute)
+
+		assert.Equal(t, 2, memCache.Len())
+		_, found := memCache.Get(ctx, "city:london")
+		assert.False(t, found, "oldest key should have been evicted")
+		_, found = memCache.Get(ctx, "city:paris")
+		assert.True(t, found)
+		_, found = memCache.Get(ctx, "city:kyiv")
+		assert.True(t, found)
+	})
+
+	t.Run("GetUpdatesRecency", func(t *testing.T) {
+		memCache := NewMemoryCacheWithMaxEntries(2).(*MemoryCache)
+
+		memCache.Set(ctx, "city:london", []byte("a"), time.Minute)
+		memCache.Set(ctx, "city:paris", []byte("b"), time.Minute)
+
+		// Reading london makes paris the least recently used
+		_, found := memCache.Get(ctx, "city:london")
+		require.True(t, found)
+
+		memCache.Set(ctx, "city:kyiv", []byte("c"), time.Minute)
+
+		_, found = memCache.Get(ctx, "city:london")
+		assert.True(t, found, "recently read key should survive eviction")
+		_, found = memCache.Get(ctx, "city:paris")
+		assert.False(t, found)
+	})
+
+	t.Run("OverwriteDoesNotEvict", func(t *testing.T) {
+		memCache := NewMemoryCacheWithMaxEntries(2).(*MemoryCache)
+
+		memCache.Set(ctx, "city:london", []byte("a"), time.Minute)
+		memCache.Set(ctx, "city:paris", []byte("b"), time.Minute)
+		memCache.Set(ctx, "city:london", []byte("updated"), time.Minute)
+
+		assert.Equal(t, 2, memCache.Len())
+		data, found := memCache.Get(ctx, "city:london")
+		require.True(t, found)
+		assert.Equal(t, []byte("updated"), data)
+	})
+
+	t.Run("EvictionKeepsByteAccounting", func(t *testing.T) {
+		memCache := NewMemoryCacheWithMaxEntries(1).(*MemoryCache)
+
+		memCache.Set(ctx, "city:london", []byte("payload-one"), time.Minute)
+		memCache.Set(ctx, "city:paris", []byte("pp"), time.Minute)
+
+		stats, ok := memCache.SizeStats(ctx)
+		require.True(t, ok)
+		assert.Equal(t, int64(1), stats.Entries)
+		assert.Equal(t, int64(len("city:paris")+len("pp")), stats.ApproxBytes)
+	})
+
+	t.Run("ZeroCapLeavesCacheUnbounded", func(t *testing.T) {
+		memCache := NewMemoryCacheWithMaxEntries(0).(*MemoryCache)
+
+		for _, key := range []string{"city:london", "city:paris", "city:kyiv"} {
+			memCache.Set(ctx, key, []byte("x"), time.Minute)
+		}
+
+		assert.Equal(t, 3, memCache.Len())
+	})
+}
+
+func TestParseUsedMemory(t *testing.T) {
+	info := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n"
+	assert.Equal(t, int64(1048576), parseUsedMemory(info))
+	assert.Zero(t, parseUsedMemory("# Memory\r\n"))
+	assert.Zero(t, parseUsedMemory("used_memory:not-a-number\r\n"))
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// redisRetryInterval is how often the fallback cache re-attempts the Redis
+// connection after starting on the memory backend
+const redisRetryInterval = 30 * time.Second
+
+// FallbackCache serves from an in-memory cache while the Redis backend is
+// unavailable and promotes back to Redis once a periodic retry succeeds.
+// Entries cached in memory are discarded on promotion
+type FallbackCache struct {
+	mu      sync.RWMutex
+	backend GenericCache
+	config  *RedisCacheConfig
+}
+
+// NewFallbackCache starts on a memory backend and retries Redis in the
+// background
+func NewFallbackCache(config *RedisCacheConfig) *FallbackCache {
+	f := &FallbackCache{
+		backend: NewMemoryCache(),
+		config:  config,
+	}
+	go f.retryRedis()
+	return f
+}
+
+func (f *FallbackCache) retryRedis() {
+	ticker := time.NewTicker(redisRetryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		redisCache, err := NewRedisCache(f.config)
+		if err != nil {
+			slog.Debug("Redis still unavailable, staying on memory cache", "addr", f.config.Addr, "error", err)
+			continue
+		}
+
+		f.mu.Lock()
+		f.backend = redisCache
+		f.mu.Unlock()
+
+		slog.Info("Redis cache healthy again, promoted from memory fallback", "addr", f.config.Addr)
+		return
+	}
+}
+
+func (f *FallbackCache) current() GenericCache {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.backend
+}
+
+func (f *FallbackCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	return f.current().Get(ctx, key)
+}
+
+func (f *FallbackCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	f.current().Set(ctx, key, value, ttl)
+}
+
+func (f *FallbackCache) Delete(ctx context.Context, key string) {
+	f.current().Delete(ctx, key)
+}
+
+func (f *FallbackCache) Clear(ctx context.Context) {
+	f.current().Clear(ctx)
+}
+
+// Ping reports the health of whichever backend is active; the memory
+// fallback is always healthy
+func (f *FallbackCache) Ping(ctx context.Context) error {
+	if pinger, ok := f.current().(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// SizeStats reports the footprint of whichever backend is active
+func (f *FallbackCache) SizeStats(ctx context.Context) (SizeStats, bool) {
+	if sizer, ok := f.current().(Sizer); ok {
+		return sizer.SizeStats(ctx)
+	}
+	return SizeStats{}, false
+}
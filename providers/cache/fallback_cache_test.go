@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackCache_ServesFromMemory(t *testing.T) {
+	// Port 1 is never a Redis server, so the fallback stays on memory
+	fallback := NewFallbackCache(&RedisCacheConfig{
+		Addr:        "localhost:1",
+		DialTimeout: time.Second,
+	})
+
+	ctx := context.Background()
+	fallback.Set(ctx, "test:london", []byte("cached"), time.Minute)
+
+	value, found := fallback.Get(ctx, "test:london")
+	assert.True(t, found)
+	assert.Equal(t, []byte("cached"), value)
+
+	fallback.Delete(ctx, "test:london")
+	_, found = fallback.Get(ctx, "test:london")
+	assert.False(t, found)
+}
+
+func TestFallbackCache_MemoryBackendIsHealthy(t *testing.T) {
+	fallback := NewFallbackCache(&RedisCacheConfig{
+		Addr:        "localhost:1",
+		DialTimeout: time.Second,
+	})
+
+	assert.NoError(t, fallback.Ping(context.Background()))
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"sync"
@@ -21,6 +22,25 @@ type GenericCache interface {
 	Clear(ctx context.Context)
 }
 
+// Pinger is implemented by caches that can verify connectivity to their
+// backend; in-process caches have no backend and simply omit it
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SizeStats describes a cache's footprint for capacity monitoring: how many
+// entries it holds and its approximate size in bytes
+type SizeStats struct {
+	Entries     int64 `json:"entries"`
+	ApproxBytes int64 `json:"approx_bytes"`
+}
+
+// Sizer exposes entry counts and approximate memory usage; implemented by
+// backends that can measure their footprint
+type Sizer interface {
+	SizeStats(ctx context.Context) (SizeStats, bool)
+}
+
 // Cache defines the interface for weather caching operations
 type Cache interface {
 	Get(key string) (*models.WeatherResponse, bool)
@@ -39,13 +59,37 @@ type MemoryCache struct {
 	mutex  sync.RWMutex
 	ticker *time.Ticker
 	stopCh chan struct{}
+
+	// bytes approximates the footprint as the sum of key and value sizes,
+	// maintained on every mutation so reporting it stays O(1)
+	bytes int64
+
+	// maxEntries bounds the cache with least-recently-used eviction; zero
+	// leaves the cache TTL-only. order keeps keys most-recently-used first
+	// and elements maps each key to its node in order
+	maxEntries int
+	order      *list.List
+	elements   map[string]*list.Element
 }
 
 func NewMemoryCache() GenericCache {
+	return NewMemoryCacheWithMaxEntries(0)
+}
+
+// NewMemoryCacheWithMaxEntries creates a memory cache holding at most
+// maxEntries entries, evicting the least recently used one when a new key
+// would exceed the bound. Zero or negative maxEntries disables the bound
+func NewMemoryCacheWithMaxEntries(maxEntries int) GenericCache {
 	cache := &MemoryCache{
-		data:   make(map[string]cacheEntry),
-		ticker: time.NewTicker(defaultCleanupInterval),
-		stopCh: make(chan struct{}),
+		data:       make(map[string]cacheEntry),
+		ticker:     time.NewTicker(defaultCleanupInterval),
+		stopCh:     make(chan struct{}),
+		maxEntries: maxEntries,
+	}
+
+	if maxEntries > 0 {
+		cache.order = list.New()
+		cache.elements = make(map[string]*list.Element)
 	}
 
 	go cache.cleanup()
@@ -53,6 +97,10 @@ func NewMemoryCache() GenericCache {
 }
 
 func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	if c.maxEntries > 0 {
+		return c.getAndTouch(key)
+	}
+
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
@@ -68,6 +116,28 @@ func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
 	return entry.Data, true
 }
 
+// getAndTouch is the Get path for bounded caches: hits move the key to the
+// front of the recency order, which needs the write lock
+func (c *MemoryCache) getAndTouch(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.data[key]
+	if !exists {
+		return nil, false
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, false
+	}
+
+	if element, ok := c.elements[key]; ok {
+		c.order.MoveToFront(element)
+	}
+
+	return entry.Data, true
+}
+
 func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
 	if value == nil {
 		return
@@ -76,17 +146,65 @@ func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl tim
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if existing, exists := c.data[key]; exists {
+		c.bytes -= entrySize(key, existing.Data)
+	}
+
 	c.data[key] = cacheEntry{
 		Data:      value,
 		ExpiresAt: time.Now().Add(ttl),
 	}
+	c.bytes += entrySize(key, value)
+
+	if c.maxEntries > 0 {
+		if element, ok := c.elements[key]; ok {
+			c.order.MoveToFront(element)
+		} else {
+			c.elements[key] = c.order.PushFront(key)
+		}
+		for len(c.data) > c.maxEntries {
+			c.evictOldest()
+		}
+	}
+}
+
+// evictOldest removes the least recently used entry; the caller holds the
+// write lock
+func (c *MemoryCache) evictOldest() {
+	back := c.order.Back()
+	if back == nil {
+		return
+	}
+
+	key := back.Value.(string)
+	if entry, exists := c.data[key]; exists {
+		c.bytes -= entrySize(key, entry.Data)
+		delete(c.data, key)
+	}
+	c.removeFromOrder(key)
+}
+
+// removeFromOrder drops a key from the recency bookkeeping; it is a no-op
+// for unbounded caches. The caller holds the write lock
+func (c *MemoryCache) removeFromOrder(key string) {
+	if c.maxEntries <= 0 {
+		return
+	}
+	if element, ok := c.elements[key]; ok {
+		c.order.Remove(element)
+		delete(c.elements, key)
+	}
 }
 
 func (c *MemoryCache) Delete(ctx context.Context, key string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if existing, exists := c.data[key]; exists {
+		c.bytes -= entrySize(key, existing.Data)
+	}
 	delete(c.data, key)
+	c.removeFromOrder(key)
 }
 
 func (c *MemoryCache) Clear(ctx context.Context) {
@@ -94,6 +212,36 @@ func (c *MemoryCache) Clear(ctx context.Context) {
 	defer c.mutex.Unlock()
 
 	c.data = make(map[string]cacheEntry)
+	c.bytes = 0
+	if c.maxEntries > 0 {
+		c.order = list.New()
+		c.elements = make(map[string]*list.Element)
+	}
+}
+
+// entrySize approximates how much memory an entry occupies
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
+
+// Len reports how many entries the cache currently holds, including ones
+// past their TTL that the periodic cleanup has not removed yet
+func (c *MemoryCache) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return len(c.data)
+}
+
+// SizeStats reports the entry count and approximate byte footprint
+func (c *MemoryCache) SizeStats(ctx context.Context) (SizeStats, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return SizeStats{
+		Entries:     int64(len(c.data)),
+		ApproxBytes: c.bytes,
+	}, true
 }
 
 // WeatherCache wraps generic cache with weather-specific operations
@@ -161,7 +309,9 @@ func (c *MemoryCache) removeExpiredEntries() {
 	now := time.Now()
 	for key, entry := range c.data {
 		if now.After(entry.ExpiresAt) {
+			c.bytes -= entrySize(key, entry.Data)
 			delete(c.data, key)
+			c.removeFromOrder(key)
 		}
 	}
 }
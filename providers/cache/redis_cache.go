@@ -3,6 +3,8 @@ package cache
 import (
 	"context"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -20,6 +22,27 @@ type RedisCacheConfig struct {
 	DialTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+
+	// Connection pool tuning; zero values keep the go-redis defaults
+	PoolSize     int
+	MinIdleConns int
+	MaxConnAge   time.Duration
+}
+
+// PoolStats reports connection pool counters from the underlying Redis client
+type PoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+// PoolStatser exposes connection pool counters; implemented by cache
+// backends that keep a connection pool
+type PoolStatser interface {
+	PoolStats() PoolStats
 }
 
 func NewRedisCache(config *RedisCacheConfig) (GenericCache, error) {
@@ -32,6 +55,9 @@ func NewRedisCache(config *RedisCacheConfig) (GenericCache, error) {
 		DialTimeout:  config.DialTimeout,
 		ReadTimeout:  config.ReadTimeout,
 		WriteTimeout: config.WriteTimeout,
+		PoolSize:     config.PoolSize,
+		MinIdleConns: config.MinIdleConns,
+		MaxConnAge:   config.MaxConnAge,
 	})
 
 	if err := client.Ping(ctx).Err(); err != nil {
@@ -69,6 +95,56 @@ func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time
 	}
 }
 
+// Ping verifies connectivity to the Redis backend
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// PoolStats reports the go-redis connection pool counters
+func (r *RedisCache) PoolStats() PoolStats {
+	stats := r.client.PoolStats()
+	return PoolStats{
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+	}
+}
+
+// SizeStats reports the keyspace size via DBSIZE and the server's
+// used_memory from INFO memory. The byte figure covers the whole Redis
+// database, not only this cache's keys
+func (r *RedisCache) SizeStats(ctx context.Context) (SizeStats, bool) {
+	entries, err := r.client.DBSize(ctx).Result()
+	if err != nil {
+		slog.Warn("Redis DBSIZE error", "error", err)
+		return SizeStats{}, false
+	}
+
+	stats := SizeStats{Entries: entries}
+	if info, err := r.client.Info(ctx, "memory").Result(); err == nil {
+		stats.ApproxBytes = parseUsedMemory(info)
+	}
+	return stats, true
+}
+
+// parseUsedMemory extracts the used_memory byte count from an INFO memory
+// block; zero when the field is missing or malformed
+func parseUsedMemory(info string) int64 {
+	for _, line := range strings.Split(info, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "used_memory:"); ok {
+			bytes, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0
+			}
+			return bytes
+		}
+	}
+	return 0
+}
+
 func (r *RedisCache) Delete(ctx context.Context, key string) {
 	if err := r.client.Del(ctx, key).Err(); err != nil {
 		slog.Error("Redis delete error", "error", err, "key", key)
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type RedisClusterCache struct {
+	client *redis.ClusterClient
+	ctx    context.Context
+}
+
+type RedisClusterCacheConfig struct {
+	Addrs        []string
+	Password     string
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+func NewRedisClusterCache(config *RedisClusterCacheConfig) (GenericCache, error) {
+	ctx := context.Background()
+
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:        config.Addrs,
+		Password:     config.Password,
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Redis cluster cache connected successfully", "addrs", config.Addrs)
+
+	return &RedisClusterCache{
+		client: client,
+		ctx:    ctx,
+	}, nil
+}
+
+func (r *RedisClusterCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	val, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false
+		}
+		slog.Error("Redis cluster get error", "error", err, "key", key)
+		return nil, false
+	}
+
+	return []byte(val), true
+}
+
+func (r *RedisClusterCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if value == nil {
+		return
+	}
+
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		slog.Error("Redis cluster set error", "error", err, "key", key)
+	}
+}
+
+// Ping verifies connectivity to the Redis cluster backend
+func (r *RedisClusterCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *RedisClusterCache) Delete(ctx context.Context, key string) {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		slog.Error("Redis cluster delete error", "error", err, "key", key)
+	}
+}
+
+func (r *RedisClusterCache) Clear(ctx context.Context) {
+	err := r.client.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.FlushDB(ctx).Err()
+	})
+	if err != nil {
+		slog.Error("Redis cluster clear error", "error", err)
+	}
+}
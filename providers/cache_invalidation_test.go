@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestProviderManager_InvalidateCity(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := newSWRTestServer(&hits, &mu)
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithCacheTTL(time.Minute).
+		Build()
+	require.NoError(t, err)
+
+	// Populate the cache, then confirm the second lookup is served from it
+	_, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, weather.Temperature)
+
+	require.NoError(t, manager.InvalidateCity(context.Background(), "London"))
+
+	// The next lookup goes back to the provider
+	weather, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, weather.Temperature)
+}
+
+func TestProviderManager_InvalidateCity_NotCached(t *testing.T) {
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithCacheTTL(time.Minute).
+		Build()
+	require.NoError(t, err)
+
+	err = manager.InvalidateCity(context.Background(), "Atlantis")
+	require.Error(t, err)
+
+	var appErr *weathererr.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+}
+
+func TestProviderManager_InvalidateCity_CachingDisabled(t *testing.T) {
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(nil).
+		Build()
+	require.NoError(t, err)
+
+	err = manager.InvalidateCity(context.Background(), "London")
+	require.Error(t, err)
+
+	var appErr *weathererr.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+}
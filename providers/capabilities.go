@@ -0,0 +1,31 @@
+package providers
+
+// ProviderCapabilities describes which operations a weather provider can
+// structurally serve. The chain consults it before dispatching a request so
+// a hop is never wasted on a provider that cannot satisfy the operation
+type ProviderCapabilities struct {
+	// Current conditions for a city
+	Current bool `json:"current"`
+	// Multi-day forecasts
+	Forecast bool `json:"forecast"`
+	// Provider-sourced weather alerts; none of the current integrations
+	// expose these (alert emails are computed from current conditions)
+	Alerts bool `json:"alerts"`
+}
+
+// CapabilityReporter is an optional WeatherProvider capability for
+// describing which operations the provider supports. Providers without it
+// are assumed capable of everything, so an omitted reporter never hides
+// functionality — it only forgoes the skip optimization
+type CapabilityReporter interface {
+	Capabilities() ProviderCapabilities
+}
+
+// providerCapabilities resolves a provider's capabilities, assuming full
+// capability for providers that do not describe themselves
+func providerCapabilities(provider WeatherProvider) ProviderCapabilities {
+	if reporter, ok := provider.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return ProviderCapabilities{Current: true, Forecast: true, Alerts: true}
+}
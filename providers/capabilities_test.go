@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/models"
+)
+
+// capabilityStubProvider is a forecast stub that declares its own
+// capabilities and counts forecast calls so tests can assert skips
+type capabilityStubProvider struct {
+	stubForecastProvider
+	caps          ProviderCapabilities
+	weather       *models.WeatherResponse
+	forecastCalls int
+}
+
+func (s *capabilityStubProvider) Capabilities() ProviderCapabilities {
+	return s.caps
+}
+
+func (s *capabilityStubProvider) GetCurrentWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
+	return s.weather, nil
+}
+
+func (s *capabilityStubProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	s.forecastCalls++
+	return s.stubForecastProvider.GetForecast(city, days)
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	t.Run("ConcreteProviders", func(t *testing.T) {
+		weatherAPI := NewWeatherAPIProvider(&config.WeatherConfig{APIKey: "test-key", BaseURL: "https://api.example.com"})
+		assert.Equal(t, ProviderCapabilities{Current: true, Forecast: true}, providerCapabilities(weatherAPI))
+
+		openWeather := NewOpenWeatherMapProvider("test-key", "https://api.example.com")
+		assert.Equal(t, ProviderCapabilities{Current: true}, providerCapabilities(openWeather))
+
+		accuWeather := NewAccuWeatherProviderMock()
+		assert.Equal(t, ProviderCapabilities{Current: true}, providerCapabilities(accuWeather))
+	})
+
+	t.Run("SurviveDecoration", func(t *testing.T) {
+		provider := NewWeatherAPIProvider(&config.WeatherConfig{APIKey: "test-key", BaseURL: "https://api.example.com"})
+		wrapped := NewCircuitBreakerDecorator(
+			NewRetryDecorator(provider, 2, time.Millisecond),
+			CircuitBreakerOptions{},
+		)
+
+		assert.Equal(t, ProviderCapabilities{Current: true, Forecast: true}, providerCapabilities(wrapped))
+	})
+
+	t.Run("UnknownProvidersAssumedFullyCapable", func(t *testing.T) {
+		caps := providerCapabilities(&stubForecastProvider{})
+		assert.True(t, caps.Current)
+		assert.True(t, caps.Forecast)
+		assert.True(t, caps.Alerts)
+	})
+}
+
+func TestChain_ForecastSkipsIncapableProvider(t *testing.T) {
+	incapable := &capabilityStubProvider{caps: ProviderCapabilities{Current: true}}
+	supported := &stubForecastProvider{forecast: &models.ForecastResponse{
+		City: "London",
+		Days: []models.ForecastDay{{Date: "2025-01-01"}},
+	}}
+
+	chain := NewChainBuilder().
+		AddHandler(NewOpenWeatherMapHandler(incapable)).
+		AddHandler(NewWeatherAPIHandler(supported)).
+		Build()
+
+	forecast, err := chain.HandleForecast("London", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "London", forecast.City)
+	assert.Zero(t, incapable.forecastCalls, "incapable provider should be skipped, not called")
+}
+
+func TestProviderManager_GetProviderInfo_Capabilities(t *testing.T) {
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithOpenWeatherMapKey("ow-key").
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi", "openweathermap"}).
+		Build()
+	require.NoError(t, err)
+
+	info := manager.GetProviderInfo()
+	caps, ok := info["capabilities"].(map[string]ProviderCapabilities)
+	require.True(t, ok)
+	assert.Equal(t, ProviderCapabilities{Current: true, Forecast: true}, caps["weatherapi"])
+	assert.Equal(t, ProviderCapabilities{Current: true}, caps["openweathermap"])
+}
+
+// Current conditions still flow through incapable-forecast providers
+func TestChain_CapabilitiesDoNotAffectCurrentWeather(t *testing.T) {
+	provider := &capabilityStubProvider{
+		caps:    ProviderCapabilities{Current: true},
+		weather: &models.WeatherResponse{Temperature: 12.0},
+	}
+
+	chain := NewChainBuilder().
+		AddHandler(NewOpenWeatherMapHandler(provider)).
+		Build()
+
+	weather, err := chain.Handle(context.Background(), "London")
+
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, weather.Temperature)
+}
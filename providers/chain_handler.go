@@ -1,16 +1,59 @@
 package providers
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/metrics"
 	"weatherapi.app/models"
 )
 
+// defaultRateLimitBackoff is how long a provider sits out after a 429 that
+// carried no Retry-After hint
+const defaultRateLimitBackoff = 30 * time.Second
+
 type BaseWeatherHandler struct {
 	next         WeatherProviderChain
 	provider     WeatherProvider
 	providerName string
+
+	// rateLimitedUntil is when the provider's most recent 429 said it may
+	// be retried; until then the handler passes straight to the next one
+	mu               sync.Mutex
+	rateLimitedUntil time.Time
+}
+
+// rateLimited reports whether the provider is still inside the backoff
+// window from an earlier rate-limit response
+func (h *BaseWeatherHandler) rateLimited() (time.Time, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rateLimitedUntil, time.Now().Before(h.rateLimitedUntil)
+}
+
+// noteRateLimit records the retry time from a rate-limit error so later
+// requests skip the provider until the window passes
+func (h *BaseWeatherHandler) noteRateLimit(err error) {
+	var appErr *weathererr.AppError
+	if !stderrors.As(err, &appErr) || appErr.Type != weathererr.RateLimitError {
+		return
+	}
+
+	backoff := appErr.RetryAfter
+	if backoff <= 0 {
+		backoff = defaultRateLimitBackoff
+	}
+
+	h.mu.Lock()
+	h.rateLimitedUntil = time.Now().Add(backoff)
+	h.mu.Unlock()
+
+	slog.Warn("provider rate limited", "provider", h.providerName, "retryIn", backoff)
 }
 
 func NewBaseWeatherHandler(provider WeatherProvider, providerName string) *BaseWeatherHandler {
@@ -20,14 +63,36 @@ func NewBaseWeatherHandler(provider WeatherProvider, providerName string) *BaseW
 	}
 }
 
-func (h *BaseWeatherHandler) Handle(city string) (*models.WeatherResponse, error) {
+func (h *BaseWeatherHandler) Handle(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	if h.provider != nil {
-		response, err := h.provider.GetCurrentWeather(city)
+		if until, limited := h.rateLimited(); limited {
+			slog.Info("provider skipped during rate-limit backoff", "provider", h.providerName, "city", city, "until", until)
+			if h.next == nil {
+				return nil, weathererr.NewRateLimitError(
+					fmt.Sprintf("%s is rate limited", h.providerName), time.Until(until))
+			}
+			return h.next.Handle(ctx, city)
+		}
+
+		metrics.ProviderRequestsTotal.WithLabelValues(h.providerName).Inc()
+
+		start := time.Now()
+		response, err := h.provider.GetCurrentWeather(ctx, city)
+		metrics.ProviderLatencySeconds.WithLabelValues(h.providerName).Observe(time.Since(start).Seconds())
+
 		if err == nil {
 			return response, nil
 		}
 
+		// A 304 answer is not a failure; the caller reuses its cached
+		// response instead of falling through the chain
+		if stderrors.Is(err, errNotModified) {
+			return nil, err
+		}
+
+		metrics.ProviderFailuresTotal.WithLabelValues(h.providerName).Inc()
 		slog.Info("provider failed", "provider", h.providerName, "city", city, "error", err)
+		h.noteRateLimit(err)
 
 		// If this is the last handler in the chain and no next handler, return the actual error
 		if h.next == nil {
@@ -36,12 +101,60 @@ func (h *BaseWeatherHandler) Handle(city string) (*models.WeatherResponse, error
 	}
 
 	if h.next != nil {
-		return h.next.Handle(city)
+		return h.next.Handle(ctx, city)
 	}
 
 	return nil, fmt.Errorf("all weather providers failed for city: %s", city)
 }
 
+func (h *BaseWeatherHandler) HandleForecast(city string, days int) (*models.ForecastResponse, error) {
+	// Skip providers that structurally cannot serve forecasts instead of
+	// burning a request on a guaranteed not-supported answer
+	if h.provider != nil && !providerCapabilities(h.provider).Forecast {
+		slog.Debug("provider skipped: forecast not supported", "provider", h.providerName, "city", city)
+		if h.next != nil {
+			return h.next.HandleForecast(city, days)
+		}
+		return nil, ErrForecastNotSupported
+	}
+
+	if h.provider != nil {
+		if until, limited := h.rateLimited(); limited {
+			slog.Info("provider skipped during rate-limit backoff", "provider", h.providerName, "city", city, "until", until)
+			if h.next == nil {
+				return nil, weathererr.NewRateLimitError(
+					fmt.Sprintf("%s is rate limited", h.providerName), time.Until(until))
+			}
+			return h.next.HandleForecast(city, days)
+		}
+
+		metrics.ProviderRequestsTotal.WithLabelValues(h.providerName).Inc()
+
+		start := time.Now()
+		response, err := h.provider.GetForecast(city, days)
+		metrics.ProviderLatencySeconds.WithLabelValues(h.providerName).Observe(time.Since(start).Seconds())
+
+		if err == nil {
+			return response, nil
+		}
+
+		metrics.ProviderFailuresTotal.WithLabelValues(h.providerName).Inc()
+		slog.Info("provider forecast failed", "provider", h.providerName, "city", city, "error", err)
+		h.noteRateLimit(err)
+
+		// If this is the last handler in the chain and no next handler, return the actual error
+		if h.next == nil {
+			return nil, err
+		}
+	}
+
+	if h.next != nil {
+		return h.next.HandleForecast(city, days)
+	}
+
+	return nil, fmt.Errorf("all weather providers failed to provide forecast for city: %s", city)
+}
+
 func (h *BaseWeatherHandler) SetNext(handler WeatherProviderChain) {
 	h.next = handler
 }
@@ -83,6 +196,17 @@ func NewAccuWeatherHandler(provider WeatherProvider) WeatherProviderChain {
 	}
 }
 
+type StaticFileHandler struct {
+	*BaseWeatherHandler
+}
+
+func NewStaticFileHandler(provider WeatherProvider) WeatherProviderChain {
+	baseHandler := NewBaseWeatherHandler(provider, "StaticFile")
+	return &StaticFileHandler{
+		BaseWeatherHandler: baseHandler,
+	}
+}
+
 type ChainBuilder struct {
 	handlers []WeatherProviderChain
 }
@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"weatherapi.app/models"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCooldown         = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned when a provider's circuit breaker is open and
+// calls are being short-circuited
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitState represents the current state of a circuit breaker
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions configures failure tracking for a provider
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures before the
+	// breaker opens
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before allowing a
+	// half-open probe request
+	Cooldown time.Duration
+}
+
+// CircuitBreakerDecorator wraps a weather provider and short-circuits calls
+// after repeated consecutive failures, letting the chain move on immediately
+// instead of paying the full provider timeout
+type CircuitBreakerDecorator struct {
+	provider WeatherProvider
+	options  CircuitBreakerOptions
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreakerDecorator creates a circuit breaker around the given
+// provider, applying defaults for unset options
+func NewCircuitBreakerDecorator(provider WeatherProvider, opts CircuitBreakerOptions) *CircuitBreakerDecorator {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultFailureThreshold
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultCooldown
+	}
+
+	return &CircuitBreakerDecorator{
+		provider: provider,
+		options:  opts,
+	}
+}
+
+// GetCurrentWeather delegates to the wrapped provider unless the breaker is open
+func (d *CircuitBreakerDecorator) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	if err := d.allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := d.provider.GetCurrentWeather(ctx, city)
+	// A 304 from a conditional fetch means the provider is healthy and the
+	// cached response still stands; it must not trip the breaker
+	if err != nil && errors.Is(err, errNotModified) {
+		d.record(nil)
+		return nil, err
+	}
+	d.record(err)
+	return response, err
+}
+
+// GetForecast delegates to the wrapped provider unless the breaker is open.
+// Providers that simply don't support forecasts do not count as failures.
+func (d *CircuitBreakerDecorator) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	if err := d.allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := d.provider.GetForecast(city, days)
+	if err != nil && IsForecastNotSupported(err) {
+		return nil, err
+	}
+	d.record(err)
+	return response, err
+}
+
+// Capabilities reports the wrapped provider's capabilities
+func (d *CircuitBreakerDecorator) Capabilities() ProviderCapabilities {
+	return providerCapabilities(d.provider)
+}
+
+// State reports the current breaker state for operator visibility
+func (d *CircuitBreakerDecorator) State() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state == circuitOpen && time.Since(d.openedAt) >= d.options.Cooldown {
+		return circuitHalfOpen.String()
+	}
+	return d.state.String()
+}
+
+// allow checks whether a call may proceed, transitioning to half-open after
+// the cooldown has elapsed
+func (d *CircuitBreakerDecorator) allow() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state != circuitOpen {
+		return nil
+	}
+
+	if time.Since(d.openedAt) >= d.options.Cooldown {
+		d.state = circuitHalfOpen
+		return nil
+	}
+
+	return ErrCircuitOpen
+}
+
+// record updates failure tracking based on the call outcome
+func (d *CircuitBreakerDecorator) record(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err == nil {
+		d.state = circuitClosed
+		d.failures = 0
+		return
+	}
+
+	d.failures++
+	if d.failures >= d.options.FailureThreshold || d.state == circuitHalfOpen {
+		d.state = circuitOpen
+		d.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/models"
+)
+
+// countingProvider tracks how many times it was called and fails on demand
+type countingProvider struct {
+	calls    int
+	err      error
+	response *models.WeatherResponse
+}
+
+func (p *countingProvider) GetCurrentWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.response, nil
+}
+
+func (p *countingProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	p.calls++
+	return nil, ErrForecastNotSupported
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	provider := &countingProvider{err: fmt.Errorf("provider down")}
+	breaker := NewCircuitBreakerDecorator(provider, CircuitBreakerOptions{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := breaker.GetCurrentWeather(context.Background(), "London")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, "open", breaker.State())
+
+	// Further calls short-circuit without hitting the provider
+	_, err := breaker.GetCurrentWeather(context.Background(), "London")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	provider := &countingProvider{response: &models.WeatherResponse{Temperature: 10.0}}
+	breaker := NewCircuitBreakerDecorator(provider, CircuitBreakerOptions{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+	})
+
+	provider.err = fmt.Errorf("provider down")
+	_, err := breaker.GetCurrentWeather(context.Background(), "London")
+	assert.Error(t, err)
+
+	provider.err = nil
+	_, err = breaker.GetCurrentWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, "closed", breaker.State())
+
+	// A single new failure must not re-open the breaker
+	provider.err = fmt.Errorf("provider down")
+	_, err = breaker.GetCurrentWeather(context.Background(), "London")
+	assert.Error(t, err)
+	assert.Equal(t, "closed", breaker.State())
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	provider := &countingProvider{err: fmt.Errorf("provider down")}
+	breaker := NewCircuitBreakerDecorator(provider, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         10 * time.Millisecond,
+	})
+
+	_, err := breaker.GetCurrentWeather(context.Background(), "London")
+	assert.Error(t, err)
+	assert.Equal(t, "open", breaker.State())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, "half-open", breaker.State())
+
+	// A successful probe closes the breaker again
+	provider.err = nil
+	provider.response = &models.WeatherResponse{Temperature: 10.0}
+	_, err = breaker.GetCurrentWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, "closed", breaker.State())
+}
+
+func TestCircuitBreaker_ForecastNotSupportedIsNotFailure(t *testing.T) {
+	provider := &countingProvider{}
+	breaker := NewCircuitBreakerDecorator(provider, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+	})
+
+	_, err := breaker.GetForecast("London", 1)
+	assert.ErrorIs(t, err, ErrForecastNotSupported)
+	assert.Equal(t, "closed", breaker.State())
+}
@@ -0,0 +1,53 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// errNotModified reports that the upstream answered 304 to a conditional
+// request, so the previously cached response is still current
+var errNotModified = errors.New("upstream response not modified")
+
+// conditionalFetch carries the upstream validators of a cached response into
+// a provider call and collects the ones from the fresh response. Providers
+// that emit no validators leave the fields empty and the next fetch is
+// unconditional
+type conditionalFetch struct {
+	ETag         string
+	LastModified string
+}
+
+type conditionalFetchKey struct{}
+
+// withConditionalFetch attaches conditional-fetch state to the context so
+// providers deeper in the chain can send and capture validators
+func withConditionalFetch(ctx context.Context, cf *conditionalFetch) context.Context {
+	return context.WithValue(ctx, conditionalFetchKey{}, cf)
+}
+
+// conditionalFetchFrom returns the conditional-fetch state on the context,
+// or nil when the caller did not request one
+func conditionalFetchFrom(ctx context.Context) *conditionalFetch {
+	cf, _ := ctx.Value(conditionalFetchKey{}).(*conditionalFetch)
+	return cf
+}
+
+// applyTo sets If-None-Match and If-Modified-Since headers for whichever
+// validators the previous response carried
+func (cf *conditionalFetch) applyTo(req *http.Request) {
+	if cf.ETag != "" {
+		req.Header.Set("If-None-Match", cf.ETag)
+	}
+	if cf.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cf.LastModified)
+	}
+}
+
+// capture records the validators of a fresh response so the next fetch can
+// be conditional; a provider without validators clears them
+func (cf *conditionalFetch) capture(resp *http.Response) {
+	cf.ETag = resp.Header.Get("ETag")
+	cf.LastModified = resp.Header.Get("Last-Modified")
+}
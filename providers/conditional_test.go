@@ -0,0 +1,128 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+func TestWeatherAPIProvider_ConditionalFetch(t *testing.T) {
+	const etag = `"v1"`
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`)
+	}))
+	defer mockServer.Close()
+
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:  "test-key",
+		BaseURL: mockServer.URL,
+	})
+
+	// The first fetch has no validators to send and captures the response's
+	cf := &conditionalFetch{}
+	ctx := withConditionalFetch(context.Background(), cf)
+
+	weather, err := provider.GetCurrentWeather(ctx, "London")
+	require.NoError(t, err)
+	require.NotNil(t, weather)
+	assert.Equal(t, etag, cf.ETag)
+	assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", cf.LastModified)
+
+	// The second fetch sends them back and gets a 304
+	weather, err = provider.GetCurrentWeather(ctx, "London")
+	assert.Error(t, err)
+	assert.Nil(t, weather)
+	assert.True(t, errors.Is(err, errNotModified))
+}
+
+func TestWeatherAPIProvider_NoConditionalState(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		assert.Empty(t, r.Header.Get("If-Modified-Since"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`)
+	}))
+	defer mockServer.Close()
+
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:  "test-key",
+		BaseURL: mockServer.URL,
+	})
+
+	// Without conditional state on the context the request is unconditional
+	weather, err := provider.GetCurrentWeather(context.Background(), "London")
+	assert.NoError(t, err)
+	assert.NotNil(t, weather)
+}
+
+func TestProviderManager_SWRConditionalRefresh(t *testing.T) {
+	const etag = `"weather-v1"`
+
+	var mu sync.Mutex
+	fullResponses, notModified := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		fullResponses++
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`)
+	}))
+	defer server.Close()
+
+	manager := newSWRTestManager(t, server.URL, 30*time.Millisecond)
+
+	// Cold cache: full fetch stores the payload and its validators
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, weather.Temperature)
+
+	// Past the TTL the stale read triggers a background revalidation that
+	// comes back 304 and keeps the cached value
+	time.Sleep(60 * time.Millisecond)
+	weather, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, weather.Temperature)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return notModified == 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	// The 304 extended the entry's TTL, so the next read is a fresh cache
+	// hit and the upstream saw exactly one full response
+	weather, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, weather.Temperature)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, fullResponses)
+}
@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	weathererr "weatherapi.app/errors"
+)
+
+// dkimSignedHeaders lists the headers covered by the signature, in the order
+// they are hashed; headers absent from a message are skipped
+var dkimSignedHeaders = []string{"From", "To", "Subject", "MIME-Version", "Content-Type"}
+
+var wspSequence = regexp.MustCompile(`[ \t]+`)
+
+// dkimSigner prepends a DKIM-Signature header to outgoing messages using
+// rsa-sha256 with relaxed/relaxed canonicalization (RFC 6376)
+type dkimSigner struct {
+	domain   string
+	selector string
+	key      *rsa.PrivateKey
+}
+
+// newDKIMSigner loads an RSA private key in PEM form (PKCS#1 or PKCS#8) from
+// keyPath and returns a signer for the given domain and selector
+func newDKIMSigner(keyPath, domain, selector string) (*dkimSigner, error) {
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, weathererr.NewConfigurationError("failed to read DKIM private key", err)
+	}
+
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, weathererr.NewConfigurationError("DKIM private key is not PEM-encoded", nil)
+	}
+
+	key, err := parseRSAPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, weathererr.NewConfigurationError("failed to parse DKIM private key", err)
+	}
+
+	return &dkimSigner{domain: domain, selector: selector, key: key}, nil
+}
+
+// parseRSAPrivateKey accepts both PKCS#1 and PKCS#8 encodings since openssl
+// and dkim tooling produce either
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("DKIM private key must be RSA, got %T", parsed)
+	}
+	return key, nil
+}
+
+// Sign returns the message with a DKIM-Signature header prepended
+func (d *dkimSigner) Sign(message []byte) ([]byte, error) {
+	headerBlock, body, found := bytes.Cut(message, []byte("\r\n\r\n"))
+	if !found {
+		return nil, weathererr.NewEmailError("cannot sign malformed email message", nil)
+	}
+
+	headers := parseMessageHeaders(string(headerBlock))
+	bodyHash := sha256.Sum256(relaxedBody(body))
+
+	var signedNames []string
+	var canonical strings.Builder
+	for _, name := range dkimSignedHeaders {
+		value, ok := headers[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		signedNames = append(signedNames, strings.ToLower(name))
+		canonical.WriteString(relaxedHeader(name, value) + "\r\n")
+	}
+
+	dkimValue := fmt.Sprintf("v=1; a=rsa-sha256; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		d.domain, d.selector, time.Now().Unix(), strings.Join(signedNames, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	// The DKIM-Signature header itself is hashed last, with an empty b= tag
+	// and no trailing CRLF
+	canonical.WriteString(relaxedHeader("DKIM-Signature", dkimValue))
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, d.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, weathererr.NewEmailError("failed to sign email message", err)
+	}
+
+	signed := fmt.Sprintf("DKIM-Signature: %s%s\r\n", dkimValue, base64.StdEncoding.EncodeToString(signature))
+	return append([]byte(signed), message...), nil
+}
+
+// parseMessageHeaders maps lowercased header names to their unfolded values
+func parseMessageHeaders(block string) map[string]string {
+	headers := make(map[string]string)
+	var lastName string
+	for _, line := range strings.Split(block, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && lastName != "" {
+			headers[lastName] += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		lastName = strings.ToLower(strings.TrimSpace(name))
+		headers[lastName] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// relaxedHeader canonicalizes one header: lowercased name, unfolded value
+// with whitespace runs collapsed to a single space
+func relaxedHeader(name, value string) string {
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	value = wspSequence.ReplaceAllString(value, " ")
+	return strings.ToLower(name) + ":" + strings.TrimSpace(value)
+}
+
+// relaxedBody canonicalizes the body: trailing whitespace stripped per line,
+// whitespace runs collapsed, trailing empty lines removed, and a final CRLF
+// on non-empty bodies
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(wspSequence.ReplaceAllString(lines[i], " "), " ")
+	}
+
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	if end == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines[:end], "\r\n") + "\r\n")
+}
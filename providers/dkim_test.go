@@ -0,0 +1,142 @@
+package providers
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+// writeDKIMTestKey generates an RSA key and writes it as PKCS#1 PEM,
+// returning the key path and the public half for verification
+func writeDKIMTestKey(t *testing.T) (string, *rsa.PublicKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "dkim.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0o600))
+
+	return keyPath, &key.PublicKey
+}
+
+// verifyDKIMSignature recomputes the body and header hashes from the received
+// message and checks the signature against the public key
+func verifyDKIMSignature(t *testing.T, message string, pub *rsa.PublicKey) {
+	t.Helper()
+
+	lines := strings.Split(message, "\n")
+	require.True(t, strings.HasPrefix(lines[0], "DKIM-Signature: "))
+	dkimValue := strings.TrimPrefix(lines[0], "DKIM-Signature: ")
+
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(dkimValue, ";") {
+		if name, value, found := strings.Cut(strings.TrimSpace(tag), "="); found {
+			tags[name] = value
+		}
+	}
+
+	blank := -1
+	for i, line := range lines {
+		if line == "" {
+			blank = i
+			break
+		}
+	}
+	require.GreaterOrEqual(t, blank, 0)
+
+	body := strings.Join(lines[blank+1:], "\r\n")
+	bodyHash := sha256.Sum256(relaxedBody([]byte(body)))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(bodyHash[:]), tags["bh"])
+
+	headers := parseMessageHeaders(strings.Join(lines[1:blank], "\r\n"))
+	var canonical strings.Builder
+	for _, name := range strings.Split(tags["h"], ":") {
+		value, ok := headers[name]
+		require.True(t, ok, "signed header %q missing from message", name)
+		canonical.WriteString(relaxedHeader(name, value) + "\r\n")
+	}
+
+	unsignedEnd := strings.Index(dkimValue, "; b=") + len("; b=")
+	canonical.WriteString(relaxedHeader("DKIM-Signature", dkimValue[:unsignedEnd]))
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	require.NoError(t, err)
+	require.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+}
+
+func TestSMTPEmailProvider_DKIMSignsMessages(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	keyPath, pub := writeDKIMTestKey(t)
+
+	config := poolTestEmailConfig(t, server, 0)
+	config.DKIMPrivateKeyPath = keyPath
+	config.DKIMDomain = "weatherapi.app"
+	config.DKIMSelector = "weather"
+
+	provider, err := NewSMTPEmailProvider(config)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "<p>Sunny</p>", true))
+
+	message := server.lastMessageData()
+	assert.Contains(t, message, "d=weatherapi.app")
+	assert.Contains(t, message, "s=weather")
+	verifyDKIMSignature(t, message, pub)
+}
+
+func TestSMTPEmailProvider_DKIMDisabledWithoutKey(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "body", false))
+	assert.NotContains(t, server.lastMessageData(), "DKIM-Signature")
+}
+
+func TestNewSMTPEmailProvider_DKIMKeyErrors(t *testing.T) {
+	t.Run("MissingKeyFile", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		config := poolTestEmailConfig(t, server, 0)
+		config.DKIMPrivateKeyPath = filepath.Join(t.TempDir(), "missing.pem")
+		config.DKIMDomain = "weatherapi.app"
+		config.DKIMSelector = "weather"
+
+		_, err := NewSMTPEmailProvider(config)
+		require.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ConfigurationError, appErr.Type)
+	})
+
+	t.Run("NotPEMEncoded", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		keyPath := filepath.Join(t.TempDir(), "dkim.pem")
+		require.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0o600))
+
+		config := poolTestEmailConfig(t, server, 0)
+		config.DKIMPrivateKeyPath = keyPath
+		config.DKIMDomain = "weatherapi.app"
+		config.DKIMSelector = "weather"
+
+		_, err := NewSMTPEmailProvider(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not PEM-encoded")
+	})
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"weatherapi.app/config"
+)
+
+const (
+	// DefaultFetchLockTTL bounds how long a fetch lock can be held before
+	// Redis expires it, protecting against crashed holders
+	DefaultFetchLockTTL = 10 * time.Second
+
+	// DefaultFetchLockWait bounds how long a replica waits for another
+	// replica's fetch before fetching itself
+	DefaultFetchLockWait = 2 * time.Second
+
+	fetchLockPollInterval = 50 * time.Millisecond
+)
+
+// FetchLock coordinates provider fetches for a cache key across replicas so a
+// cold key is fetched once cluster-wide instead of by every replica at once
+type FetchLock interface {
+	// TryAcquire attempts to take the lock for key, returning whether the
+	// caller now holds it
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) bool
+
+	// Release gives the lock back so other replicas can acquire it
+	Release(ctx context.Context, key string)
+}
+
+// RedisFetchLock implements FetchLock with a SETNX key that Redis expires
+// after the lock TTL. Errors talking to Redis fail open: the caller is told
+// it holds the lock and proceeds with its own fetch rather than blocking on
+// a broken coordination backend
+type RedisFetchLock struct {
+	client *redis.Client
+}
+
+// NewRedisFetchLock creates a fetch lock backed by an existing Redis client
+func NewRedisFetchLock(client *redis.Client) *RedisFetchLock {
+	return &RedisFetchLock{client: client}
+}
+
+// NewRedisFetchLockFromConfig connects a dedicated Redis client for fetch
+// locking using the shared Redis settings
+func NewRedisFetchLockFromConfig(redisConfig *config.RedisConfig) (*RedisFetchLock, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         redisConfig.Addr,
+		Password:     redisConfig.Password,
+		DB:           redisConfig.DB,
+		DialTimeout:  time.Duration(redisConfig.DialTimeout) * time.Second,
+		ReadTimeout:  time.Duration(redisConfig.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(redisConfig.WriteTimeout) * time.Second,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+
+	return NewRedisFetchLock(client), nil
+}
+
+func (l *RedisFetchLock) TryAcquire(ctx context.Context, key string, ttl time.Duration) bool {
+	acquired, err := l.client.SetNX(ctx, key, "1", ttl).Result()
+	if err != nil {
+		slog.Error("fetch lock acquire error, failing open", "error", err, "key", key)
+		return true
+	}
+	return acquired
+}
+
+func (l *RedisFetchLock) Release(ctx context.Context, key string) {
+	if err := l.client.Del(ctx, key).Err(); err != nil {
+		slog.Error("fetch lock release error", "error", err, "key", key)
+	}
+}
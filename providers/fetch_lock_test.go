@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+// stubFetchLock is an in-process FetchLock for exercising the manager's
+// locking behavior without Redis
+type stubFetchLock struct {
+	acquired  bool
+	onDenied  func()
+	acquires  int
+	releases  int
+	lastKey   string
+	lastTTL   time.Duration
+	releaseCh chan struct{}
+}
+
+func (s *stubFetchLock) TryAcquire(_ context.Context, key string, ttl time.Duration) bool {
+	s.acquires++
+	s.lastKey = key
+	s.lastTTL = ttl
+	if !s.acquired && s.onDenied != nil {
+		s.onDenied()
+	}
+	return s.acquired
+}
+
+func (s *stubFetchLock) Release(_ context.Context, _ string) {
+	s.releases++
+	if s.releaseCh != nil {
+		close(s.releaseCh)
+	}
+}
+
+func newFetchLockTestManager(t *testing.T, serverURL string, lock FetchLock, wait time.Duration) *ProviderManager {
+	t.Helper()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(serverURL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		Build()
+	require.NoError(t, err)
+
+	manager.fetchLock = lock
+	manager.configuration.FetchLockWait = wait
+	return manager
+}
+
+func newFetchLockTestServer(hits *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`))
+	}))
+}
+
+func TestProviderManager_FetchLockAcquiredFetchesAndReleases(t *testing.T) {
+	hits := 0
+	server := newFetchLockTestServer(&hits)
+	defer server.Close()
+
+	lock := &stubFetchLock{acquired: true}
+	manager := newFetchLockTestManager(t, server.URL, lock, 100*time.Millisecond)
+
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, weather.Temperature)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 1, lock.acquires)
+	assert.Equal(t, 1, lock.releases)
+	assert.Equal(t, "lock:weather:london", lock.lastKey)
+	assert.Equal(t, DefaultFetchLockTTL, lock.lastTTL)
+}
+
+func TestProviderManager_FetchLockDeniedServesOtherReplicaResult(t *testing.T) {
+	hits := 0
+	server := newFetchLockTestServer(&hits)
+	defer server.Close()
+
+	lock := &stubFetchLock{acquired: false}
+	manager := newFetchLockTestManager(t, server.URL, lock, 500*time.Millisecond)
+
+	// Simulate another replica finishing its fetch while we wait on the lock
+	lock.onDenied = func() {
+		manager.instrumentedCache.Set(context.Background(), manager.generateCacheKey("London"),
+			[]byte(`{"temperature": 20.0, "humidity": 50, "description": "Cloudy"}`), time.Minute)
+	}
+
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, weather.Temperature)
+	assert.Equal(t, 0, hits)
+	assert.Equal(t, 0, lock.releases)
+}
+
+func TestProviderManager_FetchLockDeniedFailsOpen(t *testing.T) {
+	hits := 0
+	server := newFetchLockTestServer(&hits)
+	defer server.Close()
+
+	lock := &stubFetchLock{acquired: false}
+	manager := newFetchLockTestManager(t, server.URL, lock, 60*time.Millisecond)
+
+	// Nothing ever lands in the cache, so after the wait the replica
+	// fetches on its own rather than failing the request
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, weather.Temperature)
+	assert.Equal(t, 1, hits)
+}
+
+func TestRedisFetchLock(t *testing.T) {
+	lock, err := NewRedisFetchLockFromConfig(&config.RedisConfig{
+		Addr:        "localhost:6379",
+		DialTimeout: 1,
+	})
+	if err != nil {
+		t.Skipf("Redis not available, skipping test: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "lock:weather:fetch-lock-test"
+	lock.Release(ctx, key)
+
+	assert.True(t, lock.TryAcquire(ctx, key, time.Minute))
+	assert.False(t, lock.TryAcquire(ctx, key, time.Minute))
+
+	lock.Release(ctx, key)
+	assert.True(t, lock.TryAcquire(ctx, key, time.Minute))
+	lock.Release(ctx, key)
+}
@@ -0,0 +1,17 @@
+package providers
+
+import "errors"
+
+const (
+	// MaxForecastDays defines the maximum number of forecast days supported
+	MaxForecastDays = 3
+)
+
+// ErrForecastNotSupported is returned by providers that cannot serve forecasts;
+// the chain treats it like any other failure and falls back to the next provider
+var ErrForecastNotSupported = errors.New("forecast not supported by this provider")
+
+// IsForecastNotSupported reports whether the error is the not-supported sentinel
+func IsForecastNotSupported(err error) bool {
+	return errors.Is(err, ErrForecastNotSupported)
+}
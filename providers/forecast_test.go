@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/models"
+)
+
+func TestWeatherAPIProvider_GetForecast(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.String(), "/forecast.json")
+		assert.Contains(t, r.URL.String(), "q=London")
+		assert.Contains(t, r.URL.String(), "days=3")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"forecast": {
+				"forecastday": [
+					{"date": "2025-01-01", "day": {"maxtemp_c": 8.5, "mintemp_c": 2.1, "avghumidity": 80, "condition": {"text": "Cloudy"}}},
+					{"date": "2025-01-02", "day": {"maxtemp_c": 10.0, "mintemp_c": 3.0, "avghumidity": 75, "condition": {"text": "Sunny"}}},
+					{"date": "2025-01-03", "day": {"maxtemp_c": 9.0, "mintemp_c": 1.5, "avghumidity": 82, "condition": {"text": "Rain"}}}
+				]
+			}
+		}`))
+		require.NoError(t, err)
+	}))
+	defer mockServer.Close()
+
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:  "test-key",
+		BaseURL: mockServer.URL,
+	})
+
+	forecast, err := provider.GetForecast("London", 3)
+
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 3)
+	assert.Equal(t, "London", forecast.City)
+	assert.Equal(t, "2025-01-01", forecast.Days[0].Date)
+	assert.Equal(t, 8.5, forecast.Days[0].MaxTemp)
+	assert.Equal(t, 2.1, forecast.Days[0].MinTemp)
+	assert.Equal(t, 80.0, forecast.Days[0].Humidity)
+	assert.Equal(t, "Cloudy", forecast.Days[0].Description)
+}
+
+func TestWeatherAPIProvider_GetForecast_InvalidDays(t *testing.T) {
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:  "test-key",
+		BaseURL: "http://localhost",
+	})
+
+	_, err := provider.GetForecast("London", 0)
+	assert.Error(t, err)
+
+	_, err = provider.GetForecast("London", MaxForecastDays+1)
+	assert.Error(t, err)
+}
+
+func TestAccuWeatherProvider_GetForecast_NotSupported(t *testing.T) {
+	provider := NewAccuWeatherProvider("test-key", "http://localhost")
+
+	_, err := provider.GetForecast("London", 3)
+
+	assert.Error(t, err)
+	assert.True(t, IsForecastNotSupported(err))
+}
+
+// stubForecastProvider lets chain fallback tests control forecast behavior
+type stubForecastProvider struct {
+	forecast *models.ForecastResponse
+	err      error
+}
+
+func (s *stubForecastProvider) GetCurrentWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
+	return nil, s.err
+}
+
+func (s *stubForecastProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.forecast, nil
+}
+
+func TestChain_ForecastFallsBackOnNotSupported(t *testing.T) {
+	unsupported := &stubForecastProvider{err: ErrForecastNotSupported}
+	supported := &stubForecastProvider{forecast: &models.ForecastResponse{
+		City: "London",
+		Days: []models.ForecastDay{{Date: "2025-01-01"}},
+	}}
+
+	chain := NewChainBuilder().
+		AddHandler(NewAccuWeatherHandler(unsupported)).
+		AddHandler(NewWeatherAPIHandler(supported)).
+		Build()
+
+	forecast, err := chain.HandleForecast("London", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "London", forecast.City)
+}
+
+func TestChain_ForecastAllProvidersFail(t *testing.T) {
+	chain := NewChainBuilder().
+		AddHandler(NewAccuWeatherHandler(&stubForecastProvider{err: ErrForecastNotSupported})).
+		Build()
+
+	_, err := chain.HandleForecast("London", 1)
+
+	assert.Error(t, err)
+	assert.True(t, IsForecastNotSupported(err))
+}
@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"net/http"
+	"time"
+)
+
+// appVersion identifies this application in the default User-Agent
+const appVersion = "1.0"
+
+// DefaultUserAgent is sent on outbound provider requests when no custom
+// User-Agent is configured; some weather APIs reject Go's default
+const DefaultUserAgent = "weatherapi.app/" + appVersion
+
+// headerTransport decorates an http.RoundTripper so every provider request
+// carries the configured User-Agent and any extra headers
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("User-Agent", t.userAgent)
+	for name, value := range t.headers {
+		clone.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(clone)
+}
+
+// newProviderHTTPClient builds the HTTP client provider integrations share:
+// a timeout plus the configured User-Agent and extra request headers
+func newProviderHTTPClient(timeout time.Duration, userAgent string, headers map[string]string) *http.Client {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &headerTransport{
+			base:      http.DefaultTransport,
+			userAgent: userAgent,
+			headers:   headers,
+		},
+	}
+}
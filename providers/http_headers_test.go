@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+func TestWeatherAPIProvider_CustomUserAgentAndHeaders(t *testing.T) {
+	var gotUserAgent, gotClientID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		gotClientID = r.Header.Get("X-Client-Id")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:       "test-key",
+		BaseURL:      server.URL,
+		UserAgent:    "custom-agent/2.0",
+		ExtraHeaders: map[string]string{"X-Client-Id": "abc123"},
+	})
+
+	_, err := provider.GetCurrentWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom-agent/2.0", gotUserAgent)
+	assert.Equal(t, "abc123", gotClientID)
+}
+
+func TestWeatherAPIProvider_DefaultUserAgent(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewWeatherAPIProvider(&config.WeatherConfig{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+
+	_, err := provider.GetCurrentWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultUserAgent, gotUserAgent)
+}
+
+func TestOpenWeatherMapProvider_CustomUserAgentAndHeaders(t *testing.T) {
+	var gotUserAgent, gotEnv string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		gotEnv = r.Header.Get("X-Env")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"main": {"temp": 10.0, "humidity": 70}, "weather": [{"description": "clear sky"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenWeatherMapProviderWithHeaders(
+		"test-key",
+		server.URL,
+		5*time.Second,
+		"custom-agent/2.0",
+		map[string]string{"X-Env": "prod"},
+	)
+
+	_, err := provider.GetCurrentWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom-agent/2.0", gotUserAgent)
+	assert.Equal(t, "prod", gotEnv)
+}
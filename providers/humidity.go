@@ -0,0 +1,54 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlexibleFloat decodes JSON numeric values that providers variously encode
+// as integers, floats, or numeric strings
+type FlexibleFloat float64
+
+// UnmarshalJSON accepts ints, floats, and numeric strings
+func (f *FlexibleFloat) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if len(s) >= 2 && s[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return fmt.Errorf("decode numeric string: %w", err)
+		}
+		s = strings.TrimSpace(str)
+	}
+
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("parse numeric value %q: %w", s, err)
+	}
+
+	*f = FlexibleFloat(value)
+	return nil
+}
+
+// parseHumidityValue tolerantly converts a generically decoded JSON humidity
+// value (number or numeric string) to float64
+func parseHumidityValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		return parsed, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlexibleFloat_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+	}{
+		{"Integer", `64`, 64},
+		{"Float", `64.5`, 64.5},
+		{"NumericString", `"64"`, 64},
+		{"FloatString", `"64.5"`, 64.5},
+		{"StringWithSpaces", `" 64 "`, 64},
+		{"Null", `null`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value FlexibleFloat
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &value))
+			assert.Equal(t, tt.expected, float64(value))
+		})
+	}
+}
+
+func TestFlexibleFloat_UnmarshalJSON_Invalid(t *testing.T) {
+	var value FlexibleFloat
+	assert.Error(t, json.Unmarshal([]byte(`"not-a-number"`), &value))
+}
+
+func TestOpenWeatherMapResponse_HumidityVariants(t *testing.T) {
+	for _, humidity := range []string{`64`, `64.5`, `"64.5"`} {
+		payload := `{"main":{"temp":15.5,"humidity":` + humidity + `},"weather":[{"description":"cloudy"}]}`
+
+		var response OpenWeatherMapResponse
+		require.NoError(t, json.Unmarshal([]byte(payload), &response), "humidity %s", humidity)
+		assert.InDelta(t, 64, float64(response.Main.Humidity), 0.5)
+	}
+}
+
+func TestAccuWeatherResponse_HumidityVariants(t *testing.T) {
+	for _, humidity := range []string{`65`, `65.0`, `"65"`} {
+		payload := `{"Temperature":{"Metric":{"Value":22.5}},"RelativeHumidity":` + humidity + `,"WeatherText":"Sunny"}`
+
+		var response AccuWeatherResponse
+		require.NoError(t, json.Unmarshal([]byte(payload), &response), "humidity %s", humidity)
+		assert.Equal(t, 65.0, float64(response.RelativeHumidity))
+	}
+}
+
+func TestParseHumidityValue(t *testing.T) {
+	value, ok := parseHumidityValue(float64(70))
+	assert.True(t, ok)
+	assert.Equal(t, 70.0, value)
+
+	value, ok = parseHumidityValue("70.5")
+	assert.True(t, ok)
+	assert.Equal(t, 70.5, value)
+
+	_, ok = parseHumidityValue(nil)
+	assert.False(t, ok)
+
+	_, ok = parseHumidityValue("cloudy")
+	assert.False(t, ok)
+}
@@ -58,10 +58,48 @@ func (c *InstrumentedCache) Delete(ctx context.Context, key string) {
 	c.cache.Delete(ctx, key)
 }
 
+// Invalidate removes a key and reports whether it was present. The existence
+// check bypasses hit/miss metrics so admin invalidations don't skew cache
+// statistics
+func (c *InstrumentedCache) Invalidate(ctx context.Context, key string) bool {
+	_, found := c.cache.Get(ctx, key)
+	if found {
+		c.cache.Delete(ctx, key)
+	}
+	return found
+}
+
 func (c *InstrumentedCache) Clear(ctx context.Context) {
 	c.cache.Clear(ctx)
 }
 
+// Ping verifies connectivity to the cache backend; caches without a remote
+// backend are always considered healthy
+func (c *InstrumentedCache) Ping(ctx context.Context) error {
+	if pinger, ok := c.cache.(cache.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// PoolStats reports connection pool counters for backends that keep a
+// connection pool; the second return is false for backends without one
+func (c *InstrumentedCache) PoolStats() (cache.PoolStats, bool) {
+	if statser, ok := c.cache.(cache.PoolStatser); ok {
+		return statser.PoolStats(), true
+	}
+	return cache.PoolStats{}, false
+}
+
+// SizeStats reports the entry count and approximate memory footprint for
+// backends that can measure themselves; the second return is false otherwise
+func (c *InstrumentedCache) SizeStats(ctx context.Context) (cache.SizeStats, bool) {
+	if sizer, ok := c.cache.(cache.Sizer); ok {
+		return sizer.SizeStats(ctx)
+	}
+	return cache.SizeStats{}, false
+}
+
 func (c *InstrumentedCache) GetMetrics() *metrics.CacheMetrics {
 	return c.metrics
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	"weatherapi.app/metrics"
@@ -10,12 +11,14 @@ import (
 
 // WeatherProvider defines the interface for weather data providers
 type WeatherProvider interface {
-	GetCurrentWeather(city string) (*models.WeatherResponse, error)
+	GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error)
+	GetForecast(city string, days int) (*models.ForecastResponse, error)
 }
 
 // WeatherProviderChain defines the interface for Chain of Responsibility pattern
 type WeatherProviderChain interface {
-	Handle(city string) (*models.WeatherResponse, error)
+	Handle(ctx context.Context, city string) (*models.WeatherResponse, error)
+	HandleForecast(city string, days int) (*models.ForecastResponse, error)
 	SetNext(handler WeatherProviderChain)
 	GetProviderName() string
 }
@@ -35,9 +38,24 @@ type EmailProvider interface {
 	SendEmail(to, subject, body string, isHTML bool) error
 }
 
+// MultipartEmailSender is an optional EmailProvider capability for sending a
+// multipart/alternative message carrying both HTML and plain-text bodies, so
+// text-only clients still get a readable copy
+type MultipartEmailSender interface {
+	SendMultipartEmail(to, subject, htmlBody, textBody string) error
+}
+
+// CityScopedEmailSender is an optional EmailProvider capability for
+// rendering a per-message From display name that mentions the subscriber's
+// city; providers without it keep a fixed From name
+type CityScopedEmailSender interface {
+	ForCity(city string) EmailProvider
+}
+
 // WeatherManager defines the interface for weather provider management
 type WeatherManager interface {
-	GetWeather(city string) (*models.WeatherResponse, error)
+	GetWeather(ctx context.Context, city string) (*models.WeatherResponse, error)
+	GetForecast(city string, days int) (*models.ForecastResponse, error)
 }
 
 type WeatherProviderMetrics interface {
@@ -14,22 +14,43 @@ import (
 
 type FileLoggerImpl struct {
 	filePath string
+	minLevel slog.Level
 	mutex    sync.Mutex
 }
 
 func NewFileLogger(logPath string) (FileLogger, error) {
+	// Debug keeps every entry, matching the logger's historical behavior
+	return NewFileLoggerWithLevel(logPath, slog.LevelDebug)
+}
+
+// NewFileLoggerWithLevel creates a file logger that drops entries below the
+// given minimum level, mirroring slog's levels: request entries log at debug,
+// responses at info, and errors at error. A minimum of info suppresses the
+// per-request noise in production while keeping outcomes
+func NewFileLoggerWithLevel(logPath string, minLevel slog.Level) (FileLogger, error) {
 	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
 		return nil, fmt.Errorf("create log directory: %w", err)
 	}
 
 	return &FileLoggerImpl{
 		filePath: logPath,
+		minLevel: minLevel,
 	}, nil
 }
 
+// enabled reports whether entries at the given level pass the minimum
+func (l *FileLoggerImpl) enabled(level slog.Level) bool {
+	return level >= l.minLevel
+}
+
 func (l *FileLoggerImpl) LogRequest(providerName, city string) {
+	if !l.enabled(slog.LevelDebug) {
+		return
+	}
+
 	logEntry := map[string]interface{}{
 		"timestamp": time.Now().Format(time.RFC3339),
+		"level":     "debug",
 		"provider":  providerName,
 		"event":     "request",
 		"city":      city,
@@ -38,19 +59,28 @@ func (l *FileLoggerImpl) LogRequest(providerName, city string) {
 	l.writeLog(logEntry)
 }
 
-// LogResponse logs a successful weather response
+// LogResponse logs a successful weather response. A nil response (e.g. for
+// forecast requests) logs the event without the weather payload.
 func (l *FileLoggerImpl) LogResponse(providerName, city string, response *models.WeatherResponse, duration time.Duration) {
+	if !l.enabled(slog.LevelInfo) {
+		return
+	}
+
 	logEntry := map[string]interface{}{
 		"timestamp":   time.Now().Format(time.RFC3339),
+		"level":       "info",
 		"provider":    providerName,
 		"event":       "response",
 		"city":        city,
 		"duration_ms": duration.Milliseconds(),
-		"response": map[string]interface{}{
+	}
+
+	if response != nil {
+		logEntry["response"] = map[string]interface{}{
 			"temperature": response.Temperature,
 			"humidity":    response.Humidity,
 			"description": response.Description,
-		},
+		}
 	}
 
 	l.writeLog(logEntry)
@@ -58,8 +88,13 @@ func (l *FileLoggerImpl) LogResponse(providerName, city string, response *models
 
 // LogError logs an error during weather request
 func (l *FileLoggerImpl) LogError(providerName, city string, err error, duration time.Duration) {
+	if !l.enabled(slog.LevelError) {
+		return
+	}
+
 	logEntry := map[string]interface{}{
 		"timestamp":   time.Now().Format(time.RFC3339),
+		"level":       "error",
 		"provider":    providerName,
 		"event":       "error",
 		"city":        city,
@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/models"
+)
+
+func readLogLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestFileLogger_LevelFilter(t *testing.T) {
+	weather := &models.WeatherResponse{Temperature: 15.0, Humidity: 76.0, Description: "Sunny"}
+
+	t.Run("DebugKeepsEverything", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "providers.log")
+		logger, err := NewFileLoggerWithLevel(path, slog.LevelDebug)
+		require.NoError(t, err)
+
+		logger.LogRequest("weatherapi", "London")
+		logger.LogResponse("weatherapi", "London", weather, time.Millisecond)
+		logger.LogError("weatherapi", "London", assert.AnError, time.Millisecond)
+
+		lines := readLogLines(t, path)
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"event":"request"`)
+		assert.Contains(t, lines[0], `"level":"debug"`)
+		assert.Contains(t, lines[1], `"level":"info"`)
+		assert.Contains(t, lines[2], `"level":"error"`)
+	})
+
+	t.Run("InfoDropsRequestEntries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "providers.log")
+		logger, err := NewFileLoggerWithLevel(path, slog.LevelInfo)
+		require.NoError(t, err)
+
+		logger.LogRequest("weatherapi", "London")
+		logger.LogResponse("weatherapi", "London", weather, time.Millisecond)
+		logger.LogError("weatherapi", "London", assert.AnError, time.Millisecond)
+
+		lines := readLogLines(t, path)
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"event":"response"`)
+		assert.Contains(t, lines[1], `"event":"error"`)
+	})
+
+	t.Run("ErrorKeepsOnlyErrors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "providers.log")
+		logger, err := NewFileLoggerWithLevel(path, slog.LevelError)
+		require.NoError(t, err)
+
+		logger.LogRequest("weatherapi", "London")
+		logger.LogResponse("weatherapi", "London", weather, time.Millisecond)
+		logger.LogError("weatherapi", "London", assert.AnError, time.Millisecond)
+
+		lines := readLogLines(t, path)
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], `"event":"error"`)
+	})
+
+	t.Run("DefaultConstructorKeepsEverything", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "providers.log")
+		logger, err := NewFileLogger(path)
+		require.NoError(t, err)
+
+		logger.LogRequest("weatherapi", "London")
+
+		lines := readLogLines(t, path)
+		require.Len(t, lines, 1)
+	})
+}
@@ -0,0 +1,84 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestProviderManager_NegativeCacheSkipsProviders(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithNegativeCacheTTL(time.Minute).
+		Build()
+	require.NoError(t, err)
+
+	// First lookup hits the provider and caches the negative result
+	_, err = manager.GetWeather(context.Background(), "NoSuchCity")
+	assert.Error(t, err)
+	assert.Equal(t, 1, hits)
+
+	// Second lookup is served from the negative cache
+	_, err = manager.GetWeather(context.Background(), "NoSuchCity")
+	assert.Error(t, err)
+	assert.Equal(t, 1, hits)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+}
+
+func TestProviderManager_NegativeCacheDisabledByDefault(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		Build()
+	require.NoError(t, err)
+
+	_, err = manager.GetWeather(context.Background(), "NoSuchCity")
+	assert.Error(t, err)
+	_, err = manager.GetWeather(context.Background(), "NoSuchCity")
+	assert.Error(t, err)
+	assert.Equal(t, 2, hits)
+}
+
+func TestProviderManagerBuilder_NegativeCacheTTLValidation(t *testing.T) {
+	_, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithCacheTTL(time.Minute).
+		WithNegativeCacheTTL(2 * time.Minute).
+		Build()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "negative cache TTL must be shorter than cache TTL")
+}
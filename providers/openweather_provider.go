@@ -1,12 +1,14 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"weatherapi.app/errors"
 	"weatherapi.app/models"
 )
 
@@ -18,31 +20,57 @@ type OpenWeatherMapProvider struct {
 
 type OpenWeatherMapResponse struct {
 	Main struct {
-		Temp     float64 `json:"temp"`
-		Humidity float64 `json:"humidity"`
+		Temp     float64       `json:"temp"`
+		Humidity FlexibleFloat `json:"humidity"`
 	} `json:"main"`
 	Weather []struct {
 		Description string `json:"description"`
 	} `json:"weather"`
+	Dt      int64  `json:"dt"`
 	Message string `json:"message,omitempty"`
 }
 
 func NewOpenWeatherMapProvider(apiKey, baseURL string) WeatherProvider {
+	return NewOpenWeatherMapProviderWithTimeout(apiKey, baseURL, DefaultHTTPTimeout)
+}
+
+// NewOpenWeatherMapProviderWithTimeout creates a provider with an explicit HTTP timeout
+func NewOpenWeatherMapProviderWithTimeout(apiKey, baseURL string, timeout time.Duration) WeatherProvider {
+	return NewOpenWeatherMapProviderWithHeaders(apiKey, baseURL, timeout, "", nil)
+}
+
+// NewOpenWeatherMapProviderWithHeaders creates a provider with an explicit
+// timeout, User-Agent, and extra request headers
+func NewOpenWeatherMapProviderWithHeaders(apiKey, baseURL string, timeout time.Duration, userAgent string, headers map[string]string) WeatherProvider {
 	return &OpenWeatherMapProvider{
-		apiKey:  apiKey,
-		baseURL: baseURL + "/weather",
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		apiKey:     apiKey,
+		baseURL:    baseURL + "/weather",
+		httpClient: newProviderHTTPClient(timeout, userAgent, headers),
 	}
 }
 
-func (p *OpenWeatherMapProvider) GetCurrentWeather(city string) (*models.WeatherResponse, error) {
+// GetCurrentWeather retrieves weather data from the OpenWeatherMap
+// /weather endpoint
+func (p *OpenWeatherMapProvider) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+
 	url := fmt.Sprintf("%s?q=%s&appid=%s&units=metric", p.baseURL, city, p.apiKey)
 
-	resp, err := p.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("openweathermap API request failed: %w", err)
+		return nil, errors.NewExternalAPIError("failed to build weather request", err)
+	}
+
+	cf := conditionalFetchFrom(ctx)
+	if cf != nil {
+		cf.applyTo(req)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.NewExternalAPIError("failed to get weather data", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -50,30 +78,52 @@ func (p *OpenWeatherMapProvider) GetCurrentWeather(city string) (*models.Weather
 		}
 	}()
 
+	// 304 means the validators we sent still match; the caller keeps its
+	// cached response
+	if cf != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, p.handleHTTPError(resp.StatusCode)
+		return nil, p.handleHTTPError(resp)
+	}
+
+	if cf != nil {
+		cf.capture(resp)
 	}
 
 	var apiResponse OpenWeatherMapResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, fmt.Errorf("decode openweathermap response: %w", err)
+		return nil, errors.NewExternalAPIError("failed to decode weather data", err)
 	}
 
 	return p.convertToWeatherResponse(&apiResponse), nil
 }
 
-func (p *OpenWeatherMapProvider) handleHTTPError(statusCode int) error {
-	switch statusCode {
-	case http.StatusUnauthorized:
-		return fmt.Errorf("openweathermap: invalid API key")
+// GetForecast is not supported by the OpenWeatherMap integration; the chain
+// falls back to the next provider
+func (p *OpenWeatherMapProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	return nil, ErrForecastNotSupported
+}
+
+// Capabilities reports that the OpenWeatherMap integration serves current
+// conditions only
+func (p *OpenWeatherMapProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Current: true}
+}
+
+// handleHTTPError maps OpenWeatherMap status codes onto the same error types
+// the WeatherAPI provider uses so the chain treats both providers alike
+func (p *OpenWeatherMapProvider) handleHTTPError(resp *http.Response) error {
+	switch resp.StatusCode {
 	case http.StatusNotFound:
-		return fmt.Errorf("openweathermap: city not found")
+		return errors.NewNotFoundError("city not found")
+	case http.StatusUnauthorized:
+		return errors.NewExternalAPIError("openweathermap: invalid API key", nil)
 	case http.StatusTooManyRequests:
-		return fmt.Errorf("openweathermap: rate limit exceeded")
-	case http.StatusServiceUnavailable:
-		return fmt.Errorf("openweathermap: service unavailable")
+		return errors.NewRateLimitError("openweathermap: rate limit exceeded", parseRetryAfter(resp))
 	default:
-		return fmt.Errorf("openweathermap: HTTP %d error", statusCode)
+		return errors.NewExternalAPIError(fmt.Sprintf("openweathermap returned status code %d", resp.StatusCode), nil)
 	}
 }
 
@@ -83,9 +133,17 @@ func (p *OpenWeatherMapProvider) convertToWeatherResponse(apiResp *OpenWeatherMa
 		description = apiResp.Weather[0].Description
 	}
 
-	return &models.WeatherResponse{
+	response := &models.WeatherResponse{
 		Temperature: apiResp.Main.Temp,
-		Humidity:    apiResp.Main.Humidity,
+		Humidity:    float64(apiResp.Main.Humidity),
 		Description: description,
 	}
+
+	// dt reports when the provider observed these conditions
+	if apiResp.Dt > 0 {
+		observedAt := time.Unix(apiResp.Dt, 0).UTC()
+		response.ProviderObservedAt = &observedAt
+	}
+
+	return response
 }
@@ -0,0 +1,147 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestOpenWeatherMapProvider_GetCurrentWeather(t *testing.T) {
+	t.Run("ValidWeatherResponse", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Contains(t, r.URL.String(), "/weather")
+			assert.Contains(t, r.URL.String(), "q=London")
+			assert.Contains(t, r.URL.String(), "appid=test-api-key")
+			assert.Contains(t, r.URL.String(), "units=metric")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{
+				"main": {"temp": 15.5, "humidity": 76},
+				"weather": [{"description": "scattered clouds"}],
+				"dt": 1700000000
+			}`))
+			assert.NoError(t, err)
+		}))
+		defer mockServer.Close()
+
+		provider := NewOpenWeatherMapProvider("test-api-key", mockServer.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Equal(t, 15.5, weather.Temperature)
+		assert.Equal(t, 76.0, weather.Humidity)
+		assert.Equal(t, "scattered clouds", weather.Description)
+		require.NotNil(t, weather.ProviderObservedAt)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), *weather.ProviderObservedAt)
+	})
+
+	t.Run("MissingObservationTimestamp", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{
+				"main": {"temp": 10.0, "humidity": 60},
+				"weather": [{"description": "clear sky"}]
+			}`))
+			assert.NoError(t, err)
+		}))
+		defer mockServer.Close()
+
+		provider := NewOpenWeatherMapProvider("test-api-key", mockServer.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Nil(t, weather.ProviderObservedAt)
+	})
+
+	t.Run("HTTPErrorMapping", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			statusCode   int
+			expectedType weathererr.ErrorType
+		}{
+			{name: "CityNotFound", statusCode: http.StatusNotFound, expectedType: weathererr.NotFoundError},
+			{name: "InvalidAPIKey", statusCode: http.StatusUnauthorized, expectedType: weathererr.ExternalAPIError},
+			{name: "RateLimited", statusCode: http.StatusTooManyRequests, expectedType: weathererr.RateLimitError},
+			{name: "ServerError", statusCode: http.StatusInternalServerError, expectedType: weathererr.ExternalAPIError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(tt.statusCode)
+				}))
+				defer mockServer.Close()
+
+				provider := NewOpenWeatherMapProvider("test-api-key", mockServer.URL)
+				weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+				assert.Error(t, err)
+				assert.Nil(t, weather)
+
+				var appErr *weathererr.AppError
+				assert.True(t, errors.As(err, &appErr))
+				assert.Equal(t, tt.expectedType, appErr.Type)
+			})
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"main": `))
+			assert.NoError(t, err)
+		}))
+		defer mockServer.Close()
+
+		provider := NewOpenWeatherMapProvider("test-api-key", mockServer.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ExternalAPIError, appErr.Type)
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		provider := NewOpenWeatherMapProvider("test-api-key", "http://localhost")
+		weather, err := provider.GetCurrentWeather(context.Background(), "")
+
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+
+	t.Run("MissingDescription", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"main": {"temp": 5.0, "humidity": 50}, "weather": []}`))
+			assert.NoError(t, err)
+		}))
+		defer mockServer.Close()
+
+		provider := NewOpenWeatherMapProvider("test-api-key", mockServer.URL)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Equal(t, "No description", weather.Description)
+	})
+}
@@ -3,15 +3,19 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
 	"weatherapi.app/metrics"
 	"weatherapi.app/models"
 	"weatherapi.app/providers/cache"
+	"weatherapi.app/tracing"
 )
 
 type CacheType int
@@ -19,6 +23,7 @@ type CacheType int
 const (
 	CacheTypeMemory CacheType = iota
 	CacheTypeRedis
+	CacheTypeRedisCluster
 )
 
 func (c CacheType) String() string {
@@ -27,6 +32,8 @@ func (c CacheType) String() string {
 		return "memory"
 	case CacheTypeRedis:
 		return "redis"
+	case CacheTypeRedisCluster:
+		return "redis-cluster"
 	default:
 		return "unknown"
 	}
@@ -38,14 +45,53 @@ func CacheTypeFromString(s string) CacheType {
 		return CacheTypeMemory
 	case "redis":
 		return CacheTypeRedis
+	case "redis-cluster":
+		return CacheTypeRedisCluster
 	default:
 		return CacheTypeMemory
 	}
 }
 
+// CacheMode controls how cached weather entries past their TTL are served
+type CacheMode int
+
+const (
+	// CacheModeStrict treats an expired entry as a miss and fetches inline
+	CacheModeStrict CacheMode = iota
+	// CacheModeSWR serves an expired entry immediately (up to the configured
+	// max staleness) and refreshes it in the background
+	CacheModeSWR
+)
+
+func (m CacheMode) String() string {
+	switch m {
+	case CacheModeStrict:
+		return "strict"
+	case CacheModeSWR:
+		return "swr"
+	default:
+		return "unknown"
+	}
+}
+
+func CacheModeFromString(s string) CacheMode {
+	switch s {
+	case "swr":
+		return CacheModeSWR
+	default:
+		return CacheModeStrict
+	}
+}
+
+// DefaultCacheMaxStaleness bounds how far past its TTL an entry may be
+// served in stale-while-revalidate mode when no bound is configured
+const DefaultCacheMaxStaleness = 5 * time.Minute
+
 type ProviderManagerOptions struct {
 	Cache             Cache
 	InstrumentedCache *InstrumentedCache
+	FetchLock         FetchLock
+	Tracer            tracing.Tracer
 }
 
 type ProviderManager struct {
@@ -53,8 +99,17 @@ type ProviderManager struct {
 	cache             Cache
 	instrumentedCache *InstrumentedCache
 	logger            FileLogger
+	transformer       *ResponseTransformer
 	configuration     *ProviderConfiguration
 	cacheType         CacheType
+	breakers          map[string]*CircuitBreakerDecorator
+	namedHandlers     map[string]WeatherProviderChain
+	capabilities      map[string]ProviderCapabilities
+	fetchLock         FetchLock
+	inflight          flightGroup
+	refreshing        sync.Map
+	tracer            tracing.Tracer
+	tracingEnabled    bool
 }
 
 type ProviderConfiguration struct {
@@ -64,23 +119,50 @@ type ProviderConfiguration struct {
 	OpenWeatherMapBaseURL string
 	AccuWeatherKey        string
 	AccuWeatherBaseURL    string
+	AccuWeatherMockMode   bool
+	StaticFilePath        string
 	CacheTTL              time.Duration
 	LogFilePath           string
 	EnableLogging         bool
+	LogLevel              slog.Level
 	ProviderOrder         []string
 	CacheType             CacheType
 	CacheConfig           *config.CacheConfig
+	MemoryCacheMaxEntries int
+	Transform             *TransformRules
+	ProviderTimeout       time.Duration
+	CircuitBreaker        *CircuitBreakerOptions
+	NegativeCacheTTL      time.Duration
+	CacheMode             CacheMode
+	CacheMaxStaleness     time.Duration
+	ServeStaleOnError     bool
+	ProviderRetries       int
+	FetchLockTTL          time.Duration
+	FetchLockWait         time.Duration
+	ResponseBounds        *ResponseBounds
+
+	// UserAgent is sent on all outbound provider requests; ExtraHeaders adds
+	// custom headers keyed by provider name
+	UserAgent    string
+	ExtraHeaders map[string]map[string]string
 }
 
 func NewProviderManager(config *ProviderConfiguration, opts *ProviderManagerOptions) (*ProviderManager, error) {
 	manager := &ProviderManager{
 		configuration: config,
+		breakers:      make(map[string]*CircuitBreakerDecorator),
+		tracer:        tracing.NewNoopTracer(),
 	}
 
 	// Apply options if provided
 	if opts != nil {
 		manager.cache = opts.Cache
 		manager.instrumentedCache = opts.InstrumentedCache
+		manager.fetchLock = opts.FetchLock
+		if opts.Tracer != nil {
+			manager.tracer = opts.Tracer
+			manager.tracingEnabled = true
+		}
 	}
 
 	// Initialize components
@@ -102,13 +184,17 @@ func (pm *ProviderManager) initializeComponents() error {
 	}
 
 	if pm.configuration.EnableLogging {
-		logger, err := NewFileLogger(pm.configuration.LogFilePath)
+		logger, err := NewFileLoggerWithLevel(pm.configuration.LogFilePath, pm.configuration.LogLevel)
 		if err != nil {
 			return fmt.Errorf("create logger: %w", err)
 		}
 		pm.logger = logger
 	}
 
+	if pm.configuration.Transform != nil && !pm.configuration.Transform.IsZero() {
+		pm.transformer = NewResponseTransformer(*pm.configuration.Transform)
+	}
+
 	return nil
 }
 
@@ -117,7 +203,10 @@ var _ WeatherManager = (*ProviderManager)(nil)
 var _ WeatherProviderMetrics = (*ProviderManager)(nil)
 
 func (pm *ProviderManager) buildProviderChain() error {
-	providers := pm.createProviders()
+	providers, err := pm.createProviders()
+	if err != nil {
+		return err
+	}
 
 	// Fail fast if no providers are configured
 	if len(providers) == 0 {
@@ -130,10 +219,26 @@ func (pm *ProviderManager) buildProviderChain() error {
 	}
 
 	pm.primaryChain = chain
+
+	// Keep standalone handlers per provider so a lookup can be pinned to one
+	// provider without falling through the chain
+	pm.namedHandlers = make(map[string]WeatherProviderChain)
+	for name, provider := range providers {
+		if handler := pm.createHandler(name, provider); handler != nil {
+			pm.namedHandlers[name] = handler
+		}
+	}
+
+	// Record per-provider capabilities for introspection
+	pm.capabilities = make(map[string]ProviderCapabilities, len(providers))
+	for name, provider := range providers {
+		pm.capabilities[name] = providerCapabilities(provider)
+	}
+
 	return nil
 }
 
-func (pm *ProviderManager) createProviders() map[string]WeatherProvider {
+func (pm *ProviderManager) createProviders() (map[string]WeatherProvider, error) {
 	providers := make(map[string]WeatherProvider)
 
 	if weatherProvider := pm.createWeatherAPIProvider(); weatherProvider != nil {
@@ -148,7 +253,15 @@ func (pm *ProviderManager) createProviders() map[string]WeatherProvider {
 		providers["accuweather"] = accuWeatherProvider
 	}
 
-	return providers
+	if pm.configuration.StaticFilePath != "" {
+		staticProvider, err := pm.createStaticFileProvider()
+		if err != nil {
+			return nil, err
+		}
+		providers["static"] = staticProvider
+	}
+
+	return providers, nil
 }
 
 // createWeatherAPIProvider creates and configures WeatherAPI provider if API key is provided
@@ -163,17 +276,23 @@ func (pm *ProviderManager) createWeatherAPIProvider() WeatherProvider {
 	}
 
 	weatherConfig := &config.WeatherConfig{
-		APIKey:  pm.configuration.WeatherAPIKey,
-		BaseURL: baseURL,
+		APIKey:                 pm.configuration.WeatherAPIKey,
+		BaseURL:                baseURL,
+		ProviderTimeoutSeconds: int(pm.providerTimeout() / time.Second),
+		UserAgent:              pm.configuration.UserAgent,
+		ExtraHeaders:           pm.configuration.ExtraHeaders["weatherapi"],
 	}
 
 	var provider WeatherProvider = NewWeatherAPIProvider(weatherConfig)
+	provider = pm.wrapWithValidation(provider)
+	provider = pm.wrapWithRetry(provider)
+	provider = pm.wrapWithCircuitBreaker("weatherapi", provider)
 
 	if pm.configuration.EnableLogging {
 		provider = NewWeatherLoggerDecorator(provider, pm.logger, "WeatherAPI")
 	}
 
-	return provider
+	return pm.wrapWithTracing("WeatherAPI", provider)
 }
 
 // createOpenWeatherProvider creates and configures OpenWeatherMap provider if API key is provided
@@ -187,13 +306,22 @@ func (pm *ProviderManager) createOpenWeatherProvider() WeatherProvider {
 		baseURL = "https://api.openweathermap.org/data/2.5"
 	}
 
-	var provider = NewOpenWeatherMapProvider(pm.configuration.OpenWeatherMapKey, baseURL)
+	var provider = NewOpenWeatherMapProviderWithHeaders(
+		pm.configuration.OpenWeatherMapKey,
+		baseURL,
+		pm.providerTimeout(),
+		pm.configuration.UserAgent,
+		pm.configuration.ExtraHeaders["openweathermap"],
+	)
+	provider = pm.wrapWithValidation(provider)
+	provider = pm.wrapWithRetry(provider)
+	provider = pm.wrapWithCircuitBreaker("openweathermap", provider)
 
 	if pm.configuration.EnableLogging {
 		provider = NewWeatherLoggerDecorator(provider, pm.logger, "OpenWeatherMap")
 	}
 
-	return provider
+	return pm.wrapWithTracing("OpenWeatherMap", provider)
 }
 
 // createAccuWeatherProvider creates and configures AccuWeather provider if API key is provided
@@ -207,13 +335,85 @@ func (pm *ProviderManager) createAccuWeatherProvider() WeatherProvider {
 		baseURL = "http://dataservice.accuweather.com/currentconditions/v1"
 	}
 
-	var provider = NewAccuWeatherProvider(pm.configuration.AccuWeatherKey, baseURL)
+	var provider WeatherProvider
+	if pm.configuration.AccuWeatherMockMode {
+		provider = NewAccuWeatherProviderMock()
+	} else {
+		provider = NewAccuWeatherProviderWithTimeout(pm.configuration.AccuWeatherKey, baseURL, pm.providerTimeout())
+	}
+	provider = pm.wrapWithValidation(provider)
+	provider = pm.wrapWithRetry(provider)
+	provider = pm.wrapWithCircuitBreaker("accuweather", provider)
 
 	if pm.configuration.EnableLogging {
 		provider = NewWeatherLoggerDecorator(provider, pm.logger, "AccuWeather")
 	}
 
-	return provider
+	return pm.wrapWithTracing("AccuWeather", provider)
+}
+
+// createStaticFileProvider loads the static JSON provider. Unlike the API
+// providers it fails fast on a broken file, and it skips retries and circuit
+// breaking since there is no network involved
+func (pm *ProviderManager) createStaticFileProvider() (WeatherProvider, error) {
+	provider, err := NewStaticFileProvider(pm.configuration.StaticFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("create static file provider: %w", err)
+	}
+
+	if pm.configuration.EnableLogging {
+		provider = NewWeatherLoggerDecorator(provider, pm.logger, "StaticFile")
+	}
+
+	return pm.wrapWithTracing("StaticFile", provider), nil
+}
+
+// wrapWithTracing wraps a provider with per-attempt spans when a tracer is
+// configured
+func (pm *ProviderManager) wrapWithTracing(providerName string, provider WeatherProvider) WeatherProvider {
+	if !pm.tracingEnabled {
+		return provider
+	}
+	return NewWeatherTracingDecorator(provider, pm.tracer, providerName)
+}
+
+// wrapWithValidation wraps a provider with response sanity checking so
+// implausible decoded values are rejected before they reach the cache
+func (pm *ProviderManager) wrapWithValidation(provider WeatherProvider) WeatherProvider {
+	bounds := DefaultResponseBounds()
+	if pm.configuration.ResponseBounds != nil {
+		bounds = *pm.configuration.ResponseBounds
+	}
+	return NewValidationDecorator(provider, bounds)
+}
+
+// wrapWithRetry wraps a provider with transient-error retries when configured
+func (pm *ProviderManager) wrapWithRetry(provider WeatherProvider) WeatherProvider {
+	if pm.configuration.ProviderRetries <= 0 {
+		return provider
+	}
+	return NewRetryDecorator(provider, pm.configuration.ProviderRetries, defaultRetryBaseDelay)
+}
+
+// wrapWithCircuitBreaker wraps a provider with a circuit breaker when breaker
+// options are configured, tracking it for state reporting
+func (pm *ProviderManager) wrapWithCircuitBreaker(providerName string, provider WeatherProvider) WeatherProvider {
+	if pm.configuration.CircuitBreaker == nil {
+		return provider
+	}
+
+	breaker := NewCircuitBreakerDecorator(provider, *pm.configuration.CircuitBreaker)
+	pm.breakers[providerName] = breaker
+	return breaker
+}
+
+// providerTimeout returns the configured per-provider HTTP timeout,
+// falling back to the default when unset
+func (pm *ProviderManager) providerTimeout() time.Duration {
+	if pm.configuration.ProviderTimeout > 0 {
+		return pm.configuration.ProviderTimeout
+	}
+	return DefaultHTTPTimeout
 }
 
 func (pm *ProviderManager) buildChain(providers map[string]WeatherProvider) WeatherProviderChain {
@@ -239,31 +439,73 @@ func (pm *ProviderManager) createHandler(providerName string, provider WeatherPr
 		return NewOpenWeatherMapHandler(provider)
 	case "accuweather":
 		return NewAccuWeatherHandler(provider)
+	case "static":
+		return NewStaticFileHandler(provider)
 	default:
 		return nil
 	}
 }
 
-func (pm *ProviderManager) GetWeather(city string) (*models.WeatherResponse, error) {
-	if pm.instrumentedCache != nil {
-		return pm.getWeatherWithCache(city)
+func (pm *ProviderManager) GetWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	ctx, span := pm.tracer.StartSpan(ctx, "ProviderManager.GetWeather")
+	defer span.End()
+	span.SetAttribute("city", city)
+
+	// Concurrent requests for the same city share a single upstream fetch
+	// and cache-fill; different cities coalesce independently
+	response, err := pm.inflight.Do(strings.ToLower(strings.TrimSpace(city)), func() (*models.WeatherResponse, error) {
+		if pm.instrumentedCache != nil {
+			return pm.getWeatherWithCache(ctx, city)
+		}
+
+		response, err := pm.primaryChain.Handle(ctx, city)
+		if err != nil {
+			return nil, err
+		}
+		return pm.applyTransform(response), nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
 	}
-	return pm.primaryChain.Handle(city)
+	return response, nil
 }
 
-func (pm *ProviderManager) getWeatherWithCache(city string) (*models.WeatherResponse, error) {
-	cacheKey := pm.generateCacheKey(city)
+// GetWeatherFrom queries a single named provider, bypassing the fallback
+// chain and the cache; used by operators to debug one provider. The name must
+// be one of the configured providers
+func (pm *ProviderManager) GetWeatherFrom(ctx context.Context, providerName, city string) (*models.WeatherResponse, error) {
+	handler, exists := pm.namedHandlers[providerName]
+	if !exists {
+		return nil, weathererr.NewValidationError(fmt.Sprintf("unknown weather provider: %s", providerName))
+	}
+
+	response, err := handler.Handle(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	return pm.applyTransform(response), nil
+}
+
+// GetForecast retrieves a multi-day forecast through the provider chain,
+// caching results when caching is enabled
+func (pm *ProviderManager) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	if pm.instrumentedCache == nil {
+		return pm.primaryChain.HandleForecast(city, days)
+	}
+
+	cacheKey := pm.generateForecastCacheKey(city, days)
 
 	// Try cache first
 	if cachedData, found := pm.instrumentedCache.Get(context.Background(), cacheKey); found {
-		var weather models.WeatherResponse
-		if err := json.Unmarshal(cachedData, &weather); err == nil {
-			return &weather, nil
+		var forecast models.ForecastResponse
+		if err := json.Unmarshal(cachedData, &forecast); err == nil {
+			return &forecast, nil
 		}
 	}
 
 	// Cache miss - get from provider chain
-	response, err := pm.primaryChain.Handle(city)
+	response, err := pm.primaryChain.HandleForecast(city, days)
 	if err != nil {
 		return nil, err
 	}
@@ -276,8 +518,363 @@ func (pm *ProviderManager) getWeatherWithCache(city string) (*models.WeatherResp
 	return response, nil
 }
 
+func (pm *ProviderManager) generateForecastCacheKey(city string, days int) string {
+	return fmt.Sprintf("forecast:%s:%d", strings.ToLower(strings.TrimSpace(city)), days)
+}
+
+// applyTransform runs the configured response transformation, if any
+func (pm *ProviderManager) applyTransform(response *models.WeatherResponse) *models.WeatherResponse {
+	if pm.transformer == nil {
+		return response
+	}
+	return pm.transformer.Transform(response)
+}
+
+func (pm *ProviderManager) getWeatherWithCache(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	cacheKey := pm.generateCacheKey(city)
+
+	// Try cache first
+	_, cacheSpan := pm.tracer.StartSpan(ctx, "ProviderManager.cacheLookup")
+	cachedData, found := pm.instrumentedCache.Get(ctx, cacheKey)
+	cacheSpan.SetAttribute("cache.hit", found)
+	cacheSpan.End()
+	if found {
+		if weather, stale, ok := pm.inspectCachedWeather(cachedData); ok {
+			if stale {
+				pm.refreshCacheAsync(city, cacheKey)
+			}
+			return weather, nil
+		}
+	}
+
+	// A cached negative entry means the city recently failed with not-found
+	if pm.negativeCacheEnabled() {
+		if _, found := pm.instrumentedCache.Get(ctx, pm.generateNegativeCacheKey(city)); found {
+			return nil, weathererr.NewNotFoundError("city not found")
+		}
+	}
+
+	// Cache miss - coordinate the fetch across replicas when a lock is
+	// configured so a cold key is fetched once cluster-wide
+	release, acquired := pm.acquireFetchLock(cacheKey)
+	if !acquired {
+		// Another replica is already fetching; briefly poll the cache for
+		// its result, then fail open and fetch ourselves
+		if response, found := pm.waitForCachedWeather(cacheKey); found {
+			return response, nil
+		}
+	}
+	if release != nil {
+		defer release()
+	}
+
+	// Get from provider chain, capturing upstream validators so the next
+	// refresh can be a conditional request
+	cf := &conditionalFetch{}
+	response, err := pm.primaryChain.Handle(withConditionalFetch(ctx, cf), city)
+	if err != nil {
+		if stale, ok := pm.staleFallback(ctx, cacheKey, err); ok {
+			return stale, nil
+		}
+		pm.cacheNegativeResult(city, err)
+		return nil, err
+	}
+	response = pm.applyTransform(response)
+
+	// Cache the response
+	pm.storeWeatherInCache(cacheKey, response, cf)
+
+	return response, nil
+}
+
+// cachedWeatherEntry is the cache payload in stale-while-revalidate mode;
+// the timestamp lets staleness be computed after the logical TTL has passed
+type cachedWeatherEntry struct {
+	Weather  *models.WeatherResponse `json:"weather"`
+	CachedAt time.Time               `json:"cached_at"`
+
+	// Upstream validators let the next refresh be a conditional request;
+	// both stay empty for providers that emit none
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// storeWeatherInCache writes a response to the cache. In strict mode the
+// entry lives exactly one TTL; in stale-while-revalidate mode it is wrapped
+// with a timestamp, the upstream validators from the fetch, and kept around
+// for the staleness window as well
+func (pm *ProviderManager) storeWeatherInCache(cacheKey string, response *models.WeatherResponse, cf *conditionalFetch) {
+	pm.storeLastKnownGood(cacheKey, response)
+
+	if pm.swrEnabled() {
+		entry := cachedWeatherEntry{Weather: response, CachedAt: time.Now()}
+		if cf != nil {
+			entry.ETag = cf.ETag
+			entry.LastModified = cf.LastModified
+		}
+		if data, err := json.Marshal(entry); err == nil {
+			pm.instrumentedCache.Set(context.Background(), cacheKey, data, pm.configuration.CacheTTL+pm.cacheMaxStaleness())
+		}
+		return
+	}
+
+	if data, err := json.Marshal(response); err == nil {
+		pm.instrumentedCache.Set(context.Background(), cacheKey, data, pm.configuration.CacheTTL)
+	}
+}
+
+// inspectCachedWeather decodes a cached payload, reporting whether the entry
+// is usable and whether it is past its logical TTL and due a refresh
+func (pm *ProviderManager) inspectCachedWeather(data []byte) (*models.WeatherResponse, bool, bool) {
+	if !pm.swrEnabled() {
+		var weather models.WeatherResponse
+		if err := json.Unmarshal(data, &weather); err != nil {
+			return nil, false, false
+		}
+		return &weather, false, true
+	}
+
+	entry, ok := pm.decodeCachedWeatherEntry(data)
+	if !ok {
+		return nil, false, false
+	}
+
+	age := time.Since(entry.CachedAt)
+	if age > pm.configuration.CacheTTL+pm.cacheMaxStaleness() {
+		return nil, false, false
+	}
+	return entry.Weather, age > pm.configuration.CacheTTL, true
+}
+
+// decodeCachedWeatherEntry parses a stale-while-revalidate cache payload
+func (pm *ProviderManager) decodeCachedWeatherEntry(data []byte) (*cachedWeatherEntry, bool) {
+	var entry cachedWeatherEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Weather == nil || entry.CachedAt.IsZero() {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// refreshCacheAsync refreshes a stale cache entry through the provider chain
+// in the background, ensuring at most one refresh per key runs at a time
+func (pm *ProviderManager) refreshCacheAsync(city, cacheKey string) {
+	if _, alreadyRefreshing := pm.refreshing.LoadOrStore(cacheKey, struct{}{}); alreadyRefreshing {
+		return
+	}
+
+	go func() {
+		defer pm.refreshing.Delete(cacheKey)
+
+		// Revalidate with the stored upstream validators when the stale
+		// entry carries any; a 304 means the entry is still good and only
+		// its TTL needs extending
+		ctx := context.Background()
+		cf := &conditionalFetch{}
+		var cached *models.WeatherResponse
+		if data, found := pm.instrumentedCache.Get(ctx, cacheKey); found {
+			if entry, ok := pm.decodeCachedWeatherEntry(data); ok {
+				cached = entry.Weather
+				cf.ETag = entry.ETag
+				cf.LastModified = entry.LastModified
+			}
+		}
+
+		// The refresh outlives the request that triggered it
+		response, err := pm.primaryChain.Handle(withConditionalFetch(ctx, cf), city)
+		if err != nil {
+			if errors.Is(err, errNotModified) && cached != nil {
+				pm.storeWeatherInCache(cacheKey, cached, cf)
+				return
+			}
+			slog.Warn("Background cache refresh failed", "city", city, "error", err)
+			return
+		}
+		pm.storeWeatherInCache(cacheKey, pm.applyTransform(response), cf)
+	}()
+}
+
+// lastKnownGoodSuffix namespaces the degraded-fallback copy of a cache key;
+// lastKnownGoodTTL is how long that copy stays servable after every
+// provider fails
+const (
+	lastKnownGoodSuffix = ":last"
+	lastKnownGoodTTL    = 24 * time.Hour
+)
+
+// storeLastKnownGood keeps a long-lived copy of the response so a later
+// chain failure can degrade to it instead of erroring
+func (pm *ProviderManager) storeLastKnownGood(cacheKey string, response *models.WeatherResponse) {
+	if !pm.configuration.ServeStaleOnError {
+		return
+	}
+
+	entry := cachedWeatherEntry{Weather: response, CachedAt: time.Now()}
+	if data, err := json.Marshal(entry); err == nil {
+		pm.instrumentedCache.Set(context.Background(), cacheKey+lastKnownGoodSuffix, data, lastKnownGoodTTL)
+	}
+}
+
+// staleFallback serves the last known good value for the key after the whole
+// chain failed, flagging it as stale. Domain errors such as not-found and
+// validation failures are never masked by stale data
+func (pm *ProviderManager) staleFallback(ctx context.Context, cacheKey string, cause error) (*models.WeatherResponse, bool) {
+	if !pm.configuration.ServeStaleOnError {
+		return nil, false
+	}
+
+	var appErr *weathererr.AppError
+	if errors.As(cause, &appErr) &&
+		(appErr.Type == weathererr.NotFoundError || appErr.Type == weathererr.ValidationError) {
+		return nil, false
+	}
+
+	data, found := pm.instrumentedCache.Get(ctx, cacheKey+lastKnownGoodSuffix)
+	if !found {
+		return nil, false
+	}
+	entry, ok := pm.decodeCachedWeatherEntry(data)
+	if !ok {
+		return nil, false
+	}
+
+	// Copy before flagging so the cached value itself stays unmarked
+	weather := *entry.Weather
+	weather.Stale = true
+	slog.Warn("Serving stale weather after provider failure",
+		"cacheKey", cacheKey, "cachedAt", entry.CachedAt, "error", cause)
+	return &weather, true
+}
+
+func (pm *ProviderManager) swrEnabled() bool {
+	return pm.configuration.CacheMode == CacheModeSWR
+}
+
+func (pm *ProviderManager) cacheMaxStaleness() time.Duration {
+	if pm.configuration.CacheMaxStaleness > 0 {
+		return pm.configuration.CacheMaxStaleness
+	}
+	return DefaultCacheMaxStaleness
+}
+
+// warmCacheConcurrency caps how many cities are fetched at once during
+// cache warm-up
+const warmCacheConcurrency = 4
+
+// WarmCache pre-populates the cache by fetching each city through the
+// provider chain. Per-city failures are logged and skipped, and the routine
+// stops early when the context is cancelled. Without a cache configured
+// there is nothing to warm and the call is a no-op
+func (pm *ProviderManager) WarmCache(ctx context.Context, cities []string) error {
+	if pm.instrumentedCache == nil {
+		slog.Debug("Cache warm-up skipped: caching disabled")
+		return nil
+	}
+
+	slog.Info("Warming weather cache", "cities", len(cities))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, warmCacheConcurrency)
+
+loop:
+	for _, city := range cities {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(city string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := pm.getWeatherWithCache(ctx, city); err != nil {
+				slog.Warn("Cache warm-up failed for city", "city", city, "error", err)
+				return
+			}
+			slog.Debug("Cache warmed", "city", city)
+		}(city)
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// acquireFetchLock takes the distributed lock for a cache key, returning a
+// release callback and whether the lock was acquired. With no lock configured
+// the fetch proceeds unconditionally
+func (pm *ProviderManager) acquireFetchLock(cacheKey string) (func(), bool) {
+	if pm.fetchLock == nil {
+		return nil, true
+	}
+
+	lockKey := fmt.Sprintf("lock:%s", cacheKey)
+	if !pm.fetchLock.TryAcquire(context.Background(), lockKey, pm.fetchLockTTL()) {
+		return nil, false
+	}
+
+	return func() {
+		pm.fetchLock.Release(context.Background(), lockKey)
+	}, true
+}
+
+// waitForCachedWeather polls the cache while another replica holds the fetch
+// lock, returning the response once that replica publishes it
+func (pm *ProviderManager) waitForCachedWeather(cacheKey string) (*models.WeatherResponse, bool) {
+	deadline := time.Now().Add(pm.fetchLockWait())
+
+	for time.Now().Before(deadline) {
+		time.Sleep(fetchLockPollInterval)
+
+		if cachedData, found := pm.instrumentedCache.Get(context.Background(), cacheKey); found {
+			if weather, _, ok := pm.inspectCachedWeather(cachedData); ok {
+				return weather, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (pm *ProviderManager) fetchLockTTL() time.Duration {
+	if pm.configuration.FetchLockTTL > 0 {
+		return pm.configuration.FetchLockTTL
+	}
+	return DefaultFetchLockTTL
+}
+
+func (pm *ProviderManager) fetchLockWait() time.Duration {
+	if pm.configuration.FetchLockWait > 0 {
+		return pm.configuration.FetchLockWait
+	}
+	return DefaultFetchLockWait
+}
+
 func (pm *ProviderManager) generateCacheKey(city string) string {
-	return fmt.Sprintf("weather:%s", strings.ToLower(strings.TrimSpace(city)))
+	return fmt.Sprintf("weather:%s", strings.ToLower(models.NormalizeCity(city)))
+}
+
+func (pm *ProviderManager) generateNegativeCacheKey(city string) string {
+	return fmt.Sprintf("weather:notfound:%s", strings.ToLower(models.NormalizeCity(city)))
+}
+
+func (pm *ProviderManager) negativeCacheEnabled() bool {
+	return pm.instrumentedCache != nil && pm.configuration.NegativeCacheTTL > 0
+}
+
+// cacheNegativeResult stores a short-lived marker for cities that failed with
+// not-found so repeated lookups of a bad city skip the provider chain
+func (pm *ProviderManager) cacheNegativeResult(city string, err error) {
+	if !pm.negativeCacheEnabled() {
+		return
+	}
+
+	var appErr *weathererr.AppError
+	if !errors.As(err, &appErr) || appErr.Type != weathererr.NotFoundError {
+		return
+	}
+
+	pm.instrumentedCache.Set(context.Background(), pm.generateNegativeCacheKey(city), []byte("not_found"), pm.configuration.NegativeCacheTTL)
 }
 
 func (pm *ProviderManager) GetProviderInfo() map[string]interface{} {
@@ -292,29 +889,89 @@ func (pm *ProviderManager) GetProviderInfo() map[string]interface{} {
 	info["provider_order"] = pm.configuration.ProviderOrder
 	info["chain_name"] = pm.primaryChain.GetProviderName()
 
+	// Expose how many WeatherAPI keys are in rotation, never the keys
+	if keyCount := len(config.ParseAPIKeyList(pm.configuration.WeatherAPIKey)); keyCount > 0 {
+		info["weatherapi_key_count"] = keyCount
+	}
+
+	if len(pm.breakers) > 0 {
+		states := make(map[string]string, len(pm.breakers))
+		for name, breaker := range pm.breakers {
+			states[name] = breaker.State()
+		}
+		info["circuit_breakers"] = states
+	}
+
+	if len(pm.capabilities) > 0 {
+		info["capabilities"] = pm.capabilities
+	}
+
 	return info
 }
 
+// InvalidateCity removes the cached weather entry for a city so the next
+// lookup goes back to the provider chain. It returns a not-found error when
+// caching is disabled or no entry exists for the city
+func (pm *ProviderManager) InvalidateCity(ctx context.Context, city string) error {
+	if pm.instrumentedCache == nil {
+		return weathererr.NewNotFoundError("weather caching is not enabled")
+	}
+
+	if !pm.instrumentedCache.Invalidate(ctx, pm.generateCacheKey(city)) {
+		return weathererr.NewNotFoundError(fmt.Sprintf("no cached weather entry for %s", city))
+	}
+
+	slog.Info("Weather cache entry invalidated", "city", city)
+	return nil
+}
+
+// PingCache verifies connectivity to the cache backend for readiness checks;
+// managers without a cache are trivially healthy
+func (pm *ProviderManager) PingCache(ctx context.Context) error {
+	if pm.instrumentedCache == nil {
+		return nil
+	}
+	return pm.instrumentedCache.Ping(ctx)
+}
+
 func (pm *ProviderManager) GetCacheMetrics() (metrics.CacheStats, error) {
 	if pm.instrumentedCache == nil {
 		return metrics.CacheStats{}, fmt.Errorf("cache not enabled")
 	}
-	return pm.instrumentedCache.GetMetrics().GetStats(), nil
+
+	stats := pm.instrumentedCache.GetMetrics().GetStats()
+	if size, ok := pm.instrumentedCache.SizeStats(context.Background()); ok {
+		stats.Entries = size.Entries
+		stats.ApproxBytes = size.ApproxBytes
+	}
+	return stats, nil
+}
+
+// GetCachePoolStats reports Redis connection pool counters; the second
+// return is false when caching is disabled or the backend has no pool
+func (pm *ProviderManager) GetCachePoolStats() (cache.PoolStats, bool) {
+	if pm.instrumentedCache == nil {
+		return cache.PoolStats{}, false
+	}
+	return pm.instrumentedCache.PoolStats()
 }
 
 func DefaultProviderConfiguration() *ProviderConfiguration {
 	return &ProviderConfiguration{
-		CacheTTL:      10 * time.Minute,
-		LogFilePath:   "logs/weather_providers.log",
-		EnableLogging: true,
-		ProviderOrder: []string{"weatherapi", "openweathermap", "accuweather"},
-		CacheType:     CacheTypeMemory,
-		CacheConfig:   &config.CacheConfig{Type: CacheTypeMemory.String()},
+		CacheTTL:        10 * time.Minute,
+		LogFilePath:     "logs/weather_providers.log",
+		EnableLogging:   true,
+		LogLevel:        slog.LevelDebug,
+		ProviderOrder:   []string{"weatherapi", "openweathermap", "accuweather"},
+		CacheType:       CacheTypeMemory,
+		CacheConfig:     &config.CacheConfig{Type: CacheTypeMemory.String()},
+		ProviderTimeout: DefaultHTTPTimeout,
 	}
 }
 
 type ProviderManagerBuilder struct {
 	config *ProviderConfiguration
+	tracer tracing.Tracer
 }
 
 func NewProviderManagerBuilder() *ProviderManagerBuilder {
@@ -353,6 +1010,20 @@ func (b *ProviderManagerBuilder) WithAccuWeatherBaseURL(baseURL string) *Provide
 	return b
 }
 
+// WithAccuWeatherMockMode serves canned AccuWeather data instead of calling
+// the real location-key and conditions endpoints
+func (b *ProviderManagerBuilder) WithAccuWeatherMockMode(enabled bool) *ProviderManagerBuilder {
+	b.config.AccuWeatherMockMode = enabled
+	return b
+}
+
+// WithStaticFile serves weather from a JSON city-to-weather file; add
+// "static" to the provider order to place it in the chain
+func (b *ProviderManagerBuilder) WithStaticFile(path string) *ProviderManagerBuilder {
+	b.config.StaticFilePath = path
+	return b
+}
+
 func (b *ProviderManagerBuilder) WithCacheTTL(ttl time.Duration) *ProviderManagerBuilder {
 	b.config.CacheTTL = ttl
 	return b
@@ -368,6 +1039,12 @@ func (b *ProviderManagerBuilder) WithLoggingEnabled(enabled bool) *ProviderManag
 	return b
 }
 
+// WithLogLevel sets the minimum level for file-logged provider entries
+func (b *ProviderManagerBuilder) WithLogLevel(level slog.Level) *ProviderManagerBuilder {
+	b.config.LogLevel = level
+	return b
+}
+
 func (b *ProviderManagerBuilder) WithProviderOrder(order []string) *ProviderManagerBuilder {
 	b.config.ProviderOrder = order
 	return b
@@ -383,6 +1060,93 @@ func (b *ProviderManagerBuilder) WithCacheConfig(cacheConfig *config.CacheConfig
 	return b
 }
 
+func (b *ProviderManagerBuilder) WithTransformRules(rules *TransformRules) *ProviderManagerBuilder {
+	b.config.Transform = rules
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithProviderTimeout(timeout time.Duration) *ProviderManagerBuilder {
+	b.config.ProviderTimeout = timeout
+	return b
+}
+
+// WithUserAgent sets the User-Agent sent on all outbound provider requests;
+// an empty value keeps the default "weatherapi.app/<version>"
+func (b *ProviderManagerBuilder) WithUserAgent(userAgent string) *ProviderManagerBuilder {
+	b.config.UserAgent = userAgent
+	return b
+}
+
+// WithProviderExtraHeaders adds custom headers to every request the named
+// provider makes
+func (b *ProviderManagerBuilder) WithProviderExtraHeaders(provider string, headers map[string]string) *ProviderManagerBuilder {
+	if b.config.ExtraHeaders == nil {
+		b.config.ExtraHeaders = make(map[string]map[string]string)
+	}
+	b.config.ExtraHeaders[provider] = headers
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithCircuitBreaker(opts *CircuitBreakerOptions) *ProviderManagerBuilder {
+	b.config.CircuitBreaker = opts
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithNegativeCacheTTL(ttl time.Duration) *ProviderManagerBuilder {
+	b.config.NegativeCacheTTL = ttl
+	return b
+}
+
+// WithMemoryCacheMaxEntries bounds the memory cache with LRU eviction; zero
+// leaves it TTL-only
+func (b *ProviderManagerBuilder) WithMemoryCacheMaxEntries(maxEntries int) *ProviderManagerBuilder {
+	b.config.MemoryCacheMaxEntries = maxEntries
+	return b
+}
+
+// WithCacheMode selects strict or stale-while-revalidate cache behavior
+func (b *ProviderManagerBuilder) WithCacheMode(mode CacheMode) *ProviderManagerBuilder {
+	b.config.CacheMode = mode
+	return b
+}
+
+// WithCacheMaxStaleness bounds how far past its TTL an entry may be served
+// in stale-while-revalidate mode
+func (b *ProviderManagerBuilder) WithCacheMaxStaleness(maxStaleness time.Duration) *ProviderManagerBuilder {
+	b.config.CacheMaxStaleness = maxStaleness
+	return b
+}
+
+// WithServeStaleOnError degrades to the last cached value, regardless of
+// TTL, when the whole provider chain fails
+func (b *ProviderManagerBuilder) WithServeStaleOnError(enabled bool) *ProviderManagerBuilder {
+	b.config.ServeStaleOnError = enabled
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithProviderRetries(n int) *ProviderManagerBuilder {
+	b.config.ProviderRetries = n
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithResponseBounds(bounds ResponseBounds) *ProviderManagerBuilder {
+	b.config.ResponseBounds = &bounds
+	return b
+}
+
+func (b *ProviderManagerBuilder) WithFetchLock(ttl, wait time.Duration) *ProviderManagerBuilder {
+	b.config.FetchLockTTL = ttl
+	b.config.FetchLockWait = wait
+	return b
+}
+
+// WithTracer records spans around weather lookups and each provider attempt;
+// without it the manager uses a no-op tracer
+func (b *ProviderManagerBuilder) WithTracer(tracer tracing.Tracer) *ProviderManagerBuilder {
+	b.tracer = tracer
+	return b
+}
+
 func (b *ProviderManagerBuilder) Build() (*ProviderManager, error) {
 	if err := b.validate(); err != nil {
 		return nil, fmt.Errorf("provider manager configuration validation failed: %w", err)
@@ -394,7 +1158,10 @@ func (b *ProviderManagerBuilder) Build() (*ProviderManager, error) {
 	if b.config.CacheConfig != nil {
 		genericCache, err := b.createGenericCache()
 		if err != nil {
-			return nil, fmt.Errorf("create cache: %w", err)
+			genericCache, err = b.fallbackCache(err)
+			if err != nil {
+				return nil, fmt.Errorf("create cache: %w", err)
+			}
 		}
 		instrumentedCache := NewInstrumentedCache(genericCache, b.config.CacheType.String())
 		weatherCache := cache.NewWeatherCache(instrumentedCache)
@@ -403,6 +1170,29 @@ func (b *ProviderManagerBuilder) Build() (*ProviderManager, error) {
 			Cache:             weatherCache,
 			InstrumentedCache: instrumentedCache,
 		}
+
+		// Distributed fetch locking only applies when the cache is shared
+		// between replicas through Redis
+		if b.config.FetchLockTTL > 0 && b.config.CacheType == CacheTypeRedis {
+			fetchLock, err := NewRedisFetchLockFromConfig(&b.config.CacheConfig.Redis)
+			if err != nil {
+				// With the memory fallback active, Redis is known to be
+				// down; run without the distributed lock rather than fail
+				if !b.config.CacheConfig.FallbackToMemory {
+					return nil, fmt.Errorf("create fetch lock: %w", err)
+				}
+				slog.Warn("Redis fetch lock unavailable, continuing without distributed locking", "error", err)
+			} else {
+				opts.FetchLock = fetchLock
+			}
+		}
+	}
+
+	if b.tracer != nil {
+		if opts == nil {
+			opts = &ProviderManagerOptions{}
+		}
+		opts.Tracer = b.tracer
 	}
 
 	return NewProviderManager(b.config, opts)
@@ -411,29 +1201,65 @@ func (b *ProviderManagerBuilder) Build() (*ProviderManager, error) {
 func (b *ProviderManagerBuilder) createGenericCache() (cache.GenericCache, error) {
 	switch b.config.CacheType {
 	case CacheTypeMemory:
-		slog.Info("Creating memory cache")
-		return cache.NewMemoryCache(), nil
+		slog.Info("Creating memory cache", "maxEntries", b.config.MemoryCacheMaxEntries)
+		return cache.NewMemoryCacheWithMaxEntries(b.config.MemoryCacheMaxEntries), nil
 	case CacheTypeRedis:
 		slog.Info("Creating Redis cache", "addr", b.config.CacheConfig.Redis.Addr)
-		redisConfig := &cache.RedisCacheConfig{
-			Addr:         b.config.CacheConfig.Redis.Addr,
+		return cache.NewRedisCache(b.redisCacheConfig())
+	case CacheTypeRedisCluster:
+		slog.Info("Creating Redis cluster cache", "addrs", b.config.CacheConfig.Redis.ClusterAddrs)
+		clusterConfig := &cache.RedisClusterCacheConfig{
+			Addrs:        b.config.CacheConfig.Redis.ClusterAddrs,
 			Password:     b.config.CacheConfig.Redis.Password,
-			DB:           b.config.CacheConfig.Redis.DB,
 			DialTimeout:  time.Duration(b.config.CacheConfig.Redis.DialTimeout) * time.Second,
 			ReadTimeout:  time.Duration(b.config.CacheConfig.Redis.ReadTimeout) * time.Second,
 			WriteTimeout: time.Duration(b.config.CacheConfig.Redis.WriteTimeout) * time.Second,
 		}
-		return cache.NewRedisCache(redisConfig)
+		return cache.NewRedisClusterCache(clusterConfig)
 	default:
 		return nil, fmt.Errorf("unsupported cache type: %s", b.config.CacheType)
 	}
 }
 
+func (b *ProviderManagerBuilder) redisCacheConfig() *cache.RedisCacheConfig {
+	return &cache.RedisCacheConfig{
+		Addr:         b.config.CacheConfig.Redis.Addr,
+		Password:     b.config.CacheConfig.Redis.Password,
+		DB:           b.config.CacheConfig.Redis.DB,
+		DialTimeout:  time.Duration(b.config.CacheConfig.Redis.DialTimeout) * time.Second,
+		ReadTimeout:  time.Duration(b.config.CacheConfig.Redis.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(b.config.CacheConfig.Redis.WriteTimeout) * time.Second,
+		PoolSize:     b.config.CacheConfig.Redis.PoolSize,
+		MinIdleConns: b.config.CacheConfig.Redis.MinIdleConns,
+		MaxConnAge:   time.Duration(b.config.CacheConfig.Redis.MaxConnAge) * time.Second,
+	}
+}
+
+// fallbackCache builds the in-memory stand-in used when
+// CACHE_FALLBACK_TO_MEMORY is set and the Redis cache cannot be created.
+// Single-node Redis keeps retrying in the background and promotes back once
+// healthy; the cluster cache falls back without a retry
+func (b *ProviderManagerBuilder) fallbackCache(cause error) (cache.GenericCache, error) {
+	if !b.config.CacheConfig.FallbackToMemory {
+		return nil, cause
+	}
+	if b.config.CacheType != CacheTypeRedis && b.config.CacheType != CacheTypeRedisCluster {
+		return nil, cause
+	}
+
+	slog.Warn("Cache backend unavailable, falling back to memory cache", "cacheType", b.config.CacheType.String(), "error", cause)
+
+	if b.config.CacheType == CacheTypeRedis {
+		return cache.NewFallbackCache(b.redisCacheConfig()), nil
+	}
+	return cache.NewMemoryCache(), nil
+}
+
 // validate ensures the builder configuration is valid before building
 func (b *ProviderManagerBuilder) validate() error {
 	// At least one weather provider must be configured
-	if b.config.WeatherAPIKey == "" && b.config.OpenWeatherMapKey == "" && b.config.AccuWeatherKey == "" {
-		return fmt.Errorf("at least one weather provider API key must be configured")
+	if b.config.WeatherAPIKey == "" && b.config.OpenWeatherMapKey == "" && b.config.AccuWeatherKey == "" && b.config.StaticFilePath == "" {
+		return fmt.Errorf("at least one weather provider API key or a static weather file must be configured")
 	}
 
 	// Validate WeatherAPI configuration if provided
@@ -446,6 +1272,22 @@ func (b *ProviderManagerBuilder) validate() error {
 		return fmt.Errorf("cache TTL must be positive")
 	}
 
+	// Negative cache TTL is optional but must stay shorter than the positive TTL
+	if b.config.NegativeCacheTTL < 0 {
+		return fmt.Errorf("negative cache TTL cannot be negative")
+	}
+	if b.config.NegativeCacheTTL >= b.config.CacheTTL && b.config.NegativeCacheTTL > 0 {
+		return fmt.Errorf("negative cache TTL must be shorter than cache TTL")
+	}
+
+	// Fetch lock timings are optional but cannot be negative
+	if b.config.FetchLockTTL < 0 {
+		return fmt.Errorf("fetch lock TTL cannot be negative")
+	}
+	if b.config.FetchLockWait < 0 {
+		return fmt.Errorf("fetch lock wait cannot be negative")
+	}
+
 	// Validate log file path if logging is enabled
 	if b.config.EnableLogging && b.config.LogFilePath == "" {
 		return fmt.Errorf("log file path is required when logging is enabled")
@@ -456,6 +1298,7 @@ func (b *ProviderManagerBuilder) validate() error {
 		"weatherapi":     true,
 		"openweathermap": true,
 		"accuweather":    true,
+		"static":         true,
 	}
 
 	for _, provider := range b.config.ProviderOrder {
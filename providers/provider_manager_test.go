@@ -1,10 +1,18 @@
 package providers
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
 )
 
 func TestProviderManager_NoProvidersConfigured(t *testing.T) {
@@ -15,6 +23,11 @@ func TestProviderManager_NoProvidersConfigured(t *testing.T) {
 	assert.Contains(t, err.Error(), "at least one weather provider API key must be configured")
 }
 
+func TestCacheTypeRedisCluster(t *testing.T) {
+	assert.Equal(t, "redis-cluster", CacheTypeRedisCluster.String())
+	assert.Equal(t, CacheTypeRedisCluster, CacheTypeFromString("redis-cluster"))
+}
+
 func TestProviderManager_WithProvidersConfigured(t *testing.T) {
 	config := &ProviderConfiguration{
 		WeatherAPIKey:     "test-weather-api-key",
@@ -50,3 +63,156 @@ func TestProviderManager_WithProvidersConfigured(t *testing.T) {
 	assert.Nil(t, info["cache_ttl"])
 	assert.NotEmpty(t, info["chain_name"])
 }
+
+func TestProviderManager_GetWeatherFrom(t *testing.T) {
+	path := writeStaticWeatherFile(t, `{
+		"London": {"temperature": 15.0, "humidity": 76, "description": "Partly cloudy"}
+	}`)
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-weather-api-key").
+		WithStaticFile(path).
+		WithProviderOrder([]string{"weatherapi", "static"}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("NamedProvider", func(t *testing.T) {
+		// The static provider answers directly even though it is last in the
+		// fallback chain
+		weather, err := manager.GetWeatherFrom(context.Background(), "static", "London")
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Equal(t, 15.0, weather.Temperature)
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		weather, err := manager.GetWeatherFrom(context.Background(), "bogus", "London")
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+func TestProviderManagerBuilder_RedisFallbackToMemory(t *testing.T) {
+	// Port 1 is never a Redis server, so cache construction fails
+	cacheConfig := &config.CacheConfig{
+		Type:             CacheTypeRedis.String(),
+		FallbackToMemory: true,
+		Redis: config.RedisConfig{
+			Addr:         "localhost:1",
+			DialTimeout:  1,
+			ReadTimeout:  1,
+			WriteTimeout: 1,
+		},
+	}
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheType(CacheTypeRedis).
+		WithCacheConfig(cacheConfig).
+		Build()
+	require.NoError(t, err)
+	require.NotNil(t, manager)
+
+	info := manager.GetProviderInfo()
+	assert.Equal(t, true, info["cache_enabled"])
+}
+
+func TestProviderManagerBuilder_RedisUnavailableFailsFast(t *testing.T) {
+	cacheConfig := &config.CacheConfig{
+		Type: CacheTypeRedis.String(),
+		Redis: config.RedisConfig{
+			Addr:         "localhost:1",
+			DialTimeout:  1,
+			ReadTimeout:  1,
+			WriteTimeout: 1,
+		},
+	}
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL("https://api.example.com").
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheType(CacheTypeRedis).
+		WithCacheConfig(cacheConfig).
+		Build()
+	assert.Error(t, err)
+	assert.Nil(t, manager)
+	assert.Contains(t, err.Error(), "create cache")
+}
+
+func TestProviderManager_CoalescesConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	hitsByCity := map[string]int{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hitsByCity[r.URL.Query().Get("q")]++
+		mu.Unlock()
+
+		// Hold the fetch open long enough for the other requests to pile up
+		// behind the in-flight call
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{
+			"current": {
+				"temp_c": 12.5,
+				"humidity": 70,
+				"condition": {"text": "Sunny"}
+			}
+		}`))
+	}))
+	defer mockServer.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(mockServer.URL).
+		WithProviderOrder([]string{"weatherapi"}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("SameCitySharesOneFetch", func(t *testing.T) {
+		const concurrent = 10
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrent; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				weather, err := manager.GetWeather(context.Background(), "London")
+				assert.NoError(t, err)
+				assert.NotNil(t, weather)
+			}()
+		}
+		wg.Wait()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, hitsByCity["London"])
+	})
+
+	t.Run("DifferentCitiesNotCoalesced", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for _, city := range []string{"Paris", "Berlin"} {
+			wg.Add(1)
+			go func(city string) {
+				defer wg.Done()
+				_, err := manager.GetWeather(context.Background(), city)
+				assert.NoError(t, err)
+			}(city)
+		}
+		wg.Wait()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, hitsByCity["Paris"])
+		assert.Equal(t, 1, hitsByCity["Berlin"])
+	})
+}
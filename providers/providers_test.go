@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -26,6 +27,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 				"current": {
 					"temp_c": 15.0,
 					"humidity": 76,
+					"last_updated_epoch": 1700000000,
 					"condition": {
 						"text": "Partly cloudy"
 					}
@@ -41,13 +43,45 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("London")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, weather)
 		assert.Equal(t, 15.0, weather.Temperature)
 		assert.Equal(t, 76.0, weather.Humidity)
 		assert.Equal(t, "Partly cloudy", weather.Description)
+		require.NotNil(t, weather.ProviderObservedAt)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), *weather.ProviderObservedAt)
+	})
+
+	t.Run("MissingObservationTimestamp", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{
+				"current": {
+					"temp_c": 15.0,
+					"humidity": 76,
+					"condition": {
+						"text": "Partly cloudy"
+					}
+				}
+			}`))
+			require.NoError(t, err)
+		}))
+		defer mockServer.Close()
+
+		config := &config.WeatherConfig{
+			APIKey:  "test-api-key",
+			BaseURL: mockServer.URL,
+		}
+
+		provider := NewWeatherAPIProvider(config)
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Nil(t, weather.ProviderObservedAt)
 	})
 
 	t.Run("EmptyCity", func(t *testing.T) {
@@ -57,7 +91,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("")
+		weather, err := provider.GetCurrentWeather(context.Background(), "")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -80,7 +114,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("NonExistentCity")
+		weather, err := provider.GetCurrentWeather(context.Background(), "NonExistentCity")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -103,7 +137,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("London")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -128,7 +162,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("London")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -154,7 +188,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("London")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -185,7 +219,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 		}
 
 		provider := NewWeatherAPIProvider(config)
-		weather, err := provider.GetCurrentWeather("London")
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 		assert.Error(t, err)
 		assert.Nil(t, weather)
@@ -208,11 +242,12 @@ func TestSMTPEmailProvider_SendEmail(t *testing.T) {
 			FromAddress:  "test@example.com",
 		}
 
-		provider := NewSMTPEmailProvider(config)
+		provider, err := NewSMTPEmailProvider(config)
+		require.NoError(t, err)
 
 		// Note: This test would require a mock SMTP server or would actually try to send email
 		// For now, we'll test the validation logic
-		err := provider.SendEmail("", "Subject", "Body", false)
+		err = provider.SendEmail("", "Subject", "Body", false)
 		assert.Error(t, err)
 
 		var appErr *weathererr.AppError
@@ -231,8 +266,9 @@ func TestSMTPEmailProvider_SendEmail(t *testing.T) {
 			FromAddress:  "test@example.com",
 		}
 
-		provider := NewSMTPEmailProvider(config)
-		err := provider.SendEmail("", "Subject", "Body", false)
+		provider, err := NewSMTPEmailProvider(config)
+		require.NoError(t, err)
+		err = provider.SendEmail("", "Subject", "Body", false)
 
 		assert.Error(t, err)
 
@@ -252,8 +288,9 @@ func TestSMTPEmailProvider_SendEmail(t *testing.T) {
 			FromAddress:  "test@example.com",
 		}
 
-		provider := NewSMTPEmailProvider(config)
-		err := provider.SendEmail("recipient@example.com", "", "Body", false)
+		provider, err := NewSMTPEmailProvider(config)
+		require.NoError(t, err)
+		err = provider.SendEmail("recipient@example.com", "", "Body", false)
 
 		assert.Error(t, err)
 
@@ -273,7 +310,8 @@ func TestSMTPEmailProvider_SendEmail(t *testing.T) {
 			FromAddress:  "test@example.com",
 		}
 
-		provider := NewSMTPEmailProvider(config)
+		provider, err := NewSMTPEmailProvider(config)
+		require.NoError(t, err)
 
 		// Test that newlines are properly cleaned from subject
 		// This would require a mock SMTP server to fully test, but we can verify the validation
@@ -290,9 +328,98 @@ func TestNewWeatherAPIProvider(t *testing.T) {
 	provider := NewWeatherAPIProvider(config)
 
 	assert.NotNil(t, provider)
-	assert.Equal(t, "test-api-key", provider.apiKey)
+	assert.Equal(t, []string{"test-api-key"}, provider.apiKeys)
 	assert.Equal(t, "https://api.example.com", provider.baseURL)
 	assert.NotNil(t, provider.client)
+	assert.Equal(t, DefaultHTTPTimeout, provider.client.Timeout)
+}
+
+func TestNewWeatherAPIProvider_ConfiguredTimeout(t *testing.T) {
+	config := &config.WeatherConfig{
+		APIKey:                 "test-api-key",
+		BaseURL:                "https://api.example.com",
+		ProviderTimeoutSeconds: 3,
+	}
+
+	provider := NewWeatherAPIProvider(config)
+
+	assert.Equal(t, 3*time.Second, provider.client.Timeout)
+}
+
+func TestWeatherAPIProvider_KeyRotation(t *testing.T) {
+	weatherBody := `{"current": {"temp_c": 15.0, "humidity": 76, "condition": {"text": "Sunny"}}}`
+
+	t.Run("RoundRobinAcrossKeys", func(t *testing.T) {
+		var usedKeys []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usedKeys = append(usedKeys, r.URL.Query().Get("key"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(weatherBody))
+		}))
+		defer mockServer.Close()
+
+		provider := NewWeatherAPIProvider(&config.WeatherConfig{
+			APIKey:  "key-a,key-b",
+			BaseURL: mockServer.URL,
+		})
+
+		assert.Equal(t, 2, provider.KeyCount())
+
+		for i := 0; i < 3; i++ {
+			_, err := provider.GetCurrentWeather(context.Background(), "London")
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"key-a", "key-b", "key-a"}, usedKeys)
+	})
+
+	t.Run("SingleKeyUnchanged", func(t *testing.T) {
+		var usedKeys []string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usedKeys = append(usedKeys, r.URL.Query().Get("key"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(weatherBody))
+		}))
+		defer mockServer.Close()
+
+		provider := NewWeatherAPIProvider(&config.WeatherConfig{
+			APIKey:  "only-key",
+			BaseURL: mockServer.URL,
+		})
+
+		assert.Equal(t, 1, provider.KeyCount())
+
+		for i := 0; i < 2; i++ {
+			_, err := provider.GetCurrentWeather(context.Background(), "London")
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"only-key", "only-key"}, usedKeys)
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer mockServer.Close()
+
+		provider := NewWeatherAPIProvider(&config.WeatherConfig{
+			APIKey:  "key-a,key-b",
+			BaseURL: mockServer.URL,
+		})
+
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.RateLimitError, appErr.Type)
+		assert.Contains(t, appErr.Message, "rate limit exceeded")
+		assert.Equal(t, 2*time.Minute, appErr.RetryAfter)
+	})
 }
 
 func TestNewSMTPEmailProvider(t *testing.T) {
@@ -305,7 +432,8 @@ func TestNewSMTPEmailProvider(t *testing.T) {
 		FromAddress:  "test@example.com",
 	}
 
-	provider := NewSMTPEmailProvider(config)
+	provider, err := NewSMTPEmailProvider(config)
+	require.NoError(t, err)
 
 	assert.NotNil(t, provider)
 	assert.Equal(t, "smtp.example.com", provider.smtpHost)
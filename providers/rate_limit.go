@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// parseRetryAfter reads the Retry-After header of a rate-limited response,
+// accepting both the delay-seconds and HTTP-date forms; zero means the
+// server did not say when to retry
+func parseRetryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
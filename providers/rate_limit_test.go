@@ -0,0 +1,126 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	responseWithHeader := func(value string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		if value != "" {
+			resp.Header.Set("Retry-After", value)
+		}
+		return resp
+	}
+
+	t.Run("DelaySeconds", func(t *testing.T) {
+		assert.Equal(t, 90*time.Second, parseRetryAfter(responseWithHeader("90")))
+	})
+
+	t.Run("HTTPDate", func(t *testing.T) {
+		when := time.Now().Add(2 * time.Minute).UTC()
+		wait := parseRetryAfter(responseWithHeader(when.Format(http.TimeFormat)))
+
+		assert.Greater(t, wait, time.Minute)
+		assert.LessOrEqual(t, wait, 2*time.Minute)
+	})
+
+	t.Run("PastHTTPDate", func(t *testing.T) {
+		when := time.Now().Add(-time.Minute).UTC()
+		assert.Equal(t, time.Duration(0), parseRetryAfter(responseWithHeader(when.Format(http.TimeFormat))))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), parseRetryAfter(responseWithHeader("")))
+	})
+
+	t.Run("Garbage", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), parseRetryAfter(responseWithHeader("soon")))
+	})
+}
+
+func TestChainRateLimitBackoff(t *testing.T) {
+	rateLimitedProvider := func(calls *int) *WeatherAPIProvider {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls != nil {
+				*calls++
+			}
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Cleanup(server.Close)
+
+		return NewWeatherAPIProvider(&config.WeatherConfig{
+			APIKey:  "limited-key",
+			BaseURL: server.URL,
+		})
+	}
+
+	t.Run("SkipsRateLimitedProvider", func(t *testing.T) {
+		var limitedCalls int
+
+		healthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{
+				"current": {
+					"temp_c": 11.0,
+					"humidity": 70,
+					"condition": {"text": "Cloudy"}
+				}
+			}`))
+		}))
+		defer healthyServer.Close()
+
+		chain := NewChainBuilder().
+			AddHandler(NewWeatherAPIHandler(rateLimitedProvider(&limitedCalls))).
+			AddHandler(NewAccuWeatherHandler(NewWeatherAPIProvider(&config.WeatherConfig{
+				APIKey:  "healthy-key",
+				BaseURL: healthyServer.URL,
+			}))).
+			Build()
+
+		// First call hits the rate-limited provider and falls through to
+		// the healthy one
+		weather, err := chain.Handle(context.Background(), "London")
+		assert.NoError(t, err)
+		assert.NotNil(t, weather)
+		assert.Equal(t, 1, limitedCalls)
+
+		// Second call skips the limited provider entirely; it is still
+		// inside its backoff window
+		weather, err = chain.Handle(context.Background(), "London")
+		assert.NoError(t, err)
+		assert.NotNil(t, weather)
+		assert.Equal(t, 1, limitedCalls)
+	})
+
+	t.Run("LastHandlerRateLimited", func(t *testing.T) {
+		chain := NewChainBuilder().
+			AddHandler(NewWeatherAPIHandler(rateLimitedProvider(nil))).
+			Build()
+
+		_, err := chain.Handle(context.Background(), "London")
+		assert.Error(t, err)
+
+		// The backoff window is now active and there is nowhere to fall
+		// through to
+		weather, err := chain.Handle(context.Background(), "London")
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.RateLimitError, appErr.Type)
+		assert.Greater(t, appErr.RetryAfter, time.Duration(0))
+	})
+}
@@ -0,0 +1,54 @@
+package providers
+
+import (
+	"math"
+
+	"weatherapi.app/models"
+)
+
+// TransformRules describes declarative adjustments applied to weather
+// responses before they are cached or returned
+type TransformRules struct {
+	// RoundPrecision is the number of decimal places temperature and
+	// humidity are rounded to. A negative value disables rounding.
+	RoundPrecision int
+	// TemperatureOffset is added to the temperature before rounding
+	TemperatureOffset float64
+}
+
+// IsZero reports whether the rules would leave responses unchanged
+func (r *TransformRules) IsZero() bool {
+	return r.RoundPrecision < 0 && r.TemperatureOffset == 0
+}
+
+// ResponseTransformer applies configured transform rules to weather responses
+type ResponseTransformer struct {
+	rules TransformRules
+}
+
+// NewResponseTransformer creates a transformer for the given rules
+func NewResponseTransformer(rules TransformRules) *ResponseTransformer {
+	return &ResponseTransformer{rules: rules}
+}
+
+// Transform returns a copy of the response with the configured rules applied
+func (t *ResponseTransformer) Transform(response *models.WeatherResponse) *models.WeatherResponse {
+	if response == nil {
+		return nil
+	}
+
+	transformed := *response
+	transformed.Temperature += t.rules.TemperatureOffset
+
+	if t.rules.RoundPrecision >= 0 {
+		transformed.Temperature = roundTo(transformed.Temperature, t.rules.RoundPrecision)
+		transformed.Humidity = roundTo(transformed.Humidity, t.rules.RoundPrecision)
+	}
+
+	return &transformed
+}
+
+func roundTo(value float64, precision int) float64 {
+	factor := math.Pow(10, float64(precision))
+	return math.Round(value*factor) / factor
+}
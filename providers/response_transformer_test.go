@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"weatherapi.app/models"
+)
+
+func TestResponseTransformer_Rounding(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: 0})
+
+	result := transformer.Transform(&models.WeatherResponse{
+		Temperature: 21.67,
+		Humidity:    64.4,
+		Description: "Partly cloudy",
+	})
+
+	assert.Equal(t, 22.0, result.Temperature)
+	assert.Equal(t, 64.0, result.Humidity)
+	assert.Equal(t, "Partly cloudy", result.Description)
+}
+
+func TestResponseTransformer_RoundingOneDecimal(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: 1})
+
+	result := transformer.Transform(&models.WeatherResponse{
+		Temperature: 21.67,
+		Humidity:    64.44,
+	})
+
+	assert.Equal(t, 21.7, result.Temperature)
+	assert.Equal(t, 64.4, result.Humidity)
+}
+
+func TestResponseTransformer_TemperatureOffset(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: -1, TemperatureOffset: -1.5})
+
+	result := transformer.Transform(&models.WeatherResponse{
+		Temperature: 20.0,
+		Humidity:    50.0,
+	})
+
+	assert.Equal(t, 18.5, result.Temperature)
+	assert.Equal(t, 50.0, result.Humidity)
+}
+
+func TestResponseTransformer_OffsetThenRounding(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: 0, TemperatureOffset: 0.3})
+
+	result := transformer.Transform(&models.WeatherResponse{
+		Temperature: 21.4,
+		Humidity:    50.0,
+	})
+
+	assert.Equal(t, 22.0, result.Temperature)
+}
+
+func TestResponseTransformer_DoesNotMutateOriginal(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: 0, TemperatureOffset: 5})
+
+	original := &models.WeatherResponse{Temperature: 10.2, Humidity: 33.3}
+	transformer.Transform(original)
+
+	assert.Equal(t, 10.2, original.Temperature)
+	assert.Equal(t, 33.3, original.Humidity)
+}
+
+func TestResponseTransformer_NilResponse(t *testing.T) {
+	transformer := NewResponseTransformer(TransformRules{RoundPrecision: 0})
+
+	assert.Nil(t, transformer.Transform(nil))
+}
+
+func TestTransformRules_IsZero(t *testing.T) {
+	assert.True(t, (&TransformRules{RoundPrecision: -1}).IsZero())
+	assert.False(t, (&TransformRules{RoundPrecision: 0}).IsZero())
+	assert.False(t, (&TransformRules{RoundPrecision: -1, TemperatureOffset: 1}).IsZero())
+}
@@ -0,0 +1,124 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// Default sanity bounds for decoded provider responses
+const (
+	DefaultMinTemperature = -100.0
+	DefaultMaxTemperature = 60.0
+	DefaultMinHumidity    = 0.0
+	DefaultMaxHumidity    = 100.0
+)
+
+// ResponseBounds describes the sanity limits a decoded provider response
+// must satisfy before it is accepted
+type ResponseBounds struct {
+	MinTemperature float64
+	MaxTemperature float64
+	MinHumidity    float64
+	MaxHumidity    float64
+}
+
+// DefaultResponseBounds returns the bounds used when none are configured
+func DefaultResponseBounds() ResponseBounds {
+	return ResponseBounds{
+		MinTemperature: DefaultMinTemperature,
+		MaxTemperature: DefaultMaxTemperature,
+		MinHumidity:    DefaultMinHumidity,
+		MaxHumidity:    DefaultMaxHumidity,
+	}
+}
+
+// ValidationDecorator wraps a weather provider and rejects responses that
+// decoded fine but carry implausible values, such as a humidity of 9999.
+// Rejections surface as external API errors so the chain falls through to
+// the next provider instead of caching and emailing garbage
+type ValidationDecorator struct {
+	provider WeatherProvider
+	bounds   ResponseBounds
+}
+
+// NewValidationDecorator creates a validation decorator around the given
+// provider with the given sanity bounds
+func NewValidationDecorator(provider WeatherProvider, bounds ResponseBounds) *ValidationDecorator {
+	return &ValidationDecorator{
+		provider: provider,
+		bounds:   bounds,
+	}
+}
+
+// GetCurrentWeather delegates to the wrapped provider and checks the result
+// against the configured bounds
+func (d *ValidationDecorator) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	response, err := d.provider.GetCurrentWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.validateWeather(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetForecast delegates to the wrapped provider and checks every forecast
+// day against the configured bounds
+func (d *ValidationDecorator) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	response, err := d.provider.GetForecast(city, days)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, day := range response.Days {
+		if err := d.validateForecastDay(&day); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// Capabilities reports the wrapped provider's capabilities
+func (d *ValidationDecorator) Capabilities() ProviderCapabilities {
+	return providerCapabilities(d.provider)
+}
+
+func (d *ValidationDecorator) validateWeather(response *models.WeatherResponse) error {
+	if response.Temperature < d.bounds.MinTemperature || response.Temperature > d.bounds.MaxTemperature {
+		return errors.NewExternalAPIError(fmt.Sprintf(
+			"implausible temperature %.1f outside [%.1f, %.1f]",
+			response.Temperature, d.bounds.MinTemperature, d.bounds.MaxTemperature), nil)
+	}
+	if response.Humidity < d.bounds.MinHumidity || response.Humidity > d.bounds.MaxHumidity {
+		return errors.NewExternalAPIError(fmt.Sprintf(
+			"implausible humidity %.1f outside [%.1f, %.1f]",
+			response.Humidity, d.bounds.MinHumidity, d.bounds.MaxHumidity), nil)
+	}
+	if strings.TrimSpace(response.Description) == "" {
+		return errors.NewExternalAPIError("missing weather description", nil)
+	}
+	return nil
+}
+
+func (d *ValidationDecorator) validateForecastDay(day *models.ForecastDay) error {
+	if day.MinTemp < d.bounds.MinTemperature || day.MaxTemp > d.bounds.MaxTemperature {
+		return errors.NewExternalAPIError(fmt.Sprintf(
+			"implausible forecast temperatures [%.1f, %.1f] outside [%.1f, %.1f]",
+			day.MinTemp, day.MaxTemp, d.bounds.MinTemperature, d.bounds.MaxTemperature), nil)
+	}
+	if day.Humidity < d.bounds.MinHumidity || day.Humidity > d.bounds.MaxHumidity {
+		return errors.NewExternalAPIError(fmt.Sprintf(
+			"implausible forecast humidity %.1f outside [%.1f, %.1f]",
+			day.Humidity, d.bounds.MinHumidity, d.bounds.MaxHumidity), nil)
+	}
+	if strings.TrimSpace(day.Description) == "" {
+		return errors.NewExternalAPIError("missing forecast description", nil)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// stubWeatherProvider returns a fixed response so validator tests can feed
+// it implausible values
+type stubWeatherProvider struct {
+	weather  *models.WeatherResponse
+	forecast *models.ForecastResponse
+	err      error
+}
+
+func (s *stubWeatherProvider) GetCurrentWeather(_ context.Context, _ string) (*models.WeatherResponse, error) {
+	return s.weather, s.err
+}
+
+func (s *stubWeatherProvider) GetForecast(_ string, _ int) (*models.ForecastResponse, error) {
+	return s.forecast, s.err
+}
+
+func TestValidationDecorator_GetCurrentWeather(t *testing.T) {
+	tests := []struct {
+		name        string
+		weather     models.WeatherResponse
+		wantMessage string
+	}{
+		{
+			name:    "ValidResponse",
+			weather: models.WeatherResponse{Temperature: 21.5, Humidity: 65, Description: "Sunny"},
+		},
+		{
+			name:        "ImplausibleHumidity",
+			weather:     models.WeatherResponse{Temperature: 21.5, Humidity: 9999, Description: "Sunny"},
+			wantMessage: "implausible humidity",
+		},
+		{
+			name:        "ImplausibleTemperature",
+			weather:     models.WeatherResponse{Temperature: 250, Humidity: 65, Description: "Sunny"},
+			wantMessage: "implausible temperature",
+		},
+		{
+			name:        "MissingDescription",
+			weather:     models.WeatherResponse{Temperature: 21.5, Humidity: 65, Description: "  "},
+			wantMessage: "missing weather description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decorator := NewValidationDecorator(
+				&stubWeatherProvider{weather: &tt.weather}, DefaultResponseBounds())
+
+			weather, err := decorator.GetCurrentWeather(context.Background(), "London")
+
+			if tt.wantMessage == "" {
+				assert.NoError(t, err)
+				require.NotNil(t, weather)
+				assert.Equal(t, tt.weather.Temperature, weather.Temperature)
+				return
+			}
+
+			assert.Error(t, err)
+			assert.Nil(t, weather)
+
+			var appErr *weathererr.AppError
+			assert.True(t, errors.As(err, &appErr))
+			assert.Equal(t, weathererr.ExternalAPIError, appErr.Type)
+			assert.Contains(t, appErr.Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestValidationDecorator_GetForecast(t *testing.T) {
+	t.Run("ImplausibleDay", func(t *testing.T) {
+		decorator := NewValidationDecorator(&stubWeatherProvider{forecast: &models.ForecastResponse{
+			City: "London",
+			Days: []models.ForecastDay{
+				{Date: "2025-01-01", MinTemp: 2, MaxTemp: 8, Humidity: 70, Description: "Cloudy"},
+				{Date: "2025-01-02", MinTemp: 2, MaxTemp: 8, Humidity: 9999, Description: "Cloudy"},
+			},
+		}}, DefaultResponseBounds())
+
+		forecast, err := decorator.GetForecast("London", 2)
+
+		assert.Error(t, err)
+		assert.Nil(t, forecast)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ExternalAPIError, appErr.Type)
+	})
+
+	t.Run("ValidForecast", func(t *testing.T) {
+		decorator := NewValidationDecorator(&stubWeatherProvider{forecast: &models.ForecastResponse{
+			City: "London",
+			Days: []models.ForecastDay{
+				{Date: "2025-01-01", MinTemp: 2, MaxTemp: 8, Humidity: 70, Description: "Cloudy"},
+			},
+		}}, DefaultResponseBounds())
+
+		forecast, err := decorator.GetForecast("London", 1)
+
+		assert.NoError(t, err)
+		require.NotNil(t, forecast)
+		assert.Len(t, forecast.Days, 1)
+	})
+}
+
+func TestValidationDecorator_ChainFallsThrough(t *testing.T) {
+	garbage := NewValidationDecorator(&stubWeatherProvider{weather: &models.WeatherResponse{
+		Temperature: 20, Humidity: 9999, Description: "Sunny",
+	}}, DefaultResponseBounds())
+	healthy := &stubWeatherProvider{weather: &models.WeatherResponse{
+		Temperature: 18, Humidity: 72, Description: "Overcast",
+	}}
+
+	chain := NewChainBuilder().
+		AddHandler(NewWeatherAPIHandler(garbage)).
+		AddHandler(NewAccuWeatherHandler(healthy)).
+		Build()
+
+	weather, err := chain.Handle(context.Background(), "London")
+
+	require.NoError(t, err)
+	require.NotNil(t, weather)
+	assert.Equal(t, 18.0, weather.Temperature)
+}
+
+func TestValidationDecorator_CustomBounds(t *testing.T) {
+	// A site in Siberia may legitimately see temperatures the default lower
+	// bound would reject
+	decorator := NewValidationDecorator(&stubWeatherProvider{weather: &models.WeatherResponse{
+		Temperature: -120, Humidity: 50, Description: "Clear",
+	}}, ResponseBounds{MinTemperature: -150, MaxTemperature: 60, MinHumidity: 0, MaxHumidity: 100})
+
+	weather, err := decorator.GetCurrentWeather(context.Background(), "Oymyakon")
+
+	assert.NoError(t, err)
+	require.NotNil(t, weather)
+}
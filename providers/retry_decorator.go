@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+const (
+	// defaultRetryBaseDelay is the starting backoff delay between retries
+	defaultRetryBaseDelay = 200 * time.Millisecond
+)
+
+// RetryDecorator wraps a weather provider and retries transient failures with
+// jittered exponential backoff. Only external API errors are retried; domain
+// errors such as not-found or validation failures surface immediately.
+type RetryDecorator struct {
+	provider   WeatherProvider
+	maxRetries int
+	baseDelay  time.Duration
+	ctx        context.Context
+}
+
+// NewRetryDecorator creates a retry decorator around the given provider
+func NewRetryDecorator(provider WeatherProvider, maxRetries int, baseDelay time.Duration) *RetryDecorator {
+	return NewRetryDecoratorWithContext(context.Background(), provider, maxRetries, baseDelay)
+}
+
+// NewRetryDecoratorWithContext creates a retry decorator whose backoff waits
+// are aborted as soon as the given context is cancelled
+func NewRetryDecoratorWithContext(ctx context.Context, provider WeatherProvider, maxRetries int, baseDelay time.Duration) *RetryDecorator {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+
+	return &RetryDecorator{
+		provider:   provider,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		ctx:        ctx,
+	}
+}
+
+// GetCurrentWeather delegates to the wrapped provider, retrying transient errors
+func (d *RetryDecorator) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	var response *models.WeatherResponse
+	err := d.retry(ctx, func() error {
+		var callErr error
+		response, callErr = d.provider.GetCurrentWeather(ctx, city)
+		return callErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetForecast delegates to the wrapped provider, retrying transient errors
+func (d *RetryDecorator) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	var response *models.ForecastResponse
+	err := d.retry(d.ctx, func() error {
+		var callErr error
+		response, callErr = d.provider.GetForecast(city, days)
+		return callErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Capabilities reports the wrapped provider's capabilities
+func (d *RetryDecorator) Capabilities() ProviderCapabilities {
+	return providerCapabilities(d.provider)
+}
+
+// retry runs the call, retrying up to maxRetries times while it keeps
+// failing with a retryable error
+func (d *RetryDecorator) retry(ctx context.Context, call func() error) error {
+	err := call()
+	for attempt := 1; attempt <= d.maxRetries && isRetryableError(err); attempt++ {
+		if waitErr := d.wait(ctx, attempt); waitErr != nil {
+			return waitErr
+		}
+		err = call()
+	}
+	return err
+}
+
+// isRetryableError reports whether the error is a transient external API failure
+func isRetryableError(err error) bool {
+	var appErr *weathererr.AppError
+	return errors.As(err, &appErr) && appErr.Type == weathererr.ExternalAPIError
+}
+
+// wait sleeps for a jittered exponential backoff delay, aborting immediately
+// when either the request context or the decorator's context is cancelled
+func (d *RetryDecorator) wait(ctx context.Context, attempt int) error {
+	delay := d.baseDelay << (attempt - 1)
+	delay += time.Duration(rand.Int63n(int64(d.baseDelay)))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.ctx.Done():
+		return d.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// flakyProvider fails a fixed number of times before succeeding
+type flakyProvider struct {
+	calls        int
+	failuresLeft int
+	err          error
+}
+
+func (p *flakyProvider) GetCurrentWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
+	p.calls++
+	if p.failuresLeft > 0 {
+		p.failuresLeft--
+		return nil, p.err
+	}
+	return &models.WeatherResponse{Temperature: 15.0}, nil
+}
+
+func (p *flakyProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	return nil, ErrForecastNotSupported
+}
+
+func TestRetryDecorator_RetriesTransientErrors(t *testing.T) {
+	provider := &flakyProvider{
+		failuresLeft: 2,
+		err:          weathererr.NewExternalAPIError("upstream returned 502", nil),
+	}
+	decorator := NewRetryDecorator(provider, 3, time.Millisecond)
+
+	response, err := decorator.GetCurrentWeather(context.Background(), "London")
+
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, response.Temperature)
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestRetryDecorator_ExhaustedRetriesReturnError(t *testing.T) {
+	provider := &flakyProvider{
+		failuresLeft: 10,
+		err:          weathererr.NewExternalAPIError("upstream returned 503", nil),
+	}
+	decorator := NewRetryDecorator(provider, 2, time.Millisecond)
+
+	_, err := decorator.GetCurrentWeather(context.Background(), "London")
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, provider.calls) // initial attempt + 2 retries
+}
+
+func TestRetryDecorator_DoesNotRetryDomainErrors(t *testing.T) {
+	provider := &flakyProvider{
+		failuresLeft: 10,
+		err:          weathererr.NewNotFoundError("city not found"),
+	}
+	decorator := NewRetryDecorator(provider, 3, time.Millisecond)
+
+	_, err := decorator.GetCurrentWeather(context.Background(), "Nowhere")
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestRetryDecorator_CancelledContextAbortsRetries(t *testing.T) {
+	provider := &flakyProvider{
+		failuresLeft: 10,
+		err:          weathererr.NewExternalAPIError("upstream returned 502", nil),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	decorator := NewRetryDecoratorWithContext(ctx, provider, 3, time.Hour)
+
+	_, err := decorator.GetCurrentWeather(context.Background(), "London")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, provider.calls)
+}
@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"sync"
+
+	"weatherapi.app/models"
+)
+
+// flightCall is one in-progress weather fetch that concurrent callers for
+// the same key wait on
+type flightCall struct {
+	wg       sync.WaitGroup
+	response *models.WeatherResponse
+	err      error
+}
+
+// flightGroup coalesces concurrent fetches for the same key into a single
+// call, so a cold cache hit by many simultaneous requests reaches the
+// provider chain only once. It is a minimal in-process take on
+// golang.org/x/sync/singleflight, specialised to weather responses
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// Do runs fn for key unless another call for the same key is already in
+// flight, in which case it waits for that call and shares its result
+func (g *flightGroup) Do(key string, fn func() (*models.WeatherResponse, error)) (*models.WeatherResponse, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, inFlight := g.calls[key]; inFlight {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.response, call.err
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.response, call.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	call.wg.Done()
+
+	return call.response, call.err
+}
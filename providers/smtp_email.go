@@ -1,79 +1,320 @@
 package providers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net"
 	"net/smtp"
+	"net/textproto"
 	"strings"
+	"text/template"
+	"time"
 
 	"weatherapi.app/config"
-	"weatherapi.app/errors"
+	weathererr "weatherapi.app/errors"
 )
 
+// defaultSendRetryBaseDelay is the starting backoff delay between retries of
+// transiently failing sends
+const defaultSendRetryBaseDelay = 500 * time.Millisecond
+
 // SMTPEmailProvider implements EmailProvider using SMTP
 type SMTPEmailProvider struct {
-	smtpHost     string
-	smtpPort     int
-	smtpUsername string
-	smtpPassword string
-	fromName     string
-	fromAddress  string
+	smtpHost       string
+	smtpPort       int
+	smtpUsername   string
+	smtpPassword   string
+	fromName       string
+	fromNameTmpl   *template.Template // parsed when fromName carries template tokens
+	fromCity       string             // set on per-city copies; see ForCity
+	fromAddress    string
+	subjectPrefix  string
+	maxRetries     int
+	retryBaseDelay time.Duration
+	signer         *dkimSigner
+	pool           *smtpConnectionPool
 }
 
-// NewSMTPEmailProvider creates a new SMTP email provider
-func NewSMTPEmailProvider(config *config.EmailConfig) *SMTPEmailProvider {
-	return &SMTPEmailProvider{
-		smtpHost:     config.SMTPHost,
-		smtpPort:     config.SMTPPort,
-		smtpUsername: config.SMTPUsername,
-		smtpPassword: config.SMTPPassword,
-		fromName:     config.FromName,
-		fromAddress:  config.FromAddress,
+// NewSMTPEmailProvider creates a new SMTP email provider. It fails when DKIM
+// signing is configured but the private key cannot be loaded
+func NewSMTPEmailProvider(config *config.EmailConfig) (*SMTPEmailProvider, error) {
+	provider := &SMTPEmailProvider{
+		smtpHost:       config.SMTPHost,
+		smtpPort:       config.SMTPPort,
+		smtpUsername:   config.SMTPUsername,
+		smtpPassword:   config.SMTPPassword,
+		fromName:       config.FromName,
+		fromAddress:    config.FromAddress,
+		subjectPrefix:  config.SubjectPrefix,
+		maxRetries:     config.MaxRetries,
+		retryBaseDelay: defaultSendRetryBaseDelay,
+	}
+
+	// A From name like "Weather Updates ({{.City}})" is rendered per message;
+	// a plain name skips templating entirely
+	if strings.Contains(config.FromName, "{{") {
+		tmpl, err := template.New("from-name").Parse(config.FromName)
+		if err != nil {
+			return nil, fmt.Errorf("parse EMAIL_FROM_NAME template: %w", err)
+		}
+		provider.fromNameTmpl = tmpl
+	}
+
+	// DKIM signing is enabled by configuring a key; validation guarantees
+	// domain and selector come with it
+	if config.DKIMPrivateKeyPath != "" {
+		signer, err := newDKIMSigner(config.DKIMPrivateKeyPath, config.DKIMDomain, config.DKIMSelector)
+		if err != nil {
+			return nil, err
+		}
+		provider.signer = signer
 	}
+
+	// Pooling keeps connections open between sends during bulk update runs
+	if config.PoolSize > 0 {
+		provider.pool = newSMTPConnectionPool(
+			fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort),
+			config.SMTPHost,
+			provider.smtpAuth(),
+			config.PoolSize,
+			time.Duration(config.PoolIdleTimeoutSeconds)*time.Second,
+		)
+	}
+
+	return provider, nil
 }
 
-// validateSendEmailParams validates the input parameters for sending an email
+// smtpAuth returns plain authentication when credentials are configured and
+// nil otherwise, keeping the credential-less MailHog path working
+func (p *SMTPEmailProvider) smtpAuth() smtp.Auth {
+	if p.smtpUsername != "" && p.smtpPassword != "" {
+		return smtp.PlainAuth("", p.smtpUsername, p.smtpPassword, p.smtpHost)
+	}
+	return nil
+}
+
+// validateSendEmailParams validates the input parameters for sending an email.
+// A recipient with line breaks is rejected outright rather than silently
+// rewritten, since stripping would change the address
 func (p *SMTPEmailProvider) validateSendEmailParams(to, subject string) error {
 	if to == "" {
-		return errors.NewValidationError("recipient email cannot be empty")
+		return weathererr.NewValidationError("recipient email cannot be empty")
+	}
+	if strings.ContainsAny(to, "\r\n") {
+		return weathererr.NewValidationError("recipient email cannot contain line breaks")
 	}
 	if subject == "" {
-		return errors.NewValidationError("email subject cannot be empty")
+		return weathererr.NewValidationError("email subject cannot be empty")
 	}
 	return nil
 }
 
+// ForCity returns a provider whose From display name is rendered for the
+// given city. Without a templated From name (or without a city) the provider
+// itself is returned and nothing changes
+func (p *SMTPEmailProvider) ForCity(city string) EmailProvider {
+	if p.fromNameTmpl == nil || city == "" {
+		return p
+	}
+	scoped := *p
+	scoped.fromCity = city
+	return &scoped
+}
+
+// fromDisplayName renders the configured From name, substituting the city on
+// per-city copies. Line breaks are stripped so a crafted city name cannot
+// inject extra headers
+func (p *SMTPEmailProvider) fromDisplayName() string {
+	name := p.fromName
+	if p.fromNameTmpl != nil {
+		var rendered bytes.Buffer
+		if err := p.fromNameTmpl.Execute(&rendered, struct{ City string }{p.fromCity}); err == nil {
+			name = rendered.String()
+		}
+	}
+	return sanitizeHeader(name)
+}
+
+// sanitizeHeader removes CR and LF from a header value so no field, however
+// it was produced, can smuggle extra headers into the composed message
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// applySubjectPrefix prepends the configured environment tag to a subject.
+// A subject that already carries the prefix is left alone, so a message that
+// round-trips through the dead-letter queue is not tagged twice
+func (p *SMTPEmailProvider) applySubjectPrefix(subject string) string {
+	if p.subjectPrefix == "" || strings.HasPrefix(subject, p.subjectPrefix) {
+		return subject
+	}
+	return p.subjectPrefix + " " + subject
+}
+
+// messageHeaders builds the message headers shared by all outgoing emails.
+// Every value passes through sanitizeHeader to prevent header injection
+func (p *SMTPEmailProvider) messageHeaders(to, subject, contentType string) string {
+	from := fmt.Sprintf("%s <%s>", p.fromDisplayName(), sanitizeHeader(p.fromAddress))
+	return fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: %s\r\n\r\n",
+		from, sanitizeHeader(to), sanitizeHeader(p.applySubjectPrefix(subject)), contentType)
+}
+
 // SendEmail sends an email using SMTP
 func (p *SMTPEmailProvider) SendEmail(to, subject, body string, isHTML bool) error {
 	if err := p.validateSendEmailParams(to, subject); err != nil {
 		return err
 	}
 
-	// Only use authentication if username and password are provided
-	var auth smtp.Auth
-	if p.smtpUsername != "" && p.smtpPassword != "" {
-		auth = smtp.PlainAuth("", p.smtpUsername, p.smtpPassword, p.smtpHost)
+	contentType := "text/plain; charset=UTF-8"
+	if isHTML {
+		contentType = "text/html; charset=UTF-8"
 	}
 
-	mimeHeaders := "MIME-Version: 1.0\r\n"
-	contentType := "Content-Type: text/plain; charset=UTF-8\r\n"
-	if isHTML {
-		contentType = "Content-Type: text/html; charset=UTF-8\r\n"
+	return p.send(to, []byte(p.messageHeaders(to, subject, contentType)+body))
+}
+
+// SendMultipartEmail sends a multipart/alternative message carrying both a
+// plain-text and an HTML body. An empty text body degrades to a plain HTML
+// send
+func (p *SMTPEmailProvider) SendMultipartEmail(to, subject, htmlBody, textBody string) error {
+	if err := p.validateSendEmailParams(to, subject); err != nil {
+		return err
+	}
+	if textBody == "" {
+		return p.SendEmail(to, subject, htmlBody, true)
 	}
 
-	// Remove line breaks from subject to prevent header injection
-	subject = strings.ReplaceAll(strings.ReplaceAll(subject, "\r\n", ""), "\n", "")
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
 
-	from := fmt.Sprintf("%s <%s>", p.fromName, p.fromAddress)
-	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n%s%s\r\n",
-		from, to, subject, mimeHeaders, contentType)
+	// Text first: clients render the last part they support
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", textBody},
+		{"text/html; charset=UTF-8", htmlBody},
+	}
+	for _, part := range parts {
+		writer, err := alternative.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return weathererr.NewEmailError("failed to build email message", err)
+		}
+		if _, err := writer.Write([]byte(part.content)); err != nil {
+			return weathererr.NewEmailError("failed to build email message", err)
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return weathererr.NewEmailError("failed to build email message", err)
+	}
+
+	contentType := fmt.Sprintf("multipart/alternative; boundary=%q", alternative.Boundary())
+	return p.send(to, append([]byte(p.messageHeaders(to, subject, contentType)), body.Bytes()...))
+}
+
+// send delivers a fully assembled message, retrying transient failures with
+// exponential backoff up to maxRetries so a briefly unreachable SMTP server
+// does not lose the email. Permanent rejections fail immediately
+func (p *SMTPEmailProvider) send(to string, message []byte) error {
+	if p.signer != nil {
+		signed, err := p.signer.Sign(message)
+		if err != nil {
+			return err
+		}
+		message = signed
+	}
+
+	err := p.sendOnce(to, message)
+	for attempt := 1; attempt <= p.maxRetries && isTransientSendError(err); attempt++ {
+		slog.Warn("Retrying email send after transient failure", "to", to, "attempt", attempt, "error", err)
+		time.Sleep(p.retryBaseDelay << (attempt - 1))
+		err = p.sendOnce(to, message)
+	}
+	return err
+}
+
+// sendOnce attempts one delivery through the pool when one is configured and
+// over a one-shot connection otherwise
+func (p *SMTPEmailProvider) sendOnce(to string, message []byte) error {
+	if p.pool != nil {
+		return p.sendPooled(to, message)
+	}
 
-	message := headers + body
 	smtpAddr := fmt.Sprintf("%s:%d", p.smtpHost, p.smtpPort)
+	if err := smtp.SendMail(smtpAddr, p.smtpAuth(), p.fromAddress, []string{to}, message); err != nil {
+		return weathererr.NewEmailError("failed to send email", err)
+	}
+
+	return nil
+}
+
+// isTransientSendError reports whether a failed send is worth retrying:
+// network problems and 4xx server replies are transient, while 5xx replies
+// such as a rejected recipient or failed authentication are permanent
+func isTransientSendError(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
 
-	err := smtp.SendMail(smtpAddr, auth, p.fromAddress, []string{to}, []byte(message))
+// sendPooled sends through a pooled connection, retrying once on a fresh
+// connection since a pooled one may have been dropped by the server
+func (p *SMTPEmailProvider) sendPooled(to string, message []byte) error {
+	client, err := p.pool.get()
 	if err != nil {
-		return errors.NewEmailError("failed to send email", err)
+		return weathererr.NewEmailError("failed to connect to SMTP server", err)
 	}
 
+	if err := p.transmit(client, to, message); err != nil {
+		p.pool.discard(client)
+
+		client, dialErr := p.pool.dial()
+		if dialErr != nil {
+			return weathererr.NewEmailError("failed to send email", err)
+		}
+		if err := p.transmit(client, to, message); err != nil {
+			p.pool.discard(client)
+			return weathererr.NewEmailError("failed to send email", err)
+		}
+		p.pool.put(client)
+		return nil
+	}
+
+	p.pool.put(client)
 	return nil
 }
+
+// transmit runs a single mail transaction on an open SMTP connection
+func (p *SMTPEmailProvider) transmit(client *smtp.Client, to string, message []byte) error {
+	if err := client.Mail(p.fromAddress); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// Close releases any pooled SMTP connections; providers without pooling have
+// nothing to release
+func (p *SMTPEmailProvider) Close() {
+	if p.pool != nil {
+		p.pool.Close()
+	}
+}
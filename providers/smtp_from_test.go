@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPEmailProvider_PlainFromName(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	// A From name without template tokens behaves exactly as before, with
+	// or without a city scope
+	scoped := provider.ForCity("London")
+	assert.Same(t, provider, scoped)
+
+	require.NoError(t, provider.SendEmail("user@example.com", "Hello", "<p>Hi</p>", true))
+	assert.Contains(t, server.lastMessageData(), "From: Weather API <no-reply@weatherapi.app>")
+}
+
+func TestSMTPEmailProvider_TemplatedFromName(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	cfg := poolTestEmailConfig(t, server, 0)
+	cfg.FromName = "Weather Updates ({{.City}})"
+
+	provider, err := NewSMTPEmailProvider(cfg)
+	require.NoError(t, err)
+
+	t.Run("CityRendered", func(t *testing.T) {
+		scoped := provider.ForCity("London")
+		require.NoError(t, scoped.SendEmail("user@example.com", "Hello", "<p>Hi</p>", true))
+		assert.Contains(t, server.lastMessageData(), "From: Weather Updates (London) <no-reply@weatherapi.app>")
+	})
+
+	t.Run("WithoutCity", func(t *testing.T) {
+		// No city to scope to: the provider itself is reused
+		assert.Same(t, provider, provider.ForCity(""))
+	})
+
+	t.Run("HeaderInjectionStripped", func(t *testing.T) {
+		scoped := provider.ForCity("Lon\r\nBcc: attacker@example.com")
+		require.NoError(t, scoped.SendEmail("user@example.com", "Hello", "<p>Hi</p>", true))
+
+		message := server.lastMessageData()
+		assert.NotContains(t, message, "\r\nBcc:")
+		assert.Contains(t, message, "From: Weather Updates (LonBcc: attacker@example.com) <no-reply@weatherapi.app>")
+	})
+}
+
+func TestSMTPEmailProvider_InvalidFromNameTemplate(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	cfg := poolTestEmailConfig(t, server, 0)
+	cfg.FromName = "Weather Updates ({{.City"
+
+	provider, err := NewSMTPEmailProvider(cfg)
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+	assert.Contains(t, err.Error(), "EMAIL_FROM_NAME")
+}
@@ -0,0 +1,53 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestSanitizeHeader(t *testing.T) {
+	assert.Equal(t, "plain value", sanitizeHeader("plain value"))
+	assert.Equal(t, "splitvalue", sanitizeHeader("split\r\nvalue"))
+	assert.Equal(t, "subjectBcc: evil@x", sanitizeHeader("subject\r\nBcc: evil@x"))
+}
+
+func TestSMTPEmailProvider_SubjectInjectionStripped(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendEmail("user@example.com", "subject\r\nBcc: evil@x", "<p>Hi</p>", true))
+
+	message := server.lastMessageData()
+	assert.NotContains(t, message, "\r\nBcc:")
+	assert.Contains(t, message, "Subject: subjectBcc: evil@x")
+}
+
+func TestSMTPEmailProvider_MultipartSubjectInjectionStripped(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendMultipartEmail("user@example.com", "subject\r\nBcc: evil@x", "<p>Hi</p>", "Hi"))
+
+	message := server.lastMessageData()
+	assert.NotContains(t, message, "\r\nBcc:")
+	assert.Contains(t, message, "Subject: subjectBcc: evil@x")
+}
+
+func TestSMTPEmailProvider_RecipientWithLineBreaksRejected(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	err = provider.SendEmail("user@example.com\r\nBcc: evil@x", "Hello", "<p>Hi</p>", true)
+	require.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+}
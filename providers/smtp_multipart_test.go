@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestSMTPEmailProvider_SendMultipartEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	err = provider.SendMultipartEmail(
+		"user@example.com",
+		"Weather Update",
+		"<p>Sunny in <strong>London</strong></p>",
+		"Sunny in London",
+	)
+	require.NoError(t, err)
+
+	message := server.lastMessageData()
+	assert.Contains(t, message, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, message, "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, message, "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, message, "Sunny in London")
+	assert.Contains(t, message, "<p>Sunny in <strong>London</strong></p>")
+}
+
+func TestSMTPEmailProvider_SendMultipartEmailWithoutTextBody(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	err = provider.SendMultipartEmail("user@example.com", "Weather Update", "<p>Sunny</p>", "")
+	require.NoError(t, err)
+
+	message := server.lastMessageData()
+	assert.Contains(t, message, "Content-Type: text/html; charset=UTF-8")
+	assert.NotContains(t, message, "multipart/alternative")
+}
+
+func TestSMTPEmailProvider_SendMultipartEmailValidation(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	err = provider.SendMultipartEmail("", "Weather Update", "<p>Sunny</p>", "Sunny")
+	require.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+}
@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"crypto/tls"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// pooledSMTPConn wraps an SMTP client with the time it was last used so the
+// pool can discard connections the server may have dropped while idle
+type pooledSMTPConn struct {
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+// smtpConnectionPool keeps a bounded set of authenticated SMTP connections
+// open between sends so bulk update runs don't pay the dial, TLS, and auth
+// handshake for every single email
+type smtpConnectionPool struct {
+	addr        string
+	host        string
+	auth        smtp.Auth
+	size        int
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	conns  []*pooledSMTPConn
+	closed bool
+}
+
+func newSMTPConnectionPool(addr, host string, auth smtp.Auth, size int, idleTimeout time.Duration) *smtpConnectionPool {
+	return &smtpConnectionPool{
+		addr:        addr,
+		host:        host,
+		auth:        auth,
+		size:        size,
+		idleTimeout: idleTimeout,
+	}
+}
+
+// get returns a pooled connection, discarding any that have idled past the
+// timeout or no longer answer a health check, and dials a fresh one when
+// the pool is empty
+func (p *smtpConnectionPool) get() (*smtp.Client, error) {
+	for {
+		p.mu.Lock()
+		if len(p.conns) == 0 {
+			p.mu.Unlock()
+			return p.dial()
+		}
+		conn := p.conns[len(p.conns)-1]
+		p.conns = p.conns[:len(p.conns)-1]
+		p.mu.Unlock()
+
+		if time.Since(conn.lastUsed) >= p.idleTimeout {
+			_ = conn.client.Close()
+			continue
+		}
+
+		// The server may have silently dropped the connection while it sat
+		// idle; a NOOP catches that here rather than mid-send
+		if err := conn.client.Noop(); err != nil {
+			_ = conn.client.Close()
+			continue
+		}
+
+		return conn.client, nil
+	}
+}
+
+// dial opens and prepares a new SMTP connection, mirroring smtp.SendMail:
+// STARTTLS when the server offers it, then authentication when credentials
+// are configured (the credential-less MailHog path skips both)
+func (p *smtpConnectionPool) dial() (*smtp.Client, error) {
+	client, err := smtp.Dial(p.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: p.host, MinVersion: tls.VersionTLS12}); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
+
+	if p.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(p.auth); err != nil {
+				_ = client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return client, nil
+}
+
+// put returns a healthy connection to the pool for reuse, closing it instead
+// when the pool is already full or shut down
+func (p *smtpConnectionPool) put(client *smtp.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || len(p.conns) >= p.size {
+		_ = client.Quit()
+		return
+	}
+
+	p.conns = append(p.conns, &pooledSMTPConn{client: client, lastUsed: time.Now()})
+}
+
+// discard closes a connection that failed mid-send so it is never reused
+func (p *smtpConnectionPool) discard(client *smtp.Client) {
+	_ = client.Close()
+}
+
+// Close shuts the pool down, closing every idle connection
+func (p *smtpConnectionPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, conn := range p.conns {
+		_ = conn.client.Quit()
+	}
+	p.conns = nil
+}
@@ -0,0 +1,208 @@
+package providers
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+// fakeSMTPServer is a minimal SMTP server that counts connections and
+// accepted messages so tests can measure connection reuse, keeping the last
+// message body for content assertions
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	connections int
+	messages    int
+	lastMessage string
+
+	// mailFailures makes the server reject that many MAIL commands with
+	// mailFailureCode before accepting; -1 rejects them all
+	mailFailures    int
+	mailFailureCode string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener}
+	go server.acceptLoop()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		go s.serve(conn)
+	}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	writer := bufio.NewWriter(conn)
+	reader := bufio.NewReader(conn)
+
+	reply := func(line string) {
+		_, _ = writer.WriteString(line + "\r\n")
+		_ = writer.Flush()
+	}
+
+	reply("220 fake ESMTP ready")
+
+	inData := false
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if inData {
+			if line == "." {
+				inData = false
+				s.mu.Lock()
+				s.messages++
+				s.lastMessage = strings.Join(data, "\n")
+				s.mu.Unlock()
+				data = nil
+				reply("250 OK")
+			} else {
+				data = append(data, line)
+			}
+			continue
+		}
+
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(command, "MAIL"):
+			s.mu.Lock()
+			failing := s.mailFailures != 0
+			if s.mailFailures > 0 {
+				s.mailFailures--
+			}
+			code := s.mailFailureCode
+			s.mu.Unlock()
+
+			if failing {
+				reply(code)
+				continue
+			}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT"),
+			strings.HasPrefix(command, "RSET"), strings.HasPrefix(command, "NOOP"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "DATA"):
+			inData = true
+			reply("354 go ahead")
+		case strings.HasPrefix(command, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("500 unrecognized")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) stats() (connections, messages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections, s.messages
+}
+
+func (s *fakeSMTPServer) lastMessageData() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastMessage
+}
+
+func poolTestEmailConfig(t *testing.T, server *fakeSMTPServer, poolSize int) *config.EmailConfig {
+	t.Helper()
+
+	addr, ok := server.listener.Addr().(*net.TCPAddr)
+	require.True(t, ok)
+
+	return &config.EmailConfig{
+		SMTPHost:               "127.0.0.1",
+		SMTPPort:               addr.Port,
+		FromName:               "Weather API",
+		FromAddress:            "no-reply@weatherapi.app",
+		PoolSize:               poolSize,
+		PoolIdleTimeoutSeconds: 60,
+	}
+}
+
+func TestSMTPEmailProvider_PooledSendsReuseConnection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 2))
+	require.NoError(t, err)
+	defer provider.Close()
+
+	for i := 0; i < 3; i++ {
+		err := provider.SendEmail("user@example.com", "Weather Update", "<p>Sunny</p>", true)
+		require.NoError(t, err)
+	}
+
+	connections, messages := server.stats()
+	assert.Equal(t, 3, messages)
+	assert.Equal(t, 1, connections)
+}
+
+func TestSMTPEmailProvider_UnpooledSendsDialPerEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err := provider.SendEmail("user@example.com", "Weather Update", "<p>Sunny</p>", true)
+		require.NoError(t, err)
+	}
+
+	connections, messages := server.stats()
+	assert.Equal(t, 2, messages)
+	assert.Equal(t, 2, connections)
+}
+
+func TestSMTPEmailProvider_PooledSendRecoversFromDroppedConnection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 1))
+	require.NoError(t, err)
+	defer provider.Close()
+
+	require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "body", false))
+
+	// Kill the pooled connection behind the provider's back; the next send
+	// must discard it and retry on a fresh one
+	provider.pool.mu.Lock()
+	require.Len(t, provider.pool.conns, 1)
+	_ = provider.pool.conns[0].client.Close()
+	provider.pool.mu.Unlock()
+
+	require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "body", false))
+
+	_, messages := server.stats()
+	assert.Equal(t, 2, messages)
+}
@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retryTestProvider(t *testing.T, server *fakeSMTPServer, maxRetries int) *SMTPEmailProvider {
+	t.Helper()
+
+	config := poolTestEmailConfig(t, server, 0)
+	config.MaxRetries = maxRetries
+
+	provider, err := NewSMTPEmailProvider(config)
+	require.NoError(t, err)
+	provider.retryBaseDelay = time.Millisecond
+	return provider
+}
+
+func TestSMTPEmailProvider_RetriesTransientFailure(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.mailFailures = 1
+	server.mailFailureCode = "421 try again later"
+
+	provider := retryTestProvider(t, server, 3)
+
+	err := provider.SendEmail("user@example.com", "Weather Update", "body", false)
+	require.NoError(t, err)
+
+	connections, messages := server.stats()
+	assert.Equal(t, 2, connections)
+	assert.Equal(t, 1, messages)
+}
+
+func TestSMTPEmailProvider_DoesNotRetryPermanentFailure(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.mailFailures = -1
+	server.mailFailureCode = "550 no such user"
+
+	provider := retryTestProvider(t, server, 3)
+
+	err := provider.SendEmail("user@example.com", "Weather Update", "body", false)
+	require.Error(t, err)
+
+	connections, messages := server.stats()
+	assert.Equal(t, 1, connections)
+	assert.Equal(t, 0, messages)
+}
+
+func TestSMTPEmailProvider_GivesUpAfterMaxRetries(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.mailFailures = -1
+	server.mailFailureCode = "421 try again later"
+
+	provider := retryTestProvider(t, server, 2)
+
+	err := provider.SendEmail("user@example.com", "Weather Update", "body", false)
+	require.Error(t, err)
+
+	connections, messages := server.stats()
+	assert.Equal(t, 3, connections)
+	assert.Equal(t, 0, messages)
+}
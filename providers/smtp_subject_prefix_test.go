@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPEmailProvider_SubjectPrefix(t *testing.T) {
+	t.Run("PrefixApplied", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		cfg := poolTestEmailConfig(t, server, 0)
+		cfg.SubjectPrefix = "[STAGING]"
+
+		provider, err := NewSMTPEmailProvider(cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "<p>Hi</p>", true))
+		assert.Contains(t, server.lastMessageData(), "Subject: [STAGING] Weather Update")
+	})
+
+	t.Run("NoPrefixConfigured", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		provider, err := NewSMTPEmailProvider(poolTestEmailConfig(t, server, 0))
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "<p>Hi</p>", true))
+		assert.Contains(t, server.lastMessageData(), "Subject: Weather Update")
+	})
+
+	t.Run("NotDoubled", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		cfg := poolTestEmailConfig(t, server, 0)
+		cfg.SubjectPrefix = "[STAGING]"
+
+		provider, err := NewSMTPEmailProvider(cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SendEmail("user@example.com", "[STAGING] Weather Update", "<p>Hi</p>", true))
+
+		message := server.lastMessageData()
+		assert.Contains(t, message, "Subject: [STAGING] Weather Update")
+		assert.NotContains(t, message, "[STAGING] [STAGING]")
+	})
+
+	t.Run("PrefixSanitized", func(t *testing.T) {
+		server := newFakeSMTPServer(t)
+		cfg := poolTestEmailConfig(t, server, 0)
+		cfg.SubjectPrefix = "[STAGING]\r\nBcc: evil@x"
+
+		provider, err := NewSMTPEmailProvider(cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SendEmail("user@example.com", "Weather Update", "<p>Hi</p>", true))
+
+		message := server.lastMessageData()
+		assert.NotContains(t, message, "\r\nBcc:")
+		assert.Contains(t, message, "Subject: [STAGING]Bcc: evil@x Weather Update")
+	})
+}
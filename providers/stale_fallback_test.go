@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+// newStaleFallbackTestServer serves one good weatherapi response, then fails
+// every subsequent request with the given status code
+func newStaleFallbackTestServer(failStatus int, mu *sync.Mutex, hits *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*hits++
+		n := *hits
+		mu.Unlock()
+
+		if n > 1 {
+			w.WriteHeader(failStatus)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"current": {"temp_c": 21.0, "humidity": 76, "condition": {"text": "Sunny"}}}`))
+	}))
+}
+
+func newStaleFallbackTestManager(t *testing.T, serverURL string, serveStale bool) *ProviderManager {
+	t.Helper()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(serverURL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithCacheTTL(30 * time.Millisecond).
+		WithServeStaleOnError(serveStale).
+		Build()
+	require.NoError(t, err)
+
+	return manager
+}
+
+func TestProviderManager_ServeStaleOnError(t *testing.T) {
+	t.Run("ServesLastKnownGoodAfterProviderFailure", func(t *testing.T) {
+		var mu sync.Mutex
+		hits := 0
+		server := newStaleFallbackTestServer(http.StatusInternalServerError, &mu, &hits)
+		defer server.Close()
+
+		manager := newStaleFallbackTestManager(t, server.URL, true)
+
+		weather, err := manager.GetWeather(context.Background(), "London")
+		require.NoError(t, err)
+		assert.Equal(t, 21.0, weather.Temperature)
+		assert.False(t, weather.Stale)
+
+		// Past the TTL the fresh fetch fails; the last-known-good copy is
+		// served instead, flagged as stale
+		time.Sleep(60 * time.Millisecond)
+		weather, err = manager.GetWeather(context.Background(), "London")
+		require.NoError(t, err)
+		assert.Equal(t, 21.0, weather.Temperature)
+		assert.True(t, weather.Stale)
+	})
+
+	t.Run("DisabledPropagatesError", func(t *testing.T) {
+		var mu sync.Mutex
+		hits := 0
+		server := newStaleFallbackTestServer(http.StatusInternalServerError, &mu, &hits)
+		defer server.Close()
+
+		manager := newStaleFallbackTestManager(t, server.URL, false)
+
+		_, err := manager.GetWeather(context.Background(), "London")
+		require.NoError(t, err)
+
+		time.Sleep(60 * time.Millisecond)
+		_, err = manager.GetWeather(context.Background(), "London")
+		assert.Error(t, err)
+	})
+
+	t.Run("NotFoundIsNotMasked", func(t *testing.T) {
+		var mu sync.Mutex
+		hits := 0
+		server := newStaleFallbackTestServer(http.StatusNotFound, &mu, &hits)
+		defer server.Close()
+
+		manager := newStaleFallbackTestManager(t, server.URL, true)
+
+		_, err := manager.GetWeather(context.Background(), "London")
+		require.NoError(t, err)
+
+		// A city that stops resolving should surface not-found, not a stale
+		// copy of its old weather
+		time.Sleep(60 * time.Millisecond)
+		_, err = manager.GetWeather(context.Background(), "London")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+// StaticFileProvider serves weather data from a JSON file mapping city names
+// to weather responses. It is meant for local development without real API
+// keys; unlike the AccuWeather mock it never pretends to hit a network
+type StaticFileProvider struct {
+	path   string
+	cities map[string]models.WeatherResponse
+}
+
+// NewStaticFileProvider loads the city-to-weather map from the given JSON
+// file at startup; a missing or malformed file is a configuration error
+func NewStaticFileProvider(path string) (WeatherProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read static weather file: %w", err)
+	}
+
+	var cities map[string]models.WeatherResponse
+	if err := json.Unmarshal(data, &cities); err != nil {
+		return nil, fmt.Errorf("parse static weather file %s: %w", path, err)
+	}
+
+	normalized := make(map[string]models.WeatherResponse, len(cities))
+	for city, weather := range cities {
+		normalized[normalizeStaticCity(city)] = weather
+	}
+
+	return &StaticFileProvider{path: path, cities: normalized}, nil
+}
+
+// normalizeStaticCity makes lookups case- and whitespace-insensitive, matching
+// how cache keys treat city names
+func normalizeStaticCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+func (p *StaticFileProvider) GetCurrentWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+
+	weather, ok := p.cities[normalizeStaticCity(city)]
+	if !ok {
+		return nil, errors.NewNotFoundError("city not found")
+	}
+
+	response := weather
+	return &response, nil
+}
+
+// GetForecast is not supported by the static file provider; the chain falls
+// back to the next provider
+func (p *StaticFileProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	return nil, ErrForecastNotSupported
+}
+
+// Capabilities reports that the static file provider serves current
+// conditions only
+func (p *StaticFileProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Current: true}
+}
@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+func writeStaticWeatherFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "weather.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestStaticFileProvider_GetCurrentWeather(t *testing.T) {
+	path := writeStaticWeatherFile(t, `{
+		"London": {"temperature": 15.0, "humidity": 76, "description": "Partly cloudy"},
+		"Kyiv": {"temperature": 8.5, "humidity": 60, "description": "Clear"}
+	}`)
+
+	provider, err := NewStaticFileProvider(path)
+	require.NoError(t, err)
+
+	t.Run("KnownCity", func(t *testing.T) {
+		weather, err := provider.GetCurrentWeather(context.Background(), "London")
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Equal(t, 15.0, weather.Temperature)
+		assert.Equal(t, 76.0, weather.Humidity)
+		assert.Equal(t, "Partly cloudy", weather.Description)
+	})
+
+	t.Run("CaseInsensitiveLookup", func(t *testing.T) {
+		weather, err := provider.GetCurrentWeather(context.Background(), "  kyiv ")
+		assert.NoError(t, err)
+		require.NotNil(t, weather)
+		assert.Equal(t, 8.5, weather.Temperature)
+	})
+
+	t.Run("UnknownCity", func(t *testing.T) {
+		weather, err := provider.GetCurrentWeather(context.Background(), "Atlantis")
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		weather, err := provider.GetCurrentWeather(context.Background(), "")
+		assert.Error(t, err)
+		assert.Nil(t, weather)
+	})
+
+	t.Run("ForecastNotSupported", func(t *testing.T) {
+		forecast, err := provider.GetForecast("London", 3)
+		assert.ErrorIs(t, err, ErrForecastNotSupported)
+		assert.Nil(t, forecast)
+	})
+}
+
+func TestStaticFileProvider_LoadErrors(t *testing.T) {
+	t.Run("MissingFile", func(t *testing.T) {
+		provider, err := NewStaticFileProvider(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		path := writeStaticWeatherFile(t, `{"London": `)
+		provider, err := NewStaticFileProvider(path)
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+}
+
+func TestProviderManager_StaticFileInChain(t *testing.T) {
+	path := writeStaticWeatherFile(t, `{"London": {"temperature": 12.0, "humidity": 80, "description": "Rainy"}}`)
+
+	manager, err := NewProviderManagerBuilder().
+		WithStaticFile(path).
+		WithProviderOrder([]string{"static"}).
+		WithLoggingEnabled(false).
+		WithCacheConfig(nil).
+		Build()
+	require.NoError(t, err)
+
+	weather, err := manager.GetWeather(context.Background(), "London")
+	assert.NoError(t, err)
+	require.NotNil(t, weather)
+	assert.Equal(t, 12.0, weather.Temperature)
+
+	_, err = manager.GetWeather(context.Background(), "Atlantis")
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+// newSWRTestServer serves weatherapi responses whose temperature equals the
+// hit count, so tests can tell which fetch produced a response
+func newSWRTestServer(hits *int, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*hits++
+		n := *hits
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"current": {"temp_c": %d.0, "humidity": 76, "condition": {"text": "Sunny"}}}`, n)
+	}))
+}
+
+func newSWRTestManager(t *testing.T, serverURL string, ttl time.Duration) *ProviderManager {
+	t.Helper()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(serverURL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithCacheTTL(ttl).
+		WithCacheMode(CacheModeSWR).
+		WithCacheMaxStaleness(time.Minute).
+		Build()
+	require.NoError(t, err)
+
+	return manager
+}
+
+func TestProviderManager_SWRServesStaleAndRefreshes(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := newSWRTestServer(&hits, &mu)
+	defer server.Close()
+
+	manager := newSWRTestManager(t, server.URL, 30*time.Millisecond)
+
+	// Cold cache: fetched inline
+	weather, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, weather.Temperature)
+
+	// Past the TTL the stale entry is served immediately, not refetched inline
+	time.Sleep(60 * time.Millisecond)
+	weather, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, weather.Temperature)
+
+	// The background refresh replaces the entry
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits == 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		weather, err := manager.GetWeather(context.Background(), "London")
+		return err == nil && weather.Temperature == 2.0
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestProviderManager_SWRSingleRefreshPerKey(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		n := hits
+		mu.Unlock()
+
+		// Block refresh fetches so several stale reads overlap one refresh
+		if n > 1 {
+			<-release
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"current": {"temp_c": %d.0, "humidity": 76, "condition": {"text": "Sunny"}}}`, n)
+	}))
+	defer server.Close()
+
+	manager := newSWRTestManager(t, server.URL, 30*time.Millisecond)
+
+	_, err := manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// Both stale reads return immediately; only one background refresh starts
+	for i := 0; i < 2; i++ {
+		weather, err := manager.GetWeather(context.Background(), "London")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, weather.Temperature)
+	}
+
+	close(release)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, hits)
+}
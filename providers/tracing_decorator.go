@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"context"
+
+	"weatherapi.app/models"
+	"weatherapi.app/tracing"
+)
+
+// WeatherTracingDecorator records a span per provider attempt so a traced
+// GetWeather call shows which providers were tried and how each one fared
+type WeatherTracingDecorator struct {
+	wrappedProvider WeatherProvider
+	tracer          tracing.Tracer
+	providerName    string
+}
+
+func NewWeatherTracingDecorator(provider WeatherProvider, tracer tracing.Tracer, providerName string) WeatherProvider {
+	return &WeatherTracingDecorator{
+		wrappedProvider: provider,
+		tracer:          tracer,
+		providerName:    providerName,
+	}
+}
+
+func (d *WeatherTracingDecorator) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	ctx, span := d.tracer.StartSpan(ctx, "provider.GetCurrentWeather")
+	defer span.End()
+	span.SetAttribute("provider", d.providerName)
+	span.SetAttribute("city", city)
+
+	response, err := d.wrappedProvider.GetCurrentWeather(ctx, city)
+	if err != nil {
+		span.SetAttribute("outcome", "error")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttribute("outcome", "success")
+	return response, nil
+}
+
+func (d *WeatherTracingDecorator) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	_, span := d.tracer.StartSpan(context.Background(), "provider.GetForecast")
+	defer span.End()
+	span.SetAttribute("provider", d.providerName)
+	span.SetAttribute("city", city)
+	span.SetAttribute("days", days)
+
+	response, err := d.wrappedProvider.GetForecast(city, days)
+	if err != nil {
+		span.SetAttribute("outcome", "error")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttribute("outcome", "success")
+	return response, nil
+}
+
+// Capabilities reports the wrapped provider's capabilities
+func (d *WeatherTracingDecorator) Capabilities() ProviderCapabilities {
+	return providerCapabilities(d.wrappedProvider)
+}
@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	"weatherapi.app/tracing"
+)
+
+// recordingTracer captures finished spans so tests can assert on names and
+// attributes
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	tracer *recordingTracer
+	name   string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, tracing.Span) {
+	return ctx, &recordedSpan{tracer: t, name: name, attrs: make(map[string]interface{})}
+}
+
+func (t *recordingTracer) finished(name string) []*recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []*recordedSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			matches = append(matches, span)
+		}
+	}
+	return matches
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordedSpan) RecordError(err error)                      { s.err = err }
+func (s *recordedSpan) End() {
+	s.ended = true
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, s)
+	s.tracer.mu.Unlock()
+}
+
+func TestProviderManager_TracingSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"current": {
+				"temp_c": 15.0,
+				"humidity": 76,
+				"condition": {"text": "Partly cloudy"}
+			}
+		}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		WithTracer(tracer).
+		Build()
+	require.NoError(t, err)
+
+	// First lookup misses the cache and goes through the provider
+	_, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	rootSpans := tracer.finished("ProviderManager.GetWeather")
+	require.Len(t, rootSpans, 1)
+	assert.Equal(t, "London", rootSpans[0].attrs["city"])
+	assert.NoError(t, rootSpans[0].err)
+
+	cacheSpans := tracer.finished("ProviderManager.cacheLookup")
+	require.Len(t, cacheSpans, 1)
+	assert.Equal(t, false, cacheSpans[0].attrs["cache.hit"])
+
+	attemptSpans := tracer.finished("provider.GetCurrentWeather")
+	require.Len(t, attemptSpans, 1)
+	assert.Equal(t, "WeatherAPI", attemptSpans[0].attrs["provider"])
+	assert.Equal(t, "success", attemptSpans[0].attrs["outcome"])
+
+	// Second lookup is served from cache: no new provider attempt span
+	_, err = manager.GetWeather(context.Background(), "London")
+	require.NoError(t, err)
+
+	cacheSpans = tracer.finished("ProviderManager.cacheLookup")
+	require.Len(t, cacheSpans, 2)
+	assert.Equal(t, true, cacheSpans[1].attrs["cache.hit"])
+	assert.Len(t, tracer.finished("provider.GetCurrentWeather"), 1)
+}
+
+func TestProviderManager_TracingRecordsFailedAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(nil).
+		WithTracer(tracer).
+		Build()
+	require.NoError(t, err)
+
+	_, err = manager.GetWeather(context.Background(), "NoSuchCity")
+	assert.Error(t, err)
+
+	attemptSpans := tracer.finished("provider.GetCurrentWeather")
+	require.Len(t, attemptSpans, 1)
+	assert.Equal(t, "error", attemptSpans[0].attrs["outcome"])
+	assert.Error(t, attemptSpans[0].err)
+
+	rootSpans := tracer.finished("ProviderManager.GetWeather")
+	require.Len(t, rootSpans, 1)
+	assert.Error(t, rootSpans[0].err)
+}
+
+func TestProviderManager_NoTracerBehavesAsToday(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"current": {"temp_c": 10.0, "humidity": 50, "condition": {"text": "Sunny"}}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(nil).
+		Build()
+	require.NoError(t, err)
+
+	weather, err := manager.GetWeather(context.Background(), "London")
+	assert.NoError(t, err)
+	assert.NotNil(t, weather)
+}
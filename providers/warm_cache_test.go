@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+)
+
+func TestProviderManager_WarmCache_PopulatesCache(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"current": {
+				"temp_c": 15.0,
+				"humidity": 76,
+				"condition": {"text": "Partly cloudy"}
+			}
+		}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		Build()
+	require.NoError(t, err)
+
+	err = manager.WarmCache(context.Background(), []string{"London", "Kyiv"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+
+	// Subsequent lookups are served from the warmed cache
+	_, err = manager.GetWeather(context.Background(), "London")
+	assert.NoError(t, err)
+	_, err = manager.GetWeather(context.Background(), "Kyiv")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, hits)
+}
+
+func TestProviderManager_WarmCache_CancelledContext(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		WithCacheConfig(&config.CacheConfig{Type: CacheTypeMemory.String()}).
+		Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = manager.WarmCache(ctx, []string{"London", "Kyiv"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, hits)
+}
+
+func TestProviderManager_WarmCache_CachingDisabled(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager, err := NewProviderManagerBuilder().
+		WithWeatherAPIKey("test-key").
+		WithWeatherAPIBaseURL(server.URL).
+		WithLoggingEnabled(false).
+		WithProviderOrder([]string{"weatherapi"}).
+		Build()
+	require.NoError(t, err)
+
+	err = manager.WarmCache(context.Background(), []string{"London"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, hits)
+}
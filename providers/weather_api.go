@@ -1,9 +1,11 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"weatherapi.app/config"
@@ -18,29 +20,65 @@ const (
 
 // WeatherAPIProvider implements WeatherProvider for WeatherAPI.com
 type WeatherAPIProvider struct {
-	apiKey  string
+	apiKeys []string
+	nextKey atomic.Uint32
 	baseURL string
 	client  *http.Client
 }
 
 // NewWeatherAPIProvider creates a new WeatherAPI.com provider
 func NewWeatherAPIProvider(config *config.WeatherConfig) *WeatherAPIProvider {
+	timeout := DefaultHTTPTimeout
+	if config.ProviderTimeoutSeconds > 0 {
+		timeout = time.Duration(config.ProviderTimeoutSeconds) * time.Second
+	}
+
+	keys := config.WeatherAPIKeys()
+	if len(keys) == 0 {
+		keys = []string{config.APIKey}
+	}
+
 	return &WeatherAPIProvider{
-		apiKey:  config.APIKey,
+		apiKeys: keys,
 		baseURL: config.BaseURL,
-		client:  &http.Client{Timeout: DefaultHTTPTimeout},
+		client:  newProviderHTTPClient(timeout, config.UserAgent, config.ExtraHeaders),
 	}
 }
 
+// apiKey returns the key for the next request. With several keys configured
+// requests rotate round-robin, so the retry that follows a rate-limit
+// response goes out with a different key
+func (p *WeatherAPIProvider) apiKey() string {
+	if len(p.apiKeys) == 1 {
+		return p.apiKeys[0]
+	}
+	return p.apiKeys[int(p.nextKey.Add(1)-1)%len(p.apiKeys)]
+}
+
+// KeyCount reports how many API keys the provider rotates across
+func (p *WeatherAPIProvider) KeyCount() int {
+	return len(p.apiKeys)
+}
+
 // GetCurrentWeather retrieves weather data from WeatherAPI.com
-func (p *WeatherAPIProvider) GetCurrentWeather(city string) (*models.WeatherResponse, error) {
+func (p *WeatherAPIProvider) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	if city == "" {
 		return nil, errors.NewValidationError("city cannot be empty")
 	}
 
-	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", p.baseURL, p.apiKey, city)
+	url := fmt.Sprintf("%s/current.json?key=%s&q=%s&aqi=no", p.baseURL, p.apiKey(), city)
 
-	resp, err := p.client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.NewExternalAPIError("failed to build weather request", err)
+	}
+
+	cf := conditionalFetchFrom(ctx)
+	if cf != nil {
+		cf.applyTo(req)
+	}
+
+	resp, err := p.client.Do(req)
 	if err != nil {
 		return nil, errors.NewExternalAPIError("failed to get weather data", err)
 	}
@@ -48,14 +86,30 @@ func (p *WeatherAPIProvider) GetCurrentWeather(city string) (*models.WeatherResp
 		_ = resp.Body.Close() // Explicitly ignore close error
 	}()
 
+	// 304 means the validators we sent still match; the caller keeps its
+	// cached response
+	if cf != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, errors.NewNotFoundError("city not found")
 	}
 
+	// A rate-limited key stays in rotation; the retry that follows goes
+	// out with the next key
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, errors.NewRateLimitError("weather API rate limit exceeded", parseRetryAfter(resp))
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, errors.NewExternalAPIError(fmt.Sprintf("weather API returned status code %d", resp.StatusCode), nil)
 	}
 
+	if cf != nil {
+		cf.capture(resp)
+	}
+
 	var result map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, errors.NewExternalAPIError("failed to decode weather data", err)
@@ -76,7 +130,7 @@ func (p *WeatherAPIProvider) GetCurrentWeather(city string) (*models.WeatherResp
 		return nil, errors.NewExternalAPIError("invalid weather data format: missing temperature", nil)
 	}
 
-	humidity, ok := current["humidity"].(float64)
+	humidity, ok := parseHumidityValue(current["humidity"])
 	if !ok {
 		return nil, errors.NewExternalAPIError("invalid weather data format: missing humidity", nil)
 	}
@@ -86,9 +140,99 @@ func (p *WeatherAPIProvider) GetCurrentWeather(city string) (*models.WeatherResp
 		return nil, errors.NewExternalAPIError("invalid weather data format: missing description", nil)
 	}
 
-	return &models.WeatherResponse{
+	response := &models.WeatherResponse{
 		Temperature: temperature,
 		Humidity:    humidity,
 		Description: description,
-	}, nil
+	}
+
+	// last_updated_epoch reports when the provider observed these conditions
+	if epoch, ok := current["last_updated_epoch"].(float64); ok && epoch > 0 {
+		observedAt := time.Unix(int64(epoch), 0).UTC()
+		response.ProviderObservedAt = &observedAt
+	}
+
+	return response, nil
+}
+
+// weatherAPIForecastResponse represents the relevant parts of the
+// WeatherAPI.com /forecast.json response
+type weatherAPIForecastResponse struct {
+	Forecast struct {
+		ForecastDay []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC    float64       `json:"maxtemp_c"`
+				MinTempC    float64       `json:"mintemp_c"`
+				AvgHumidity FlexibleFloat `json:"avghumidity"`
+				Condition   struct {
+					Text string `json:"text"`
+				} `json:"condition"`
+			} `json:"day"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+}
+
+// Capabilities reports that WeatherAPI.com serves both current conditions
+// and forecasts
+func (p *WeatherAPIProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Current: true, Forecast: true}
+}
+
+// GetForecast retrieves a multi-day forecast from WeatherAPI.com
+func (p *WeatherAPIProvider) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+	if days < 1 || days > MaxForecastDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("days must be between 1 and %d", MaxForecastDays))
+	}
+
+	url := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=%d&aqi=no&alerts=no", p.baseURL, p.apiKey(), city, days)
+
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, errors.NewExternalAPIError("failed to get forecast data", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() // Explicitly ignore close error
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.NewNotFoundError("city not found")
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, errors.NewRateLimitError("weather API rate limit exceeded", parseRetryAfter(resp))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewExternalAPIError(fmt.Sprintf("weather API returned status code %d", resp.StatusCode), nil)
+	}
+
+	var result weatherAPIForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.NewExternalAPIError("failed to decode forecast data", err)
+	}
+
+	if len(result.Forecast.ForecastDay) == 0 {
+		return nil, errors.NewExternalAPIError("invalid forecast data format: missing forecast days", nil)
+	}
+
+	forecast := &models.ForecastResponse{
+		City: city,
+		Days: make([]models.ForecastDay, 0, len(result.Forecast.ForecastDay)),
+	}
+
+	for _, day := range result.Forecast.ForecastDay {
+		forecast.Days = append(forecast.Days, models.ForecastDay{
+			Date:        day.Date,
+			MinTemp:     day.Day.MinTempC,
+			MaxTemp:     day.Day.MaxTempC,
+			Humidity:    float64(day.Day.AvgHumidity),
+			Description: day.Day.Condition.Text,
+		})
+	}
+
+	return forecast, nil
 }
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	"weatherapi.app/models"
@@ -20,11 +21,11 @@ func NewWeatherLoggerDecorator(provider WeatherProvider, logger FileLogger, prov
 	}
 }
 
-func (d *WeatherLoggerDecorator) GetCurrentWeather(city string) (*models.WeatherResponse, error) {
+func (d *WeatherLoggerDecorator) GetCurrentWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	d.logger.LogRequest(d.providerName, city)
 	startTime := time.Now()
 
-	response, err := d.wrappedProvider.GetCurrentWeather(city)
+	response, err := d.wrappedProvider.GetCurrentWeather(ctx, city)
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -36,6 +37,27 @@ func (d *WeatherLoggerDecorator) GetCurrentWeather(city string) (*models.Weather
 	return response, nil
 }
 
+func (d *WeatherLoggerDecorator) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	d.logger.LogRequest(d.providerName, city)
+	startTime := time.Now()
+
+	response, err := d.wrappedProvider.GetForecast(city, days)
+	duration := time.Since(startTime)
+
+	if err != nil {
+		d.logger.LogError(d.providerName, city, err, duration)
+		return nil, err
+	}
+
+	d.logger.LogResponse(d.providerName, city, nil, duration)
+	return response, nil
+}
+
+// Capabilities reports the wrapped provider's capabilities
+func (d *WeatherLoggerDecorator) Capabilities() ProviderCapabilities {
+	return providerCapabilities(d.wrappedProvider)
+}
+
 type WeatherChainLoggerDecorator struct {
 	wrappedChain WeatherProviderChain
 	logger       FileLogger
@@ -49,11 +71,11 @@ func NewWeatherChainLoggerDecorator(chain WeatherProviderChain, logger FileLogge
 	}
 }
 
-func (d *WeatherChainLoggerDecorator) Handle(city string) (*models.WeatherResponse, error) {
+func (d *WeatherChainLoggerDecorator) Handle(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	d.logger.LogRequest("WeatherChain", city)
 	startTime := time.Now()
 
-	response, err := d.wrappedChain.Handle(city)
+	response, err := d.wrappedChain.Handle(ctx, city)
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -65,6 +87,23 @@ func (d *WeatherChainLoggerDecorator) Handle(city string) (*models.WeatherRespon
 	return response, nil
 }
 
+// HandleForecast delegates forecast requests to the wrapped chain with logging
+func (d *WeatherChainLoggerDecorator) HandleForecast(city string, days int) (*models.ForecastResponse, error) {
+	d.logger.LogRequest("WeatherChain", city)
+	startTime := time.Now()
+
+	response, err := d.wrappedChain.HandleForecast(city, days)
+	duration := time.Since(startTime)
+
+	if err != nil {
+		d.logger.LogError("WeatherChain", city, err, duration)
+		return nil, err
+	}
+
+	d.logger.LogResponse("WeatherChain", city, nil, duration)
+	return response, nil
+}
+
 // SetNext delegates to the wrapped chain
 func (d *WeatherChainLoggerDecorator) SetNext(handler WeatherProviderChain) {
 	d.wrappedChain.SetNext(handler)
@@ -89,11 +128,11 @@ func NewMultiProviderLoggerDecorator(chain WeatherProviderChain, logger FileLogg
 }
 
 // Handle logs each provider attempt individually
-func (d *MultiProviderLoggerDecorator) Handle(city string) (*models.WeatherResponse, error) {
-	return d.handleWithLogging(d.wrappedChain, city)
+func (d *MultiProviderLoggerDecorator) Handle(ctx context.Context, city string) (*models.WeatherResponse, error) {
+	return d.handleWithLogging(ctx, d.wrappedChain, city)
 }
 
-func (d *MultiProviderLoggerDecorator) handleWithLogging(handler WeatherProviderChain, city string) (*models.WeatherResponse, error) {
+func (d *MultiProviderLoggerDecorator) handleWithLogging(ctx context.Context, handler WeatherProviderChain, city string) (*models.WeatherResponse, error) {
 	if handler == nil {
 		return nil, nil
 	}
@@ -102,20 +141,25 @@ func (d *MultiProviderLoggerDecorator) handleWithLogging(handler WeatherProvider
 	d.logger.LogRequest(providerName, city)
 	startTime := time.Now()
 
-	response, err := d.tryCurrentHandler(handler, city)
+	response, err := d.tryCurrentHandler(ctx, handler, city)
 	duration := time.Since(startTime)
 
 	if err != nil {
 		d.logger.LogError(providerName, city, err, duration)
-		return handler.Handle(city)
+		return handler.Handle(ctx, city)
 	}
 
 	d.logger.LogResponse(providerName, city, response, duration)
 	return response, nil
 }
 
-func (d *MultiProviderLoggerDecorator) tryCurrentHandler(handler WeatherProviderChain, city string) (*models.WeatherResponse, error) {
-	return handler.Handle(city)
+func (d *MultiProviderLoggerDecorator) tryCurrentHandler(ctx context.Context, handler WeatherProviderChain, city string) (*models.WeatherResponse, error) {
+	return handler.Handle(ctx, city)
+}
+
+// HandleForecast delegates forecast requests to the wrapped chain
+func (d *MultiProviderLoggerDecorator) HandleForecast(city string, days int) (*models.ForecastResponse, error) {
+	return d.wrappedChain.HandleForecast(city, days)
 }
 
 // SetNext delegates to the wrapped chain
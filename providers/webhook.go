@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"weatherapi.app/errors"
+)
+
+const (
+	// WebhookSignatureHeader carries the HMAC of the delivery so recipients
+	// can verify the payload came from us
+	WebhookSignatureHeader = "X-Signature"
+
+	// WebhookTimestampHeader carries the unix time the delivery was signed;
+	// recipients should reject stale timestamps to prevent replays
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+// SignWebhookPayload computes the signature header value for a delivery:
+// "sha256=" followed by the hex HMAC-SHA256 of "<unix timestamp>.<body>"
+// keyed by the subscription's signing secret. Covering the timestamp binds
+// the signature to one point in time, so a captured delivery cannot be
+// replayed later with a fresh timestamp
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookProvider delivers notification payloads to subscriber-supplied HTTP
+// endpoints, signing each request with the subscription's secret
+type WebhookProvider struct {
+	client *http.Client
+}
+
+// NewWebhookProvider creates a webhook provider with the default HTTP timeout
+func NewWebhookProvider() *WebhookProvider {
+	return &WebhookProvider{
+		client: &http.Client{Timeout: DefaultHTTPTimeout},
+	}
+}
+
+// SendWebhook POSTs payload to url. When secret is non-empty the request
+// carries X-Webhook-Timestamp and X-Signature headers computed by
+// SignWebhookPayload; an empty secret sends the payload unsigned
+func (p *WebhookProvider) SendWebhook(url string, payload []byte, secret string) error {
+	if url == "" {
+		return errors.NewValidationError("webhook URL cannot be empty")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.NewValidationError(fmt.Sprintf("invalid webhook URL: %s", url))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if secret != "" {
+		now := time.Now()
+		req.Header.Set(WebhookTimestampHeader, fmt.Sprintf("%d", now.Unix()))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, now, payload))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.NewExternalAPIError("webhook delivery failed", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() // Explicitly ignore close error
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.NewExternalAPIError(fmt.Sprintf("webhook endpoint returned status %d", resp.StatusCode), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	weathererr "weatherapi.app/errors"
+)
+
+func TestSignWebhookPayload_KnownVector(t *testing.T) {
+	signature := SignWebhookPayload(
+		"test-secret",
+		time.Unix(1700000000, 0),
+		[]byte(`{"event":"weather_update"}`),
+	)
+
+	assert.Equal(t, "sha256=9949c62a5539ef5a4565c4484cefedfd3b073082a10b85c7835beefbd646a53c", signature)
+}
+
+func TestWebhookProvider_SendWebhook_Signed(t *testing.T) {
+	payload := []byte(`{"event":"weather_update","city":"London"}`)
+
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotTimestamp = r.Header.Get(WebhookTimestampHeader)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		gotBody = body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider := NewWebhookProvider()
+	err := provider.SendWebhook(server.URL, payload, "test-secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, payload, gotBody)
+
+	// The recipient verifies by recomputing the HMAC over the received
+	// timestamp and body
+	unix, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload("test-secret", time.Unix(unix, 0), payload), gotSignature)
+}
+
+func TestWebhookProvider_SendWebhook_EmptySecretUnsigned(t *testing.T) {
+	var gotSignature, gotTimestamp string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotTimestamp = r.Header.Get(WebhookTimestampHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider := NewWebhookProvider()
+	err := provider.SendWebhook(server.URL, []byte(`{}`), "")
+
+	require.NoError(t, err)
+	assert.Empty(t, gotSignature)
+	assert.Empty(t, gotTimestamp)
+}
+
+func TestWebhookProvider_SendWebhook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	provider := NewWebhookProvider()
+	err := provider.SendWebhook(server.URL, []byte(`{}`), "test-secret")
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ExternalAPIError, appErr.Type)
+}
+
+func TestWebhookProvider_SendWebhook_EmptyURL(t *testing.T) {
+	provider := NewWebhookProvider()
+	err := provider.SendWebhook("", []byte(`{}`), "test-secret")
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+}
@@ -1,18 +1,45 @@
 package repository
 
 import (
+	stderrors "errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"weatherapi.app/errors"
 	"weatherapi.app/models"
 )
 
-// SubscriptionRepository handles data access operations for subscriptions
+// IsUniqueViolation reports whether err is a unique-constraint violation
+// from the database. GORM only maps these to ErrDuplicatedKey when the
+// dialector has error translation enabled, so the raw driver messages for
+// sqlite, postgres and mysql are matched as a fallback
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if stderrors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "unique constraint") || strings.Contains(message, "duplicate entry")
+}
+
+// SubscriptionRepository handles data access operations for subscriptions.
+// When a read replica is attached, the bulk read-only queries — the
+// scheduler feeds (GetSubscriptionsForUpdates, GetConfirmedByFrequencyBatched,
+// AlertCities, GetAlertSubscriptionsForCity), the city lookups
+// (DistinctCities, HasConfirmedSubscriptionForCity) and the admin reporting
+// queries (List, StreamConfirmed, CountByFrequency, CountConfirmed) — run
+// on it. Point lookups like FindByEmail and FindByID stay on the primary
+// because their callers write based on what they read and must not see
+// replica lag
 type SubscriptionRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	readDB *gorm.DB
 }
 
 // NewSubscriptionRepository creates a new repository for subscription data
@@ -20,6 +47,22 @@ func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
 	return &SubscriptionRepository{db: db}
 }
 
+// NewSubscriptionRepositoryWithReplica creates a repository that routes
+// read-only queries to the replica connection and everything else to the
+// primary
+func NewSubscriptionRepositoryWithReplica(db, replica *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db, readDB: replica}
+}
+
+// reader returns the handle read-only queries run on: the replica when one
+// is attached, the primary otherwise
+func (r *SubscriptionRepository) reader() *gorm.DB {
+	if r.readDB != nil {
+		return r.readDB
+	}
+	return r.db
+}
+
 // validateEmailAndCity validates that both email and city are not empty
 func (r *SubscriptionRepository) validateEmailAndCity(email, city string) error {
 	if email == "" {
@@ -78,6 +121,47 @@ func (r *SubscriptionRepository) FindByEmail(email, city string) (*models.Subscr
 	return &subscription, nil
 }
 
+// FindAllByEmail retrieves every subscription for an email address across
+// all cities
+func (r *SubscriptionRepository) FindAllByEmail(email string) ([]models.Subscription, error) {
+	slog.Debug("Finding all subscriptions by email", "email", email)
+
+	if email == "" {
+		return nil, errors.NewValidationError("email cannot be empty")
+	}
+
+	var subscriptions []models.Subscription
+	result := r.db.Where("email = ?", email).Find(&subscriptions)
+	if result.Error != nil {
+		slog.Error("Database error when finding subscriptions by email", "error", result.Error, "email", email)
+		return nil, errors.NewDatabaseError("failed to find subscriptions by email", result.Error)
+	}
+
+	slog.Debug("Found subscriptions by email", "count", len(subscriptions), "email", email)
+	return subscriptions, nil
+}
+
+// CountByEmail counts every subscription an email address holds across all
+// cities, confirmed or pending; the subscribe flow uses it to enforce the
+// per-email cap
+func (r *SubscriptionRepository) CountByEmail(email string) (int64, error) {
+	slog.Debug("Counting subscriptions by email", "email", email)
+
+	if email == "" {
+		return 0, errors.NewValidationError("email cannot be empty")
+	}
+
+	var count int64
+	result := r.db.Model(&models.Subscription{}).Where("email = ?", email).Count(&count)
+	if result.Error != nil {
+		slog.Error("Database error when counting subscriptions by email", "error", result.Error, "email", email)
+		return 0, errors.NewDatabaseError("failed to count subscriptions by email", result.Error)
+	}
+
+	slog.Debug("Counted subscriptions by email", "count", count, "email", email)
+	return count, nil
+}
+
 // FindByID retrieves a subscription by its ID
 func (r *SubscriptionRepository) FindByID(id uint) (*models.Subscription, error) {
 	slog.Debug("Finding subscription by ID", "id", id)
@@ -108,8 +192,16 @@ func (r *SubscriptionRepository) Create(subscription *models.Subscription) error
 
 	slog.Debug("Creating subscription", "email", subscription.Email, "city", subscription.City)
 
+	if subscription.Version == 0 {
+		subscription.Version = 1
+	}
+
 	result := r.db.Create(subscription)
 	if result.Error != nil {
+		if IsUniqueViolation(result.Error) {
+			slog.Warn("Duplicate subscription rejected by unique index", "email", subscription.Email, "city", subscription.City)
+			return errors.NewAlreadyExistsError("subscription already exists for this email and city")
+		}
 		slog.Error("Database error when creating subscription", "error", result.Error, "email", subscription.Email)
 		return errors.NewDatabaseError("failed to create subscription", result.Error)
 	}
@@ -118,21 +210,41 @@ func (r *SubscriptionRepository) Create(subscription *models.Subscription) error
 	return nil
 }
 
-// Update modifies an existing subscription
+// Update modifies an existing subscription using optimistic locking: the
+// write only applies while the row still carries the version the caller
+// read, so a concurrent modification surfaces as a conflict the caller can
+// retry instead of silently losing data
 func (r *SubscriptionRepository) Update(subscription *models.Subscription) error {
 	if err := r.validateSubscription(subscription); err != nil {
 		return err
 	}
 
-	slog.Debug("Updating subscription", "id", subscription.ID, "email", subscription.Email, "city", subscription.City)
+	slog.Debug("Updating subscription", "id", subscription.ID, "email", subscription.Email, "city", subscription.City, "version", subscription.Version)
+
+	readVersion := subscription.Version
+	subscription.Version = readVersion + 1
 
-	result := r.db.Save(subscription)
+	result := r.db.Model(subscription).
+		Where("version = ?", readVersion).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(subscription)
 	if result.Error != nil {
+		subscription.Version = readVersion
+		if IsUniqueViolation(result.Error) {
+			slog.Warn("Duplicate subscription rejected by unique index", "email", subscription.Email, "city", subscription.City)
+			return errors.NewAlreadyExistsError("subscription already exists for this email and city")
+		}
 		slog.Error("Database error when updating subscription", "error", result.Error, "id", subscription.ID)
 		return errors.NewDatabaseError("failed to update subscription", result.Error)
 	}
+	if result.RowsAffected == 0 {
+		subscription.Version = readVersion
+		slog.Warn("Stale subscription update rejected", "id", subscription.ID, "version", readVersion)
+		return errors.NewConflictError("subscription was modified concurrently")
+	}
 
-	slog.Debug("Updated subscription successfully", "id", subscription.ID)
+	slog.Debug("Updated subscription successfully", "id", subscription.ID, "version", subscription.Version)
 	return nil
 }
 
@@ -154,6 +266,21 @@ func (r *SubscriptionRepository) Delete(subscription *models.Subscription) error
 	return nil
 }
 
+// DeleteExpiredUnconfirmed removes unconfirmed subscriptions created before
+// olderThan, returning how many were deleted
+func (r *SubscriptionRepository) DeleteExpiredUnconfirmed(olderThan time.Time) (int64, error) {
+	slog.Debug("Deleting expired unconfirmed subscriptions", "olderThan", olderThan)
+
+	result := r.db.Where("confirmed = ? AND created_at < ?", false, olderThan).Delete(&models.Subscription{})
+	if result.Error != nil {
+		slog.Error("Database error when deleting expired unconfirmed subscriptions", "error", result.Error)
+		return 0, errors.NewDatabaseError("failed to delete expired unconfirmed subscriptions", result.Error)
+	}
+
+	slog.Debug("Deleted expired unconfirmed subscriptions", "count", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
 // GetSubscriptionsForUpdates retrieves all confirmed subscriptions for a specific frequency
 func (r *SubscriptionRepository) GetSubscriptionsForUpdates(frequency string) ([]models.Subscription, error) {
 	slog.Debug("Getting subscriptions for updates", "frequency", frequency)
@@ -163,7 +290,7 @@ func (r *SubscriptionRepository) GetSubscriptionsForUpdates(frequency string) ([
 	}
 
 	var subscriptions []models.Subscription
-	result := r.db.Where("frequency = ? AND confirmed = ?", frequency, true).Find(&subscriptions)
+	result := r.reader().Where("frequency = ? AND confirmed = ?", frequency, true).Find(&subscriptions)
 	if result.Error != nil {
 		slog.Error("Database error when getting subscriptions for updates", "error", result.Error, "frequency", frequency)
 		return nil, errors.NewDatabaseError("failed to get subscriptions for updates", result.Error)
@@ -173,14 +300,392 @@ func (r *SubscriptionRepository) GetSubscriptionsForUpdates(frequency string) ([
 	return subscriptions, nil
 }
 
+// GetConfirmedByFrequencyBatched streams confirmed subscriptions for a
+// frequency to fn in batches of batchSize, using keyset pagination on ID so
+// large lists are never loaded into memory at once. Processing stops at the
+// first error fn returns
+func (r *SubscriptionRepository) GetConfirmedByFrequencyBatched(frequency string, batchSize int, fn func([]models.Subscription) error) error {
+	slog.Debug("Streaming subscriptions for updates", "frequency", frequency, "batchSize", batchSize)
+
+	if err := r.validateFrequency(frequency); err != nil {
+		return err
+	}
+	if batchSize < 1 {
+		return errors.NewValidationError("batch size must be positive")
+	}
+
+	var lastID uint
+	for {
+		var batch []models.Subscription
+		result := r.reader().Where("frequency = ? AND confirmed = ? AND id > ?", frequency, true, lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&batch)
+		if result.Error != nil {
+			slog.Error("Database error when streaming subscriptions for updates", "error", result.Error, "frequency", frequency)
+			return errors.NewDatabaseError("failed to get subscriptions for updates", result.Error)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// StreamConfirmed streams every confirmed subscription to fn in batches of
+// batchSize, using keyset pagination on ID so exports of large datasets keep
+// memory bounded. Processing stops at the first error fn returns
+func (r *SubscriptionRepository) StreamConfirmed(batchSize int, fn func([]models.Subscription) error) error {
+	slog.Debug("Streaming confirmed subscriptions", "batchSize", batchSize)
+
+	if batchSize < 1 {
+		return errors.NewValidationError("batch size must be positive")
+	}
+
+	var lastID uint
+	for {
+		var batch []models.Subscription
+		result := r.reader().Where("confirmed = ? AND id > ?", true, lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&batch)
+		if result.Error != nil {
+			slog.Error("Database error when streaming confirmed subscriptions", "error", result.Error)
+			return errors.NewDatabaseError("failed to stream confirmed subscriptions", result.Error)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// HasConfirmedSubscriptionForCity reports whether at least one confirmed
+// subscription exists for the given city
+func (r *SubscriptionRepository) HasConfirmedSubscriptionForCity(city string) (bool, error) {
+	slog.Debug("Checking for confirmed subscriptions", "city", city)
+
+	if city == "" {
+		return false, errors.NewValidationError("city cannot be empty")
+	}
+
+	var count int64
+	result := r.reader().Model(&models.Subscription{}).
+		Where("LOWER(city) = LOWER(?) AND confirmed = ?", city, true).
+		Count(&count)
+	if result.Error != nil {
+		slog.Error("Database error when checking subscriptions for city", "error", result.Error, "city", city)
+		return false, errors.NewDatabaseError("failed to check subscriptions for city", result.Error)
+	}
+
+	return count > 0, nil
+}
+
+// AlertCities lists every distinct city with at least one confirmed alert
+// subscription; the alert run fetches weather once per city
+func (r *SubscriptionRepository) AlertCities() ([]string, error) {
+	slog.Debug("Listing distinct alert subscription cities")
+
+	var cities []string
+	result := r.reader().Model(&models.Subscription{}).
+		Where("frequency = ? AND confirmed = ?", models.FrequencyAlert.String(), true).
+		Distinct().
+		Pluck("city", &cities)
+	if result.Error != nil {
+		slog.Error("Database error when listing alert cities", "error", result.Error)
+		return nil, errors.NewDatabaseError("failed to list alert cities", result.Error)
+	}
+
+	slog.Debug("Found alert cities", "count", len(cities))
+	return cities, nil
+}
+
+// GetAlertSubscriptionsForCity retrieves the confirmed alert subscriptions
+// for a city so their thresholds can be evaluated against current weather
+func (r *SubscriptionRepository) GetAlertSubscriptionsForCity(city string) ([]models.Subscription, error) {
+	slog.Debug("Getting alert subscriptions", "city", city)
+
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+
+	var subscriptions []models.Subscription
+	result := r.reader().Where("frequency = ? AND confirmed = ? AND LOWER(city) = LOWER(?)",
+		models.FrequencyAlert.String(), true, city).Find(&subscriptions)
+	if result.Error != nil {
+		slog.Error("Database error when getting alert subscriptions", "error", result.Error, "city", city)
+		return nil, errors.NewDatabaseError("failed to get alert subscriptions", result.Error)
+	}
+
+	slog.Debug("Found alert subscriptions", "count", len(subscriptions), "city", city)
+	return subscriptions, nil
+}
+
+// DistinctCities lists every distinct city with at least one subscription;
+// used to pre-warm the weather cache at startup
+func (r *SubscriptionRepository) DistinctCities() ([]string, error) {
+	slog.Debug("Listing distinct subscription cities")
+
+	var cities []string
+	result := r.reader().Model(&models.Subscription{}).Distinct().Pluck("city", &cities)
+	if result.Error != nil {
+		slog.Error("Database error when listing distinct cities", "error", result.Error)
+		return nil, errors.NewDatabaseError("failed to list distinct cities", result.Error)
+	}
+
+	slog.Debug("Found distinct cities", "count", len(cities))
+	return cities, nil
+}
+
+// TouchLastNotified stamps when a subscriber was last emailed. Only that
+// column is written so concurrent changes to the rest of the row are not
+// clobbered
+func (r *SubscriptionRepository) TouchLastNotified(id uint, at time.Time) error {
+	if err := r.validateID(id); err != nil {
+		return err
+	}
+
+	result := r.db.Model(&models.Subscription{}).Where("id = ?", id).UpdateColumn("last_notified_at", at)
+	if result.Error != nil {
+		slog.Error("Database error when recording last notified time", "error", result.Error, "id", id)
+		return errors.NewDatabaseError("failed to record last notified time", result.Error)
+	}
+
+	return nil
+}
+
+// CountByFrequency tallies subscriptions per frequency for the admin stats
+// endpoint
+func (r *SubscriptionRepository) CountByFrequency() (map[string]int64, error) {
+	slog.Debug("Counting subscriptions by frequency")
+
+	var rows []struct {
+		Frequency string
+		Count     int64
+	}
+	result := r.reader().Model(&models.Subscription{}).
+		Select("frequency, COUNT(*) as count").
+		Group("frequency").
+		Scan(&rows)
+	if result.Error != nil {
+		slog.Error("Database error when counting subscriptions by frequency", "error", result.Error)
+		return nil, errors.NewDatabaseError("failed to count subscriptions by frequency", result.Error)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Frequency] = row.Count
+	}
+
+	slog.Debug("Counted subscriptions by frequency", "frequencies", len(counts))
+	return counts, nil
+}
+
+// CountConfirmed tallies confirmed subscriptions for the admin stats endpoint
+func (r *SubscriptionRepository) CountConfirmed() (int64, error) {
+	slog.Debug("Counting confirmed subscriptions")
+
+	var count int64
+	result := r.reader().Model(&models.Subscription{}).Where("confirmed = ?", true).Count(&count)
+	if result.Error != nil {
+		slog.Error("Database error when counting confirmed subscriptions", "error", result.Error)
+		return 0, errors.NewDatabaseError("failed to count confirmed subscriptions", result.Error)
+	}
+
+	return count, nil
+}
+
+// List retrieves a page of subscriptions ordered by ID along with the total
+// count matching the filter; confirmedOnly restricts the listing to
+// confirmed subscriptions
+func (r *SubscriptionRepository) List(offset, limit int, confirmedOnly bool) ([]models.Subscription, int64, error) {
+	slog.Debug("Listing subscriptions", "offset", offset, "limit", limit, "confirmedOnly", confirmedOnly)
+
+	if offset < 0 {
+		return nil, 0, errors.NewValidationError("offset cannot be negative")
+	}
+	if limit < 1 {
+		return nil, 0, errors.NewValidationError("limit must be positive")
+	}
+
+	query := r.reader().Model(&models.Subscription{})
+	if confirmedOnly {
+		query = query.Where("confirmed = ?", true)
+	}
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		slog.Error("Database error when counting subscriptions", "error", result.Error)
+		return nil, 0, errors.NewDatabaseError("failed to count subscriptions", result.Error)
+	}
+
+	var subscriptions []models.Subscription
+	if result := query.Order("id").Offset(offset).Limit(limit).Find(&subscriptions); result.Error != nil {
+		slog.Error("Database error when listing subscriptions", "error", result.Error)
+		return nil, 0, errors.NewDatabaseError("failed to list subscriptions", result.Error)
+	}
+
+	slog.Debug("Listed subscriptions", "count", len(subscriptions), "total", total)
+	return subscriptions, total, nil
+}
+
+// IdempotencyKeyTTL is how long a processed subscribe response can be
+// replayed before the key expires
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyKeyRepository handles data access operations for idempotency keys
+type IdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new repository for idempotency keys
+func NewIdempotencyKeyRepository(db *gorm.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// validateScope validates the key and the email+city scope it belongs to
+func (r *IdempotencyKeyRepository) validateScope(key, email, city string) error {
+	if key == "" {
+		return errors.NewValidationError("idempotency key cannot be empty")
+	}
+	if email == "" {
+		return errors.NewValidationError("email cannot be empty")
+	}
+	if city == "" {
+		return errors.NewValidationError("city cannot be empty")
+	}
+	return nil
+}
+
+// Find retrieves the stored response for a key scoped to email and city;
+// a missing or expired key returns nil
+func (r *IdempotencyKeyRepository) Find(key, email, city string) (*models.IdempotencyKey, error) {
+	slog.Debug("Finding idempotency key", "key", key, "email", email, "city", city)
+
+	if err := r.validateScope(key, email, city); err != nil {
+		return nil, err
+	}
+
+	var record models.IdempotencyKey
+	result := r.db.Where("key = ? AND email = ? AND city = ? AND expires_at > ?", key, email, city, time.Now()).First(&record)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		slog.Error("Database error when finding idempotency key", "error", result.Error, "key", key)
+		return nil, errors.NewDatabaseError("failed to find idempotency key", result.Error)
+	}
+
+	slog.Debug("Found idempotency key", "id", record.ID, "key", key)
+	return &record, nil
+}
+
+// Save records a processed response for later replay
+func (r *IdempotencyKeyRepository) Save(key, email, city string, statusCode int, body string) error {
+	slog.Debug("Saving idempotency key", "key", key, "email", email, "city", city, "status", statusCode)
+
+	if err := r.validateScope(key, email, city); err != nil {
+		return err
+	}
+
+	record := &models.IdempotencyKey{
+		Key:        key,
+		Email:      email,
+		City:       city,
+		StatusCode: statusCode,
+		Body:       body,
+		ExpiresAt:  time.Now().Add(IdempotencyKeyTTL),
+	}
+
+	result := r.db.Create(record)
+	if result.Error != nil {
+		slog.Error("Database error when saving idempotency key", "error", result.Error, "key", key)
+		return errors.NewDatabaseError("failed to save idempotency key", result.Error)
+	}
+
+	slog.Debug("Saved idempotency key", "id", record.ID, "key", key)
+	return nil
+}
+
+// DeleteExpired removes all expired idempotency keys and returns how many
+// were deleted
+func (r *IdempotencyKeyRepository) DeleteExpired() (int64, error) {
+	slog.Debug("Deleting expired idempotency keys")
+
+	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		slog.Error("Database error when deleting expired idempotency keys", "error", result.Error)
+		return 0, errors.NewDatabaseError("failed to delete expired idempotency keys", result.Error)
+	}
+
+	slog.Debug("Deleted expired idempotency keys", "count", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
+// NotificationRunRepository records weather update runs per send window so
+// a restarted scheduler can tell a fresh window from one already in progress
+type NotificationRunRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRunRepository creates a new repository for notification runs
+func NewNotificationRunRepository(db *gorm.DB) *NotificationRunRepository {
+	return &NotificationRunRepository{db: db}
+}
+
+// RecordRun inserts the (frequency, window) record, reporting whether this
+// run is the first in the window; a conflict on the unique pair means an
+// earlier run already started it
+func (r *NotificationRunRepository) RecordRun(frequency string, windowStart time.Time) (bool, error) {
+	slog.Debug("Recording notification run", "frequency", frequency, "windowStart", windowStart)
+
+	if frequency == "" {
+		return false, errors.NewValidationError("frequency cannot be empty")
+	}
+	if windowStart.IsZero() {
+		return false, errors.NewValidationError("window start cannot be zero")
+	}
+
+	run := &models.NotificationRun{Frequency: frequency, WindowStart: windowStart}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if result.Error != nil {
+		slog.Error("Database error when recording notification run", "error", result.Error, "frequency", frequency)
+		return false, errors.NewDatabaseError("failed to record notification run", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// TokenGenerator produces token string values; overridable in tests
+type TokenGenerator func() string
+
 // TokenRepository handles data access operations for authentication tokens
 type TokenRepository struct {
-	db *gorm.DB
+	db            *gorm.DB
+	generateToken TokenGenerator
 }
 
-// NewTokenRepository creates a new repository for token operations
+// NewTokenRepository creates a new repository for token operations using
+// UUID token values
 func NewTokenRepository(db *gorm.DB) *TokenRepository {
-	return &TokenRepository{db: db}
+	return NewTokenRepositoryWithGenerator(db, nil)
+}
+
+// NewTokenRepositoryWithGenerator creates a repository with a custom token
+// generator; a nil generator falls back to UUIDs
+func NewTokenRepositoryWithGenerator(db *gorm.DB, generator TokenGenerator) *TokenRepository {
+	if generator == nil {
+		generator = func() string { return uuid.New().String() }
+	}
+	return &TokenRepository{db: db, generateToken: generator}
 }
 
 // CreateTokenParams holds parameters for creating a token
@@ -235,7 +740,7 @@ func (r *TokenRepository) CreateToken(subscriptionID uint, tokenType string, exp
 	}
 
 	token := &models.Token{
-		Token:          uuid.New().String(),
+		Token:          r.generateToken(),
 		SubscriptionID: subscriptionID,
 		Type:           tokenType,
 		ExpiresAt:      time.Now().Add(expiresIn),
@@ -251,6 +756,38 @@ func (r *TokenRepository) CreateToken(subscriptionID uint, tokenType string, exp
 	return token, nil
 }
 
+// CreateEmailToken generates and stores a token scoped to an email address
+// rather than a single subscription, such as "unsubscribe_all" tokens
+func (r *TokenRepository) CreateEmailToken(email, tokenType string, expiresIn time.Duration) (*models.Token, error) {
+	slog.Debug("Creating email-scoped token", "email", email, "type", tokenType, "expiresIn", expiresIn)
+
+	if email == "" {
+		return nil, errors.NewValidationError("email cannot be empty")
+	}
+	if tokenType == "" {
+		return nil, errors.NewValidationError("token type cannot be empty")
+	}
+	if expiresIn <= 0 {
+		return nil, errors.NewValidationError("expiration duration must be positive")
+	}
+
+	token := &models.Token{
+		Token:     r.generateToken(),
+		Email:     email,
+		Type:      tokenType,
+		ExpiresAt: time.Now().Add(expiresIn),
+	}
+
+	result := r.db.Create(token)
+	if result.Error != nil {
+		slog.Error("Database error when creating email-scoped token", "error", result.Error, "email", email)
+		return nil, errors.NewDatabaseError("failed to create token", result.Error)
+	}
+
+	slog.Debug("Created email-scoped token", "tokenID", token.ID, "type", token.Type, "email", email)
+	return token, nil
+}
+
 // FindByToken retrieves a token by its string value
 func (r *TokenRepository) FindByToken(tokenStr string) (*models.Token, error) {
 	slog.Debug("Finding token by value", "token", tokenStr)
@@ -316,16 +853,130 @@ func (r *TokenRepository) FindBySubscriptionIDAndType(subscriptionID uint, token
 	return &token, nil
 }
 
-// DeleteExpiredTokens removes all expired tokens from the database
-func (r *TokenRepository) DeleteExpiredTokens() error {
+// DeleteExpiredTokens removes all expired tokens from the database and
+// returns how many were deleted
+func (r *TokenRepository) DeleteExpiredTokens() (int64, error) {
 	slog.Debug("Deleting expired tokens")
 
 	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.Token{})
 	if result.Error != nil {
 		slog.Error("Database error when deleting expired tokens", "error", result.Error)
-		return errors.NewDatabaseError("failed to delete expired tokens", result.Error)
+		return 0, errors.NewDatabaseError("failed to delete expired tokens", result.Error)
 	}
 
 	slog.Debug("Deleted expired tokens", "count", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
+// FailedEmailRepository is a dead-letter log of emails that failed all send
+// retries, kept for operator inspection
+type FailedEmailRepository struct {
+	db *gorm.DB
+}
+
+// NewFailedEmailRepository creates a new failed email repository
+func NewFailedEmailRepository(db *gorm.DB) *FailedEmailRepository {
+	return &FailedEmailRepository{db: db}
+}
+
+// Record stores one terminally failed send
+func (r *FailedEmailRepository) Record(to, subject, reason string) error {
+	slog.Debug("Recording failed email", "to", to, "subject", subject)
+
+	if to == "" {
+		return errors.NewValidationError("recipient email cannot be empty")
+	}
+
+	failedEmail := models.FailedEmail{To: to, Subject: subject, Reason: reason}
+	if result := r.db.Create(&failedEmail); result.Error != nil {
+		slog.Error("Database error when recording failed email", "error", result.Error, "to", to)
+		return errors.NewDatabaseError("failed to record failed email", result.Error)
+	}
+
+	return nil
+}
+
+// List retrieves a page of failed emails, most recent first, along with the
+// total count
+func (r *FailedEmailRepository) List(offset, limit int) ([]models.FailedEmail, int64, error) {
+	slog.Debug("Listing failed emails", "offset", offset, "limit", limit)
+
+	if offset < 0 {
+		return nil, 0, errors.NewValidationError("offset cannot be negative")
+	}
+	if limit < 1 {
+		return nil, 0, errors.NewValidationError("limit must be positive")
+	}
+
+	var total int64
+	if result := r.db.Model(&models.FailedEmail{}).Count(&total); result.Error != nil {
+		slog.Error("Database error when counting failed emails", "error", result.Error)
+		return nil, 0, errors.NewDatabaseError("failed to count failed emails", result.Error)
+	}
+
+	var failedEmails []models.FailedEmail
+	if result := r.db.Order("id DESC").Offset(offset).Limit(limit).Find(&failedEmails); result.Error != nil {
+		slog.Error("Database error when listing failed emails", "error", result.Error)
+		return nil, 0, errors.NewDatabaseError("failed to list failed emails", result.Error)
+	}
+
+	return failedEmails, total, nil
+}
+
+// ObservationRepository stores snapshots of successful weather fetches and
+// serves them back as per-city history
+type ObservationRepository struct {
+	db *gorm.DB
+}
+
+// NewObservationRepository creates a new observation repository
+func NewObservationRepository(db *gorm.DB) *ObservationRepository {
+	return &ObservationRepository{db: db}
+}
+
+// Save stores one successful weather fetch for a city
+func (r *ObservationRepository) Save(city string, weather *models.WeatherResponse) error {
+	slog.Debug("Saving weather observation", "city", city)
+
+	if city == "" {
+		return errors.NewValidationError("city cannot be empty")
+	}
+	if weather == nil {
+		return errors.NewValidationError("weather data cannot be nil")
+	}
+
+	observation := models.WeatherObservation{
+		City:        city,
+		Temperature: weather.Temperature,
+		Humidity:    weather.Humidity,
+		Description: weather.Description,
+	}
+	if result := r.db.Create(&observation); result.Error != nil {
+		slog.Error("Database error when saving weather observation", "error", result.Error, "city", city)
+		return errors.NewDatabaseError("failed to save weather observation", result.Error)
+	}
+
 	return nil
 }
+
+// FindByCity retrieves observations for a city recorded at or after since,
+// oldest first
+func (r *ObservationRepository) FindByCity(city string, since time.Time) ([]models.WeatherObservation, error) {
+	slog.Debug("Finding weather observations", "city", city, "since", since)
+
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+
+	var observations []models.WeatherObservation
+	result := r.db.
+		Where("LOWER(city) = LOWER(?) AND created_at >= ?", city, since).
+		Order("id ASC").
+		Find(&observations)
+	if result.Error != nil {
+		slog.Error("Database error when finding weather observations", "error", result.Error, "city", city)
+		return nil, errors.NewDatabaseError("failed to find weather observations", result.Error)
+	}
+
+	return observations, nil
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -18,7 +21,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Subscription{}, &models.Token{})
+	err = db.AutoMigrate(&models.Subscription{}, &models.Token{}, &models.IdempotencyKey{}, &models.FailedEmail{}, &models.NotificationRun{}, &models.WeatherObservation{})
 	assert.NoError(t, err)
 
 	return db
@@ -85,6 +88,109 @@ func TestSubscriptionRepository_FindByEmail(t *testing.T) {
 	})
 }
 
+func TestSubscriptionRepository_FindAllByEmail(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	t.Run("ValidInput_FoundAllCities", func(t *testing.T) {
+		subscriptions := []models.Subscription{
+			{Email: "multi@example.com", City: "London", Frequency: "daily", Confirmed: true},
+			{Email: "multi@example.com", City: "Paris", Frequency: "hourly", Confirmed: false},
+			{Email: "other@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		}
+		for i := range subscriptions {
+			assert.NoError(t, db.Create(&subscriptions[i]).Error)
+		}
+
+		subs, err := repo.FindAllByEmail("multi@example.com")
+		assert.NoError(t, err)
+		assert.Len(t, subs, 2)
+		for _, sub := range subs {
+			assert.Equal(t, "multi@example.com", sub.Email)
+		}
+	})
+
+	t.Run("ValidInput_NotFound", func(t *testing.T) {
+		subs, err := repo.FindAllByEmail("nonexistent@example.com")
+		assert.NoError(t, err)
+		assert.Empty(t, subs)
+	})
+
+	t.Run("EmptyEmail", func(t *testing.T) {
+		subs, err := repo.FindAllByEmail("")
+		assert.Error(t, err)
+		assert.Nil(t, subs)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+		assert.Contains(t, appErr.Message, "email cannot be empty")
+	})
+}
+
+func TestSubscriptionRepository_DeleteExpiredUnconfirmed(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+	subscriptions := []models.Subscription{
+		{Email: "stale@example.com", City: "London", Frequency: "daily", Confirmed: false, CreatedAt: oldTime},
+		{Email: "confirmed@example.com", City: "London", Frequency: "daily", Confirmed: true, CreatedAt: oldTime},
+		{Email: "fresh@example.com", City: "Paris", Frequency: "daily", Confirmed: false},
+	}
+	for i := range subscriptions {
+		assert.NoError(t, db.Create(&subscriptions[i]).Error)
+	}
+
+	deleted, err := repo.DeleteExpiredUnconfirmed(time.Now().Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	// The stale unconfirmed row is gone; the confirmed and recent ones remain
+	stale, err := repo.FindByEmail("stale@example.com", "London")
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+
+	confirmed, err := repo.FindByEmail("confirmed@example.com", "London")
+	assert.NoError(t, err)
+	assert.NotNil(t, confirmed)
+
+	fresh, err := repo.FindByEmail("fresh@example.com", "Paris")
+	assert.NoError(t, err)
+	assert.NotNil(t, fresh)
+}
+
+func TestSubscriptionRepository_DistinctCities(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	t.Run("NoSubscriptions", func(t *testing.T) {
+		cities, err := repo.DistinctCities()
+		assert.NoError(t, err)
+		assert.Empty(t, cities)
+	})
+
+	t.Run("DeduplicatesCities", func(t *testing.T) {
+		subscriptions := []models.Subscription{
+			{Email: "one@example.com", City: "London", Frequency: "daily", Confirmed: true},
+			{Email: "two@example.com", City: "London", Frequency: "hourly", Confirmed: false},
+			{Email: "three@example.com", City: "Kyiv", Frequency: "daily", Confirmed: true},
+		}
+		for i := range subscriptions {
+			assert.NoError(t, db.Create(&subscriptions[i]).Error)
+		}
+
+		cities, err := repo.DistinctCities()
+		assert.NoError(t, err)
+		assert.Len(t, cities, 2)
+		assert.Contains(t, cities, "London")
+		assert.Contains(t, cities, "Kyiv")
+	})
+}
+
 func TestSubscriptionRepository_FindByID(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewSubscriptionRepository(db)
@@ -167,6 +273,124 @@ func TestSubscriptionRepository_Create(t *testing.T) {
 	})
 }
 
+// TestSubscriptionRepository_Create_ConcurrentDuplicates races two creates
+// for the same email and city: the unique index must let exactly one row
+// through and the loser must surface as an already-exists error
+func TestSubscriptionRepository_Create_ConcurrentDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	// A single connection serializes the inserts; separate :memory:
+	// connections would each see their own empty database
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	results := make(chan error, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- repo.Create(&models.Subscription{
+				Email:     "race@example.com",
+				City:      "London",
+				Frequency: "daily",
+			})
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var succeeded, rejected int
+	for err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.AlreadyExistsError, appErr.Type)
+		rejected++
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, rejected)
+
+	var count int64
+	db.Model(&models.Subscription{}).Where("email = ? AND city = ?", "race@example.com", "London").Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestSubscriptionRepository_ReadReplicaRouting attaches a separate database
+// as the replica and checks that bulk reads hit it while point lookups and
+// writes stay on the primary
+func TestSubscriptionRepository_ReadReplicaRouting(t *testing.T) {
+	primary := setupTestDB(t)
+	replica := setupTestDB(t)
+	repo := NewSubscriptionRepositoryWithReplica(primary, replica)
+
+	onPrimary := &models.Subscription{Email: "primary@example.com", City: "London", Frequency: "daily", Confirmed: true}
+	assert.NoError(t, primary.Create(onPrimary).Error)
+
+	onReplica := &models.Subscription{Email: "replica@example.com", City: "Paris", Frequency: "daily", Confirmed: true}
+	assert.NoError(t, replica.Create(onReplica).Error)
+
+	alertOnPrimary := &models.Subscription{Email: "primary-alert@example.com", City: "Oslo", Frequency: "alert", Confirmed: true}
+	assert.NoError(t, primary.Create(alertOnPrimary).Error)
+
+	alertOnReplica := &models.Subscription{Email: "replica-alert@example.com", City: "Kyiv", Frequency: "alert", Confirmed: true}
+	assert.NoError(t, replica.Create(alertOnReplica).Error)
+
+	// Bulk read sees only what the replica holds
+	subscriptions, err := repo.GetSubscriptionsForUpdates("daily")
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, "replica@example.com", subscriptions[0].Email)
+
+	// The alert scheduler feeds read from the replica too
+	alertCities, err := repo.AlertCities()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Kyiv"}, alertCities)
+
+	alertSubscriptions, err := repo.GetAlertSubscriptionsForCity("Kyiv")
+	assert.NoError(t, err)
+	assert.Len(t, alertSubscriptions, 1)
+	assert.Equal(t, "replica-alert@example.com", alertSubscriptions[0].Email)
+
+	alertSubscriptions, err = repo.GetAlertSubscriptionsForCity("Oslo")
+	assert.NoError(t, err)
+	assert.Empty(t, alertSubscriptions)
+
+	// City lookups read from the replica
+	cities, err := repo.DistinctCities()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Paris", "Kyiv"}, cities)
+
+	hasConfirmed, err := repo.HasConfirmedSubscriptionForCity("Paris")
+	assert.NoError(t, err)
+	assert.True(t, hasConfirmed)
+
+	hasConfirmed, err = repo.HasConfirmedSubscriptionForCity("London")
+	assert.NoError(t, err)
+	assert.False(t, hasConfirmed, "primary-only city should be invisible to replica reads")
+
+	// Point lookup goes to the primary
+	found, err := repo.FindByEmail("primary@example.com", "London")
+	assert.NoError(t, err)
+	assert.NotNil(t, found)
+
+	// Writes land on the primary, never the replica
+	created := &models.Subscription{Email: "new@example.com", City: "Berlin", Frequency: "daily"}
+	assert.NoError(t, repo.Create(created))
+
+	var primaryCount, replicaCount int64
+	primary.Model(&models.Subscription{}).Count(&primaryCount)
+	replica.Model(&models.Subscription{}).Count(&replicaCount)
+	assert.Equal(t, int64(3), primaryCount)
+	assert.Equal(t, int64(2), replicaCount)
+}
+
 func TestSubscriptionRepository_Update(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewSubscriptionRepository(db)
@@ -205,6 +429,41 @@ func TestSubscriptionRepository_Update(t *testing.T) {
 		assert.Equal(t, weathererr.ValidationError, appErr.Type)
 		assert.Contains(t, appErr.Message, "subscription cannot be nil")
 	})
+
+	t.Run("ConcurrentModificationConflict", func(t *testing.T) {
+		testSub := &models.Subscription{
+			Email:     "race@example.com",
+			City:      "Paris",
+			Frequency: "daily",
+		}
+		assert.NoError(t, repo.Create(testSub))
+
+		// Two callers read the same version of the row
+		first, err := repo.FindByID(testSub.ID)
+		assert.NoError(t, err)
+		second, err := repo.FindByID(testSub.ID)
+		assert.NoError(t, err)
+
+		first.Frequency = "hourly"
+		assert.NoError(t, repo.Update(first))
+
+		// The stale writer loses the race and gets a retryable conflict
+		second.Frequency = "weekly"
+		err = repo.Update(second)
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ConflictError, appErr.Type)
+
+		current, err := repo.FindByID(testSub.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "hourly", current.Frequency)
+
+		// Re-reading picks up the new version, so the retry succeeds
+		current.Frequency = "weekly"
+		assert.NoError(t, repo.Update(current))
+	})
 }
 
 func TestSubscriptionRepository_Delete(t *testing.T) {
@@ -230,6 +489,15 @@ func TestSubscriptionRepository_Delete(t *testing.T) {
 		result := db.First(&dbSub, testSub.ID)
 		assert.Error(t, result.Error)
 		assert.Equal(t, gorm.ErrRecordNotFound, result.Error)
+
+		// The deleted row must not linger and trip the unique index when
+		// the same email re-subscribes to the same city
+		resubscribed := &models.Subscription{
+			Email:     "test@example.com",
+			City:      "London",
+			Frequency: "daily",
+		}
+		assert.NoError(t, repo.Create(resubscribed))
 	})
 
 	t.Run("NilSubscription", func(t *testing.T) {
@@ -286,6 +554,248 @@ func TestSubscriptionRepository_GetSubscriptionsForUpdates(t *testing.T) {
 	})
 }
 
+func TestSubscriptionRepository_AlertQueries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	below := -5.0
+	subscriptions := []models.Subscription{
+		{Email: "one@example.com", City: "London", Frequency: "alert", Confirmed: true, AlertTempBelow: &below},
+		{Email: "two@example.com", City: "london", Frequency: "alert", Confirmed: true, AlertCondition: "storm"},
+		{Email: "three@example.com", City: "Kyiv", Frequency: "alert", Confirmed: false, AlertTempBelow: &below},
+		{Email: "four@example.com", City: "Paris", Frequency: "daily", Confirmed: true},
+	}
+	for i := range subscriptions {
+		assert.NoError(t, db.Create(&subscriptions[i]).Error)
+	}
+
+	t.Run("AlertCities", func(t *testing.T) {
+		cities, err := repo.AlertCities()
+		assert.NoError(t, err)
+		// Only confirmed alert subscriptions count; Kyiv is unconfirmed
+		// and Paris is a daily digest
+		assert.Len(t, cities, 2)
+	})
+
+	t.Run("GetAlertSubscriptionsForCity", func(t *testing.T) {
+		subs, err := repo.GetAlertSubscriptionsForCity("LONDON")
+		assert.NoError(t, err)
+		assert.Len(t, subs, 2) // City match is case-insensitive
+
+		for _, sub := range subs {
+			assert.Equal(t, "alert", sub.Frequency)
+			assert.True(t, sub.Confirmed)
+		}
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		subs, err := repo.GetAlertSubscriptionsForCity("")
+		assert.Error(t, err)
+		assert.Nil(t, subs)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+func TestSubscriptionRepository_GetConfirmedByFrequencyBatched(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	t.Run("StreamsInBatches", func(t *testing.T) {
+		cleanupTestDB(t, db)
+
+		for i := 1; i <= 5; i++ {
+			result := db.Create(&models.Subscription{
+				Email:     fmt.Sprintf("test%d@example.com", i),
+				City:      "London",
+				Frequency: "daily",
+				Confirmed: true,
+			})
+			assert.NoError(t, result.Error)
+		}
+		result := db.Create(&models.Subscription{Email: "pending@example.com", City: "Paris", Frequency: "daily", Confirmed: false})
+		assert.NoError(t, result.Error)
+
+		var batchSizes []int
+		var seen []string
+		err := repo.GetConfirmedByFrequencyBatched("daily", 2, func(batch []models.Subscription) error {
+			batchSizes = append(batchSizes, len(batch))
+			for _, sub := range batch {
+				seen = append(seen, sub.Email)
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 2, 1}, batchSizes)
+		assert.Len(t, seen, 5)
+		assert.NotContains(t, seen, "pending@example.com")
+	})
+
+	t.Run("StopsOnCallbackError", func(t *testing.T) {
+		calls := 0
+		err := repo.GetConfirmedByFrequencyBatched("daily", 2, func([]models.Subscription) error {
+			calls++
+			return fmt.Errorf("stop here")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("InvalidBatchSize", func(t *testing.T) {
+		err := repo.GetConfirmedByFrequencyBatched("daily", 0, func([]models.Subscription) error { return nil })
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+
+	t.Run("EmptyFrequency", func(t *testing.T) {
+		err := repo.GetConfirmedByFrequencyBatched("", 2, func([]models.Subscription) error { return nil })
+		assert.Error(t, err)
+	})
+}
+
+// TestSubscriptionRepository_HasConfirmedSubscriptionForCity tests the city access lookup
+func TestSubscriptionRepository_HasConfirmedSubscriptionForCity(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	result := db.Create(&models.Subscription{
+		Email:     "confirmed@example.com",
+		City:      "London",
+		Frequency: "daily",
+		Confirmed: true,
+	})
+	assert.NoError(t, result.Error)
+
+	result = db.Create(&models.Subscription{
+		Email:     "pending@example.com",
+		City:      "Paris",
+		Frequency: "daily",
+		Confirmed: false,
+	})
+	assert.NoError(t, result.Error)
+
+	t.Run("ConfirmedCity", func(t *testing.T) {
+		allowed, err := repo.HasConfirmedSubscriptionForCity("London")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("CaseInsensitiveMatch", func(t *testing.T) {
+		allowed, err := repo.HasConfirmedSubscriptionForCity("london")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("UnconfirmedCity", func(t *testing.T) {
+		allowed, err := repo.HasConfirmedSubscriptionForCity("Paris")
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		_, err := repo.HasConfirmedSubscriptionForCity("")
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+// TestSubscriptionRepository_List tests paginated listing with total counts
+func TestSubscriptionRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	for i := 0; i < 5; i++ {
+		result := db.Create(&models.Subscription{
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			City:      "London",
+			Frequency: "daily",
+			Confirmed: i%2 == 0,
+		})
+		assert.NoError(t, result.Error)
+	}
+
+	t.Run("FirstPage", func(t *testing.T) {
+		subs, total, err := repo.List(0, 2, false)
+		assert.NoError(t, err)
+		assert.Len(t, subs, 2)
+		assert.Equal(t, int64(5), total)
+		assert.Equal(t, "user0@example.com", subs[0].Email)
+	})
+
+	t.Run("SecondPage", func(t *testing.T) {
+		subs, total, err := repo.List(2, 2, false)
+		assert.NoError(t, err)
+		assert.Len(t, subs, 2)
+		assert.Equal(t, int64(5), total)
+		assert.Equal(t, "user2@example.com", subs[0].Email)
+	})
+
+	t.Run("ConfirmedOnly", func(t *testing.T) {
+		subs, total, err := repo.List(0, 10, true)
+		assert.NoError(t, err)
+		assert.Len(t, subs, 3)
+		assert.Equal(t, int64(3), total)
+		for _, sub := range subs {
+			assert.True(t, sub.Confirmed)
+		}
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		_, _, err := repo.List(-1, 10, false)
+		assert.Error(t, err)
+
+		_, _, err = repo.List(0, 0, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestSubscriptionRepository_Counts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSubscriptionRepository(db)
+	defer cleanupTestDB(t, db)
+
+	subscriptions := []models.Subscription{
+		{Email: "one@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{Email: "two@example.com", City: "Paris", Frequency: "daily", Confirmed: false},
+		{Email: "three@example.com", City: "Kyiv", Frequency: "hourly", Confirmed: true},
+	}
+	for i := range subscriptions {
+		assert.NoError(t, db.Create(&subscriptions[i]).Error)
+	}
+
+	counts, err := repo.CountByFrequency()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), counts["daily"])
+	assert.Equal(t, int64(1), counts["hourly"])
+
+	confirmed, err := repo.CountConfirmed()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), confirmed)
+
+	assert.NoError(t, db.Create(&models.Subscription{Email: "one@example.com", City: "Kyiv", Frequency: "daily"}).Error)
+
+	byEmail, err := repo.CountByEmail("one@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), byEmail)
+
+	_, err = repo.CountByEmail("")
+	assert.Error(t, err)
+}
+
 func TestTokenRepository_CreateToken(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewTokenRepository(db)
@@ -350,6 +860,201 @@ func TestTokenRepository_CreateToken(t *testing.T) {
 	})
 }
 
+func TestIdempotencyKeyRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewIdempotencyKeyRepository(db)
+	defer cleanupTestDB(t, db)
+
+	t.Run("SaveAndFind", func(t *testing.T) {
+		err := repo.Save("key-1", "test@example.com", "London", 200, `{"message":"ok"}`)
+		assert.NoError(t, err)
+
+		record, err := repo.Find("key-1", "test@example.com", "London")
+		assert.NoError(t, err)
+		assert.NotNil(t, record)
+		assert.Equal(t, 200, record.StatusCode)
+		assert.Equal(t, `{"message":"ok"}`, record.Body)
+	})
+
+	t.Run("ScopedPerEmailAndCity", func(t *testing.T) {
+		err := repo.Save("key-2", "test@example.com", "London", 200, `{"message":"ok"}`)
+		assert.NoError(t, err)
+
+		record, err := repo.Find("key-2", "test@example.com", "Paris")
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+
+		record, err = repo.Find("key-2", "other@example.com", "London")
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		record, err := repo.Find("missing", "test@example.com", "London")
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("ExpiredKeyNotReturned", func(t *testing.T) {
+		expired := models.IdempotencyKey{
+			Key:        "key-3",
+			Email:      "test@example.com",
+			City:       "London",
+			StatusCode: 200,
+			Body:       `{"message":"ok"}`,
+			ExpiresAt:  time.Now().Add(-time.Minute),
+		}
+		assert.NoError(t, db.Create(&expired).Error)
+
+		record, err := repo.Find("key-3", "test@example.com", "London")
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+
+		deleted, err := repo.DeleteExpired()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+
+	t.Run("EmptyScope", func(t *testing.T) {
+		_, err := repo.Find("", "test@example.com", "London")
+		assert.Error(t, err)
+
+		err = repo.Save("key-4", "", "London", 200, "{}")
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+func TestNotificationRunRepository_RecordRun(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNotificationRunRepository(db)
+	defer cleanupTestDB(t, db)
+
+	window := time.Date(2026, 8, 28, 10, 0, 0, 0, time.UTC)
+
+	t.Run("FirstRunInWindow", func(t *testing.T) {
+		started, err := repo.RecordRun("hourly", window)
+		assert.NoError(t, err)
+		assert.True(t, started)
+	})
+
+	t.Run("RepeatedRunInSameWindow", func(t *testing.T) {
+		started, err := repo.RecordRun("hourly", window)
+		assert.NoError(t, err)
+		assert.False(t, started)
+	})
+
+	t.Run("IndependentPerFrequencyAndWindow", func(t *testing.T) {
+		started, err := repo.RecordRun("daily", window)
+		assert.NoError(t, err)
+		assert.True(t, started)
+
+		started, err = repo.RecordRun("hourly", window.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, started)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		_, err := repo.RecordRun("", window)
+		assert.Error(t, err)
+
+		_, err = repo.RecordRun("hourly", time.Time{})
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+func TestTokenRepository_CreateEmailToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTokenRepository(db)
+	defer cleanupTestDB(t, db)
+
+	t.Run("ValidToken", func(t *testing.T) {
+		token, err := repo.CreateEmailToken("test@example.com", "unsubscribe_all", 24*time.Hour)
+		assert.NoError(t, err)
+		assert.NotNil(t, token)
+		assert.NotEmpty(t, token.Token)
+		assert.Equal(t, "test@example.com", token.Email)
+		assert.Equal(t, "unsubscribe_all", token.Type)
+		assert.Zero(t, token.SubscriptionID)
+
+		var dbToken models.Token
+		result := db.First(&dbToken, token.ID)
+		assert.NoError(t, result.Error)
+		assert.Equal(t, "test@example.com", dbToken.Email)
+	})
+
+	t.Run("EmptyEmail", func(t *testing.T) {
+		token, err := repo.CreateEmailToken("", "unsubscribe_all", 24*time.Hour)
+		assert.Error(t, err)
+		assert.Nil(t, token)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+		assert.Contains(t, appErr.Message, "email cannot be empty")
+	})
+
+	t.Run("EmptyTokenType", func(t *testing.T) {
+		token, err := repo.CreateEmailToken("test@example.com", "", 24*time.Hour)
+		assert.Error(t, err)
+		assert.Nil(t, token)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+		assert.Contains(t, appErr.Message, "token type cannot be empty")
+	})
+}
+
+// TestTokenRepository_CustomGenerator tests deterministic token generation
+func TestTokenRepository_CustomGenerator(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	testSub := models.Subscription{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+		Confirmed: true,
+	}
+	result := db.Create(&testSub)
+	assert.NoError(t, result.Error)
+
+	t.Run("FixedGenerator", func(t *testing.T) {
+		counter := 0
+		repo := NewTokenRepositoryWithGenerator(db, func() string {
+			counter++
+			return fmt.Sprintf("fixed-token-%d", counter)
+		})
+
+		token, err := repo.CreateToken(testSub.ID, "confirmation", 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, "fixed-token-1", token.Token)
+
+		token, err = repo.CreateToken(testSub.ID, "unsubscribe", 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, "fixed-token-2", token.Token)
+	})
+
+	t.Run("NilGeneratorFallsBackToUUID", func(t *testing.T) {
+		repo := NewTokenRepositoryWithGenerator(db, nil)
+
+		token, err := repo.CreateToken(testSub.ID, "confirmation", 24*time.Hour)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+
+		_, err = uuid.Parse(token.Token)
+		assert.NoError(t, err)
+	})
+}
+
 func TestTokenRepository_FindByToken(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewTokenRepository(db)
@@ -502,8 +1207,9 @@ func TestTokenRepository_DeleteExpiredTokens(t *testing.T) {
 		assert.NoError(t, result.Error)
 	}
 
-	err := repo.DeleteExpiredTokens()
+	deleted, err := repo.DeleteExpiredTokens()
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
 
 	var remainingTokens []models.Token
 	result = db.Find(&remainingTokens)
@@ -514,3 +1220,127 @@ func TestTokenRepository_DeleteExpiredTokens(t *testing.T) {
 		assert.True(t, token.ExpiresAt.After(time.Now()))
 	}
 }
+
+func TestFailedEmailRepository_Record(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFailedEmailRepository(db)
+
+	t.Run("ValidInput", func(t *testing.T) {
+		err := repo.Record("user@example.com", "Weather Update for London", "smtp down")
+		assert.NoError(t, err)
+
+		var failedEmails []models.FailedEmail
+		assert.NoError(t, db.Find(&failedEmails).Error)
+		assert.Len(t, failedEmails, 1)
+		assert.Equal(t, "user@example.com", failedEmails[0].To)
+		assert.Equal(t, "smtp down", failedEmails[0].Reason)
+	})
+
+	t.Run("EmptyRecipient", func(t *testing.T) {
+		err := repo.Record("", "Weather Update for London", "smtp down")
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
+
+func TestFailedEmailRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFailedEmailRepository(db)
+
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, repo.Record(fmt.Sprintf("user%d@example.com", i), "Weather Update for London", "smtp down"))
+	}
+
+	t.Run("MostRecentFirst", func(t *testing.T) {
+		failedEmails, total, err := repo.List(0, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, failedEmails, 2)
+		assert.Equal(t, "user3@example.com", failedEmails[0].To)
+		assert.Equal(t, "user2@example.com", failedEmails[1].To)
+	})
+
+	t.Run("SecondPage", func(t *testing.T) {
+		failedEmails, total, err := repo.List(2, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, failedEmails, 1)
+		assert.Equal(t, "user1@example.com", failedEmails[0].To)
+	})
+
+	t.Run("InvalidPagination", func(t *testing.T) {
+		_, _, err := repo.List(-1, 10)
+		assert.Error(t, err)
+
+		_, _, err = repo.List(0, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestObservationRepository_Save(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewObservationRepository(db)
+
+	t.Run("ValidInput", func(t *testing.T) {
+		err := repo.Save("London", &models.WeatherResponse{Temperature: 15.0, Humidity: 76.0, Description: "Partly cloudy"})
+		assert.NoError(t, err)
+
+		var observations []models.WeatherObservation
+		result := db.Find(&observations)
+		assert.NoError(t, result.Error)
+		assert.Len(t, observations, 1)
+		assert.Equal(t, "London", observations[0].City)
+		assert.Equal(t, 15.0, observations[0].Temperature)
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		err := repo.Save("", &models.WeatherResponse{Temperature: 15.0})
+		assert.Error(t, err)
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+
+	t.Run("NilWeather", func(t *testing.T) {
+		err := repo.Save("London", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestObservationRepository_FindByCity(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewObservationRepository(db)
+
+	assert.NoError(t, repo.Save("London", &models.WeatherResponse{Temperature: 14.0, Description: "Cloudy"}))
+	assert.NoError(t, repo.Save("London", &models.WeatherResponse{Temperature: 16.0, Description: "Sunny"}))
+	assert.NoError(t, repo.Save("Paris", &models.WeatherResponse{Temperature: 20.0, Description: "Clear"}))
+
+	t.Run("ReturnsSeriesOldestFirst", func(t *testing.T) {
+		observations, err := repo.FindByCity("London", time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, observations, 2)
+		assert.Equal(t, 14.0, observations[0].Temperature)
+		assert.Equal(t, 16.0, observations[1].Temperature)
+	})
+
+	t.Run("CaseInsensitiveMatch", func(t *testing.T) {
+		observations, err := repo.FindByCity("london", time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, observations, 2)
+	})
+
+	t.Run("ExcludesOlderObservations", func(t *testing.T) {
+		observations, err := repo.FindByCity("London", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, observations)
+	})
+
+	t.Run("EmptyCity", func(t *testing.T) {
+		_, err := repo.FindByCity("", time.Now())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"weatherapi.app/tracing"
+)
+
+// spanInstanceKey is where the in-flight span is stashed on the gorm
+// statement between the before and after callbacks
+const spanInstanceKey = "tracing:span"
+
+// RegisterTracing installs gorm callbacks that record a span around every
+// database operation, covering all repositories at once. Callers simply skip
+// the registration when tracing is disabled
+func RegisterTracing(db *gorm.DB, tracer tracing.Tracer) error {
+	gt := &gormTracing{tracer: tracer}
+
+	registrations := []error{
+		db.Callback().Create().Before("gorm:create").Register("tracing:before_create", gt.before("create")),
+		db.Callback().Create().After("gorm:create").Register("tracing:after_create", gt.after),
+		db.Callback().Query().Before("gorm:query").Register("tracing:before_query", gt.before("query")),
+		db.Callback().Query().After("gorm:query").Register("tracing:after_query", gt.after),
+		db.Callback().Update().Before("gorm:update").Register("tracing:before_update", gt.before("update")),
+		db.Callback().Update().After("gorm:update").Register("tracing:after_update", gt.after),
+		db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", gt.before("delete")),
+		db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", gt.after),
+		db.Callback().Row().Before("gorm:row").Register("tracing:before_row", gt.before("row")),
+		db.Callback().Row().After("gorm:row").Register("tracing:after_row", gt.after),
+		db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", gt.before("raw")),
+		db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", gt.after),
+	}
+
+	for _, err := range registrations {
+		if err != nil {
+			return fmt.Errorf("register tracing callbacks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type gormTracing struct {
+	tracer tracing.Tracer
+}
+
+func (gt *gormTracing) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		_, span := gt.tracer.StartSpan(context.Background(), "repository."+operation)
+		db.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func (gt *gormTracing) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(tracing.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttribute("table", db.Statement.Table)
+	span.SetAttribute("rows_affected", db.Statement.RowsAffected)
+	if db.Error != nil {
+		span.RecordError(db.Error)
+	}
+	span.End()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/models"
+	"weatherapi.app/tracing"
+)
+
+// recordingTracer captures finished spans so tests can assert on names and
+// attributes
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	tracer *recordingTracer
+	name   string
+	attrs  map[string]interface{}
+	err    error
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, tracing.Span) {
+	return ctx, &recordedSpan{tracer: t, name: name, attrs: make(map[string]interface{})}
+}
+
+func (t *recordingTracer) finished(name string) []*recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []*recordedSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			matches = append(matches, span)
+		}
+	}
+	return matches
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordedSpan) RecordError(err error)                      { s.err = err }
+func (s *recordedSpan) End() {
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, s)
+	s.tracer.mu.Unlock()
+}
+
+func TestRegisterTracing_RecordsSpansAroundOperations(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	tracer := &recordingTracer{}
+	require.NoError(t, RegisterTracing(db, tracer))
+
+	repo := NewSubscriptionRepository(db)
+
+	subscription := &models.Subscription{
+		Email:     "traced@example.com",
+		City:      "London",
+		Frequency: "daily",
+		Confirmed: true,
+	}
+	require.NoError(t, repo.Create(subscription))
+
+	createSpans := tracer.finished("repository.create")
+	require.Len(t, createSpans, 1)
+	assert.Equal(t, "subscriptions", createSpans[0].attrs["table"])
+	assert.NoError(t, createSpans[0].err)
+
+	found, err := repo.FindByEmail("traced@example.com", "London")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	querySpans := tracer.finished("repository.query")
+	assert.NotEmpty(t, querySpans)
+	assert.Equal(t, "subscriptions", querySpans[0].attrs["table"])
+}
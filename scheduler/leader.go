@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// leaderLockKey is the advisory-lock key every replica contends on. The
+	// value is arbitrary; it only has to be the same in all instances
+	leaderLockKey int64 = 728_431_905
+
+	// leaderLockTimeout bounds each database round trip made while
+	// contending for or releasing leadership
+	leaderLockTimeout = 5 * time.Second
+)
+
+// LeaderElector coordinates scheduler replicas through a Postgres
+// session-level advisory lock. The instance holding the lock is the leader;
+// the lock is tied to a dedicated connection, so Postgres releases it
+// automatically if the leader's session dies
+type LeaderElector struct {
+	db *gorm.DB
+
+	mu     sync.Mutex
+	conn   *sql.Conn
+	leader bool
+}
+
+// NewLeaderElector creates an elector contending on the shared scheduler lock
+func NewLeaderElector(db *gorm.DB) *LeaderElector {
+	return &LeaderElector{db: db}
+}
+
+// IsLeader reports whether this instance currently holds the scheduler lock,
+// attempting to acquire it if not. An instance that cannot reach the
+// database is never the leader
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaderLockTimeout)
+	defer cancel()
+
+	if e.leader {
+		// Losing the connection holding the lock means Postgres already
+		// released it, so a failed ping forces re-election
+		if err := e.conn.PingContext(ctx); err == nil {
+			return true
+		}
+		slog.Warn("Scheduler leader connection lost; standing down")
+		e.releaseLocked()
+	}
+
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		slog.Error("Failed to get database handle for leader election", "error", err)
+		return false
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		slog.Error("Failed to open connection for leader election", "error", err)
+		return false
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", leaderLockKey).Scan(&acquired); err != nil {
+		slog.Error("Failed to contend for scheduler leadership", "error", err)
+		closeLeaderConn(conn)
+		return false
+	}
+
+	if !acquired {
+		closeLeaderConn(conn)
+		return false
+	}
+
+	e.conn = conn
+	e.leader = true
+	slog.Info("Acquired scheduler leadership")
+	return true
+}
+
+// Release gives up leadership so another instance can take over
+func (e *LeaderElector) Release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.releaseLocked()
+}
+
+func (e *LeaderElector) releaseLocked() {
+	if e.conn == nil {
+		e.leader = false
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaderLockTimeout)
+	defer cancel()
+
+	// The lock must be unlocked explicitly: Close returns the connection to
+	// the pool rather than ending the session the lock is tied to
+	if _, err := e.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", leaderLockKey); err != nil {
+		slog.Warn("Failed to release scheduler leadership lock", "error", err)
+	}
+	closeLeaderConn(e.conn)
+
+	e.conn = nil
+	e.leader = false
+}
+
+func closeLeaderConn(conn *sql.Conn) {
+	if err := conn.Close(); err != nil {
+		slog.Warn("Failed to close leader election connection", "error", err)
+	}
+}
@@ -2,20 +2,31 @@ package scheduler
 
 import (
 	"log/slog"
+	"math/rand"
 	"time"
 
 	"gorm.io/gorm"
 	"weatherapi.app/config"
+	"weatherapi.app/metrics"
 	"weatherapi.app/repository"
 	"weatherapi.app/service"
 )
 
+// WeatherAlerter checks alert subscriptions against current conditions.
+// It is discovered via type assertion so notification services without
+// alert support keep working unchanged
+type WeatherAlerter interface {
+	SendWeatherAlerts() (*service.WeatherUpdateResult, error)
+}
+
 // Scheduler manages periodic tasks for the application
 type Scheduler struct {
 	db                  *gorm.DB
 	config              *config.Config
 	tokenRepo           *repository.TokenRepository
+	subscriptionRepo    *repository.SubscriptionRepository
 	subscriptionService service.NotificationServiceInterface
+	leader              *LeaderElector
 }
 
 // NewScheduler creates and configures a new task scheduler
@@ -25,12 +36,20 @@ func NewScheduler(
 	subscriptionService service.NotificationServiceInterface,
 ) *Scheduler {
 	tokenRepo := repository.NewTokenRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+
+	var leader *LeaderElector
+	if config.Scheduler.LeaderElection {
+		leader = NewLeaderElector(db)
+	}
 
 	return &Scheduler{
 		db:                  db,
 		config:              config,
 		tokenRepo:           tokenRepo,
+		subscriptionRepo:    subscriptionRepo,
 		subscriptionService: subscriptionService,
+		leader:              leader,
 	}
 }
 
@@ -40,38 +59,116 @@ func (s *Scheduler) Start() {
 
 	go s.scheduleDaily(24*time.Hour, s.cleanupExpiredTokens)
 
+	go s.scheduleInterval(time.Duration(s.config.Scheduler.CleanupIntervalMinutes)*time.Minute, s.cleanupExpiredUnconfirmedSubscriptions)
+
 	go s.scheduleInterval(time.Duration(s.config.Scheduler.HourlyInterval)*time.Minute, func() {
-		slog.Info("Running hourly weather updates...")
-		if err := s.subscriptionService.SendWeatherUpdate("hourly"); err != nil {
-			slog.Error("Failed to send hourly weather updates", "error", err)
-		} else {
-			slog.Info("Hourly weather updates completed successfully")
-		}
+		s.runWeatherUpdate("hourly")
 	})
 
 	go s.scheduleInterval(time.Duration(s.config.Scheduler.DailyInterval)*time.Minute, func() {
-		slog.Info("Running daily weather updates...")
-		if err := s.subscriptionService.SendWeatherUpdate("daily"); err != nil {
-			slog.Error("Failed to send daily weather updates", "error", err)
-		} else {
-			slog.Info("Daily weather updates completed successfully")
-		}
+		s.runWeatherUpdate("daily")
+	})
+
+	go s.scheduleInterval(time.Duration(s.config.Scheduler.WeeklyInterval)*time.Minute, func() {
+		s.runWeatherUpdate("weekly")
 	})
 
+	if alerter, ok := s.subscriptionService.(WeatherAlerter); ok {
+		go s.scheduleInterval(time.Duration(s.config.Scheduler.AlertIntervalMinutes)*time.Minute, func() {
+			s.runWeatherAlerts(alerter)
+		})
+	}
+
 	slog.Info("Scheduler started successfully")
 }
 
+// runWeatherUpdate triggers a bulk update run and logs its outcome,
+// distinguishing a failed run from one that completed with partial failures
+func (s *Scheduler) runWeatherUpdate(frequency string) {
+	if !s.isLeader() {
+		slog.Debug("Skipping weather update run on non-leader instance", "frequency", frequency)
+		return
+	}
+
+	slog.Info("Running weather updates...", "frequency", frequency)
+
+	result, err := s.subscriptionService.SendWeatherUpdate(frequency)
+	if err != nil {
+		slog.Error("Failed to send weather updates", "frequency", frequency, "error", err)
+		return
+	}
+
+	if result.Failed > 0 {
+		slog.Warn("Weather updates completed with failures",
+			"frequency", frequency, "sent", result.Sent, "failed", result.Failed, "errors", result.Errors)
+		return
+	}
+
+	slog.Info("Weather updates completed successfully", "frequency", frequency, "sent", result.Sent)
+}
+
+// runWeatherAlerts triggers an alert check run and logs its outcome
+func (s *Scheduler) runWeatherAlerts(alerter WeatherAlerter) {
+	if !s.isLeader() {
+		slog.Debug("Skipping weather alert run on non-leader instance")
+		return
+	}
+
+	slog.Info("Running weather alert checks...")
+
+	result, err := alerter.SendWeatherAlerts()
+	if err != nil {
+		slog.Error("Failed to run weather alert checks", "error", err)
+		return
+	}
+
+	if result.Failed > 0 {
+		slog.Warn("Weather alert checks completed with failures",
+			"sent", result.Sent, "failed", result.Failed, "errors", result.Errors)
+		return
+	}
+
+	slog.Info("Weather alert checks completed successfully", "sent", result.Sent)
+}
+
+// isLeader reports whether this instance should run cross-replica jobs. With
+// leader election disabled every instance is its own leader. Cleanup jobs
+// are not gated on it: they are idempotent deletes, so a duplicate run is
+// wasteful but harmless
+func (s *Scheduler) isLeader() bool {
+	if s.leader == nil {
+		return true
+	}
+	return s.leader.IsLeader()
+}
+
 func (s *Scheduler) scheduleInterval(interval time.Duration, job func()) {
 	job()
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for range ticker.C {
+	for {
+		time.Sleep(s.jitteredInterval(interval))
 		job()
 	}
 }
 
+// jitteredInterval shifts the interval by a random offset within ± the
+// configured jitter percentage, so replicas sharing the same intervals do not
+// all fire at the same wall-clock moment. With jitter disabled (the default)
+// the interval is returned unchanged
+func (s *Scheduler) jitteredInterval(interval time.Duration) time.Duration {
+	percent := s.config.Scheduler.JitterPercent
+	if percent <= 0 {
+		return interval
+	}
+
+	maxOffset := interval / 100 * time.Duration(percent)
+	if maxOffset <= 0 {
+		return interval
+	}
+
+	return interval - maxOffset + time.Duration(rand.Int63n(2*int64(maxOffset)+1))
+}
+
 func (s *Scheduler) scheduleDaily(interval time.Duration, job func()) {
 	job()
 
@@ -85,9 +182,27 @@ func (s *Scheduler) scheduleDaily(interval time.Duration, job func()) {
 
 func (s *Scheduler) cleanupExpiredTokens() {
 	slog.Info("Running expired token cleanup...")
-	if err := s.tokenRepo.DeleteExpiredTokens(); err != nil {
+	deleted, err := s.tokenRepo.DeleteExpiredTokens()
+	if err != nil {
 		slog.Error("Failed to cleanup expired tokens", "error", err)
-	} else {
-		slog.Info("Expired token cleanup completed successfully")
+		return
 	}
+
+	metrics.Subscriptions().RecordExpiredTokens(deleted)
+	slog.Info("Expired token cleanup completed successfully", "deleted", deleted)
+}
+
+// cleanupExpiredUnconfirmedSubscriptions purges signups that were never
+// confirmed so abandoned rows do not accumulate
+func (s *Scheduler) cleanupExpiredUnconfirmedSubscriptions() {
+	slog.Info("Running unconfirmed subscription cleanup...")
+	// A signup is abandoned once its confirmation token can no longer be
+	// used, so the cutoff tracks the configured token lifetime
+	deleted, err := s.subscriptionRepo.DeleteExpiredUnconfirmed(time.Now().Add(-s.config.ConfirmationTokenTTL()))
+	if err != nil {
+		slog.Error("Failed to cleanup unconfirmed subscriptions", "error", err)
+		return
+	}
+
+	slog.Info("Unconfirmed subscription cleanup completed", "deleted", deleted)
 }
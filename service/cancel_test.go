@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+	"weatherapi.app/repository"
+)
+
+func setupCancelTestService(t *testing.T) (*SubscriptionService, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Subscription{}, &models.Token{}))
+
+	emailService := new(mockEmailService)
+	emailService.On("SendUnsubscribeConfirmationEmailWithParams", mock.Anything).Return(nil)
+
+	service := NewSubscriptionService(
+		db,
+		repository.NewSubscriptionRepository(db),
+		repository.NewTokenRepository(db),
+		emailService,
+		new(mockWeatherService),
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	return service, db
+}
+
+func TestSubscriptionService_CancelSubscription_DeletesPending(t *testing.T) {
+	service, db := setupCancelTestService(t)
+
+	subscription := &models.Subscription{Email: "pending@example.com", City: "London", Frequency: "daily"}
+	require.NoError(t, db.Create(subscription).Error)
+
+	token := &models.Token{
+		Token:          "cancel-token",
+		SubscriptionID: subscription.ID,
+		Type:           "cancel",
+		ExpiresAt:      time.Now().Add(24 * time.Hour),
+	}
+	require.NoError(t, db.Create(token).Error)
+
+	err := service.CancelSubscription("cancel-token")
+
+	assert.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Subscription{}).Where("email = ?", "pending@example.com").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestSubscriptionService_CancelSubscription_ConfirmedSubscription(t *testing.T) {
+	service, db := setupCancelTestService(t)
+
+	subscription := &models.Subscription{Email: "confirmed@example.com", City: "London", Frequency: "daily", Confirmed: true}
+	require.NoError(t, db.Create(subscription).Error)
+
+	token := &models.Token{
+		Token:          "cancel-token",
+		SubscriptionID: subscription.ID,
+		Type:           "cancel",
+		ExpiresAt:      time.Now().Add(24 * time.Hour),
+	}
+	require.NoError(t, db.Create(token).Error)
+
+	err := service.CancelSubscription("cancel-token")
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.TokenError, appErr.Type)
+}
+
+func TestEmailService_ConfirmationEmailIncludesCancelLink(t *testing.T) {
+	mockProvider := new(mockEmailProvider)
+	emailService := NewEmailService(mockProvider)
+
+	cancelURL := "http://localhost:8080/api/cancel/cancel-token"
+	mockProvider.On("SendEmail", "test@example.com", mock.AnythingOfType("string"), mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, cancelURL)
+	}), true).Return(nil)
+
+	err := emailService.SendConfirmationEmailWithParams(ConfirmationEmailParams{
+		Email:      "test@example.com",
+		ConfirmURL: "http://localhost:8080/api/confirm/confirm-token",
+		City:       "London",
+		CancelURL:  cancelURL,
+	})
+
+	assert.NoError(t, err)
+	mockProvider.AssertExpectations(t)
+}
+
+func TestSubscriptionService_CancelSubscription_WrongTokenType(t *testing.T) {
+	service, db := setupCancelTestService(t)
+
+	subscription := &models.Subscription{Email: "pending@example.com", City: "London", Frequency: "daily"}
+	require.NoError(t, db.Create(subscription).Error)
+
+	token := &models.Token{
+		Token:          "confirm-token",
+		SubscriptionID: subscription.ID,
+		Type:           "confirmation",
+		ExpiresAt:      time.Now().Add(24 * time.Hour),
+	}
+	require.NoError(t, db.Create(token).Error)
+
+	err := service.CancelSubscription("confirm-token")
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.TokenError, appErr.Type)
+}
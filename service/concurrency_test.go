@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+	"weatherapi.app/repository"
+)
+
+// setupConcurrencyTestService creates a subscription service backed by a real
+// sqlite database and real repositories so the transactional paths are exercised
+func setupConcurrencyTestService(t *testing.T) (*SubscriptionService, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// A single connection serializes concurrent transactions; sqlite has no
+	// row-level locking and separate :memory: connections see separate DBs
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&models.Subscription{}, &models.Token{}))
+
+	emailService := new(mockEmailService)
+	emailService.On("SendWelcomeEmailWithParams", mock.Anything).Return(nil)
+	emailService.On("SendUnsubscribeConfirmationEmailWithParams", mock.Anything).Return(nil)
+
+	service := NewSubscriptionService(
+		db,
+		repository.NewSubscriptionRepository(db),
+		repository.NewTokenRepository(db),
+		emailService,
+		new(mockWeatherService),
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	return service, db
+}
+
+// TestSubscriptionService_ConfirmAfterSubscriptionDeleted simulates the race
+// where an unsubscribe removes the subscription between the confirm handler's
+// initial lookup and its transaction: confirm must report not-found, not a 500
+func TestSubscriptionService_ConfirmAfterSubscriptionDeleted(t *testing.T) {
+	service, db := setupConcurrencyTestService(t)
+
+	subscription := &models.Subscription{Email: "race@example.com", City: "London", Frequency: "daily"}
+	require.NoError(t, db.Create(subscription).Error)
+
+	token := &models.Token{Token: "confirm-token", SubscriptionID: subscription.ID, Type: "confirmation"}
+	require.NoError(t, db.Create(token).Error)
+
+	// Delete the subscription out from under the confirm transaction
+	require.NoError(t, db.Unscoped().Delete(subscription).Error)
+
+	err := service.processConfirmation(subscription, token)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.NotFoundError, appErr.Type)
+}
+
+// TestSubscriptionService_ConcurrentConfirmAndUnsubscribe runs confirm and
+// unsubscribe for the same subscription concurrently; whichever loses the race
+// must fail with a clean domain error, never a database error
+func TestSubscriptionService_ConcurrentConfirmAndUnsubscribe(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		service, db := setupConcurrencyTestService(t)
+
+		subscription := &models.Subscription{Email: "race@example.com", City: "London", Frequency: "daily"}
+		require.NoError(t, db.Create(subscription).Error)
+
+		confirmToken := &models.Token{Token: "confirm-token", SubscriptionID: subscription.ID, Type: "confirmation"}
+		require.NoError(t, db.Create(confirmToken).Error)
+		unsubscribeToken := &models.Token{Token: "unsubscribe-token", SubscriptionID: subscription.ID, Type: "unsubscribe"}
+		require.NoError(t, db.Create(unsubscribeToken).Error)
+
+		var wg sync.WaitGroup
+		var confirmErr, unsubscribeErr error
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			confirmErr = service.processConfirmation(subscription, confirmToken)
+		}()
+		go func() {
+			defer wg.Done()
+			unsubscribeErr = service.processUnsubscription(subscription, unsubscribeToken)
+		}()
+		wg.Wait()
+
+		for _, err := range []error{confirmErr, unsubscribeErr} {
+			if err == nil {
+				continue
+			}
+			var appErr *weathererr.AppError
+			require.True(t, errors.As(err, &appErr), "unexpected error: %v", err)
+			assert.NotEqual(t, weathererr.DatabaseError, appErr.Type, "race surfaced as database error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import "weatherapi.app/models"
+
+// emailCopy holds the translated surrounding copy for outgoing emails,
+// keyed by language then message key. Values are fmt format strings fed the
+// same arguments in every language. Weather descriptions come from the
+// providers and are intentionally not translated
+var emailCopy = map[string]map[string]string{
+	models.LanguageEnglish.String(): {
+		"confirmation.subject": "Confirm your weather subscription for %s",
+		"confirmation.body": "<p>Please confirm your subscription to weather updates for %s by clicking the following link:</p>" +
+			"<p><a href=\"%s\">Confirm Subscription</a></p>" +
+			"<p>This link will expire in 24 hours.</p>",
+		"confirmation.cancel": "<p>If you did not request this, <a href=\"%s\">cancel this signup</a>.</p>",
+
+		"welcome.subject": "Welcome to Weather Updates for %s",
+		"welcome.body": "<p>Thank you for subscribing to %s weather updates for %s.</p>" +
+			"<p>You will receive updates %s.</p>" +
+			"<p>To unsubscribe, <a href=\"%s\">click here</a>.</p>",
+		"welcome.every_hour": "every hour",
+		"welcome.every_day":  "every day",
+
+		"unsubscribe.subject": "You have unsubscribed from weather updates for %s",
+		"unsubscribe.body":    "<p>You have successfully unsubscribed from weather updates for %s.</p>",
+
+		"unsubscribe_all.subject": "Confirm unsubscribing from all weather updates",
+		"unsubscribe_all.body": "<p>You asked to unsubscribe from weather updates for all %d %s on this address.</p>" +
+			"<p><a href=\"%s\">Unsubscribe from everything</a></p>" +
+			"<p>This link will expire in 24 hours. If you did not request this, you can ignore this email.</p>",
+		"unsubscribe_all.city":   "city",
+		"unsubscribe_all.cities": "cities",
+
+		"weather_update.subject": "Weather Update for %s",
+		"weather_update.body": "<h2>Current weather for %s</h2>" +
+			"<p><strong>Temperature:</strong> %.1f°C</p>" +
+			"<p><strong>Humidity:</strong> %.1f%%</p>" +
+			"<p><strong>Description:</strong> %s</p>" +
+			"%s" +
+			"<p>To unsubscribe, <a href=\"%s\">click here</a>.</p>",
+		"weather_update.observed": "<p><em>Observed at %s</em></p>",
+
+		"weather_digest.subject": "Your weather digest",
+		"weather_digest.intro":   "<h2>Current weather for your cities</h2>",
+		"weather_digest.city_section": "<h3>%s</h3>" +
+			"<p><strong>Temperature:</strong> %.1f°C</p>" +
+			"<p><strong>Humidity:</strong> %.1f%%</p>" +
+			"<p><strong>Description:</strong> %s</p>" +
+			"<p>To unsubscribe from %s updates, <a href=\"%s\">click here</a>.</p>",
+
+		"weather_alert.subject": "Weather Alert for %s",
+		"weather_alert.body": "<h2>Weather alert for %s</h2>" +
+			"<p>%s.</p>" +
+			"<p><strong>Temperature:</strong> %.1f°C</p>" +
+			"<p><strong>Humidity:</strong> %.1f%%</p>" +
+			"<p><strong>Description:</strong> %s</p>" +
+			"<p>To unsubscribe, <a href=\"%s\">click here</a>.</p>",
+	},
+
+	models.LanguageUkrainian.String(): {
+		"confirmation.subject": "Підтвердіть підписку на погоду для %s",
+		"confirmation.body": "<p>Підтвердіть підписку на оновлення погоди для %s, перейшовши за посиланням:</p>" +
+			"<p><a href=\"%s\">Підтвердити підписку</a></p>" +
+			"<p>Посилання дійсне протягом 24 годин.</p>",
+		"confirmation.cancel": "<p>Якщо ви цього не запитували, <a href=\"%s\">скасуйте цю реєстрацію</a>.</p>",
+
+		"welcome.subject": "Вітаємо з підпискою на оновлення погоди для %s",
+		"welcome.body": "<p>Дякуємо за підписку (%s) на оновлення погоди для %s.</p>" +
+			"<p>Ви отримуватимете оновлення %s.</p>" +
+			"<p>Щоб відписатися, <a href=\"%s\">натисніть тут</a>.</p>",
+		"welcome.every_hour": "щогодини",
+		"welcome.every_day":  "щодня",
+
+		"unsubscribe.subject": "Ви відписалися від оновлень погоди для %s",
+		"unsubscribe.body":    "<p>Ви успішно відписалися від оновлень погоди для %s.</p>",
+
+		"unsubscribe_all.subject": "Підтвердіть відписку від усіх оновлень погоди",
+		"unsubscribe_all.body": "<p>Ви попросили відписатися від оновлень погоди для всіх міст на цій адресі (%d %s).</p>" +
+			"<p><a href=\"%s\">Відписатися від усього</a></p>" +
+			"<p>Посилання дійсне протягом 24 годин. Якщо ви цього не запитували, просто проігноруйте цей лист.</p>",
+		"unsubscribe_all.city":   "підписка",
+		"unsubscribe_all.cities": "підписок",
+
+		"weather_update.subject": "Оновлення погоди для %s",
+		"weather_update.body": "<h2>Поточна погода для %s</h2>" +
+			"<p><strong>Температура:</strong> %.1f°C</p>" +
+			"<p><strong>Вологість:</strong> %.1f%%</p>" +
+			"<p><strong>Опис:</strong> %s</p>" +
+			"%s" +
+			"<p>Щоб відписатися, <a href=\"%s\">натисніть тут</a>.</p>",
+		"weather_update.observed": "<p><em>Станом на %s</em></p>",
+
+		"weather_digest.subject": "Ваш дайджест погоди",
+		"weather_digest.intro":   "<h2>Поточна погода для ваших міст</h2>",
+		"weather_digest.city_section": "<h3>%s</h3>" +
+			"<p><strong>Температура:</strong> %.1f°C</p>" +
+			"<p><strong>Вологість:</strong> %.1f%%</p>" +
+			"<p><strong>Опис:</strong> %s</p>" +
+			"<p>Щоб відписатися від оновлень для %s, <a href=\"%s\">натисніть тут</a>.</p>",
+
+		"weather_alert.subject": "Погодне попередження для %s",
+		"weather_alert.body": "<h2>Погодне попередження для %s</h2>" +
+			"<p>%s.</p>" +
+			"<p><strong>Температура:</strong> %.1f°C</p>" +
+			"<p><strong>Вологість:</strong> %.1f%%</p>" +
+			"<p><strong>Опис:</strong> %s</p>" +
+			"<p>Щоб відписатися, <a href=\"%s\">натисніть тут</a>.</p>",
+	},
+
+	models.LanguageGerman.String(): {
+		"confirmation.subject": "Bestätigen Sie Ihr Wetter-Abonnement für %s",
+		"confirmation.body": "<p>Bitte bestätigen Sie Ihr Abonnement für Wetter-Updates für %s über den folgenden Link:</p>" +
+			"<p><a href=\"%s\">Abonnement bestätigen</a></p>" +
+			"<p>Dieser Link läuft in 24 Stunden ab.</p>",
+		"confirmation.cancel": "<p>Falls Sie dies nicht angefordert haben, <a href=\"%s\">stornieren Sie diese Anmeldung</a>.</p>",
+
+		"welcome.subject": "Willkommen bei Wetter-Updates für %s",
+		"welcome.body": "<p>Vielen Dank für Ihr Abonnement (%s) der Wetter-Updates für %s.</p>" +
+			"<p>Sie erhalten Updates %s.</p>" +
+			"<p>Zum Abmelden <a href=\"%s\">klicken Sie hier</a>.</p>",
+		"welcome.every_hour": "stündlich",
+		"welcome.every_day":  "täglich",
+
+		"unsubscribe.subject": "Sie haben Wetter-Updates für %s abbestellt",
+		"unsubscribe.body":    "<p>Sie haben Wetter-Updates für %s erfolgreich abbestellt.</p>",
+
+		"unsubscribe_all.subject": "Bestätigen Sie die Abmeldung von allen Wetter-Updates",
+		"unsubscribe_all.body": "<p>Sie haben darum gebeten, Wetter-Updates für alle %d %s dieser Adresse abzubestellen.</p>" +
+			"<p><a href=\"%s\">Alles abbestellen</a></p>" +
+			"<p>Dieser Link läuft in 24 Stunden ab. Falls Sie dies nicht angefordert haben, können Sie diese E-Mail ignorieren.</p>",
+		"unsubscribe_all.city":   "Stadt",
+		"unsubscribe_all.cities": "Städte",
+
+		"weather_update.subject": "Wetter-Update für %s",
+		"weather_update.body": "<h2>Aktuelles Wetter für %s</h2>" +
+			"<p><strong>Temperatur:</strong> %.1f°C</p>" +
+			"<p><strong>Luftfeuchtigkeit:</strong> %.1f%%</p>" +
+			"<p><strong>Beschreibung:</strong> %s</p>" +
+			"%s" +
+			"<p>Zum Abmelden <a href=\"%s\">klicken Sie hier</a>.</p>",
+		"weather_update.observed": "<p><em>Beobachtet am %s</em></p>",
+
+		"weather_digest.subject": "Ihr Wetter-Digest",
+		"weather_digest.intro":   "<h2>Aktuelles Wetter für Ihre Städte</h2>",
+		"weather_digest.city_section": "<h3>%s</h3>" +
+			"<p><strong>Temperatur:</strong> %.1f°C</p>" +
+			"<p><strong>Luftfeuchtigkeit:</strong> %.1f%%</p>" +
+			"<p><strong>Beschreibung:</strong> %s</p>" +
+			"<p>Um Updates für %s abzubestellen, <a href=\"%s\">klicken Sie hier</a>.</p>",
+
+		"weather_alert.subject": "Wetterwarnung für %s",
+		"weather_alert.body": "<h2>Wetterwarnung für %s</h2>" +
+			"<p>%s.</p>" +
+			"<p><strong>Temperatur:</strong> %.1f°C</p>" +
+			"<p><strong>Luftfeuchtigkeit:</strong> %.1f%%</p>" +
+			"<p><strong>Beschreibung:</strong> %s</p>" +
+			"<p>Zum Abmelden <a href=\"%s\">klicken Sie hier</a>.</p>",
+	},
+}
+
+// emailText returns the message for key in lang, falling back to English
+// for unknown languages or untranslated keys
+func emailText(lang, key string) string {
+	if messages, ok := emailCopy[lang]; ok {
+		if msg, ok := messages[key]; ok {
+			return msg
+		}
+	}
+	return emailCopy[models.LanguageEnglish.String()][key]
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	weathererr "weatherapi.app/errors"
+)
+
+const (
+	// emailQueueBufferPerWorker sizes the job channel so producers rarely
+	// block while workers are busy
+	emailQueueBufferPerWorker = 16
+
+	// emailQueueMaxRetries bounds how often a worker re-attempts a failed
+	// send before reporting the failure to the producer
+	emailQueueMaxRetries = 2
+
+	// emailQueueRetryBaseDelay is the starting backoff delay between retries
+	emailQueueRetryBaseDelay = time.Second
+)
+
+// FailedEmailRecorder persists terminally failed sends so operators can
+// inspect and re-drive them later
+type FailedEmailRecorder interface {
+	Record(to, subject, reason string) error
+}
+
+// emailJob carries one weather update send through the queue along with the
+// channel its outcome is reported on
+type emailJob struct {
+	params WeatherUpdateEmailParams
+	result chan<- error
+}
+
+// EmailQueue delivers weather update emails through a fixed pool of workers
+// so bulk update runs are not serialized behind SMTP latency. Failed sends
+// are retried a bounded number of times before the failure is reported
+type EmailQueue struct {
+	emailService   EmailServiceInterface
+	recorder       FailedEmailRecorder
+	jobs           chan emailJob
+	wg             sync.WaitGroup
+	closeOnce      sync.Once
+	maxRetries     int
+	retryBaseDelay time.Duration
+}
+
+// NewEmailQueue starts the given number of worker goroutines; counts below
+// one are clamped so a zero-value configuration still sends
+func NewEmailQueue(emailService EmailServiceInterface, workers int) *EmailQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := &EmailQueue{
+		emailService:   emailService,
+		jobs:           make(chan emailJob, workers*emailQueueBufferPerWorker),
+		maxRetries:     emailQueueMaxRetries,
+		retryBaseDelay: emailQueueRetryBaseDelay,
+	}
+
+	for i := 0; i < workers; i++ {
+		queue.wg.Add(1)
+		go queue.worker()
+	}
+
+	return queue
+}
+
+// NewEmailQueueWithRecorder additionally dead-letters sends that failed all
+// retries into the given recorder
+func NewEmailQueueWithRecorder(emailService EmailServiceInterface, recorder FailedEmailRecorder, workers int) *EmailQueue {
+	queue := NewEmailQueue(emailService, workers)
+	queue.recorder = recorder
+	return queue
+}
+
+// Enqueue schedules one weather update email and returns a channel that
+// receives the send outcome once a worker has processed it
+func (q *EmailQueue) Enqueue(params WeatherUpdateEmailParams) <-chan error {
+	result := make(chan error, 1)
+	q.jobs <- emailJob{params: params, result: result}
+	return result
+}
+
+// Close stops accepting jobs and blocks until every queued send has been
+// processed, so shutdown does not drop emails
+func (q *EmailQueue) Close() {
+	q.closeOnce.Do(func() { close(q.jobs) })
+	q.wg.Wait()
+}
+
+func (q *EmailQueue) worker() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		err := q.sendWithRetry(job.params)
+		if err != nil && q.recorder != nil {
+			if recordErr := q.recorder.Record(job.params.Email, weatherUpdateSubject(job.params.Lang, job.params.City), err.Error()); recordErr != nil {
+				slog.Error("Failed to dead-letter email", "error", recordErr, "email", job.params.Email)
+			}
+		}
+		job.result <- err
+	}
+}
+
+// sendWithRetry re-attempts failed sends with backoff; validation errors are
+// deterministic and fail immediately
+func (q *EmailQueue) sendWithRetry(params WeatherUpdateEmailParams) error {
+	err := q.emailService.SendWeatherUpdateEmailWithParams(params)
+	for attempt := 1; attempt <= q.maxRetries && isRetryableSendError(err); attempt++ {
+		slog.Warn("Retrying queued email send", "email", params.Email, "attempt", attempt, "error", err)
+		time.Sleep(q.retryBaseDelay << (attempt - 1))
+		err = q.emailService.SendWeatherUpdateEmailWithParams(params)
+	}
+	return err
+}
+
+// isRetryableSendError reports whether re-sending could help
+func isRetryableSendError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var appErr *weathererr.AppError
+	if errors.As(err, &appErr) && appErr.Type == weathererr.ValidationError {
+		return false
+	}
+	return true
+}
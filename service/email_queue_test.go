@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+func TestEmailQueue_DeliversQueuedSends(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil)
+
+	queue := NewEmailQueue(mockEmailService, 2)
+	defer queue.Close()
+
+	var results []<-chan error
+	for i := 0; i < 5; i++ {
+		results = append(results, queue.Enqueue(WeatherUpdateEmailParams{Email: "test@example.com"}))
+	}
+	for _, result := range results {
+		assert.NoError(t, <-result)
+	}
+
+	mockEmailService.AssertNumberOfCalls(t, "SendWeatherUpdateEmailWithParams", 5)
+}
+
+func TestEmailQueue_RetriesFailedSends(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).
+		Return(weathererr.NewEmailError("smtp down", nil)).Twice()
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil).Once()
+
+	queue := NewEmailQueue(mockEmailService, 1)
+	queue.retryBaseDelay = time.Millisecond
+	defer queue.Close()
+
+	assert.NoError(t, <-queue.Enqueue(WeatherUpdateEmailParams{Email: "test@example.com"}))
+	mockEmailService.AssertNumberOfCalls(t, "SendWeatherUpdateEmailWithParams", 3)
+}
+
+func TestEmailQueue_DoesNotRetryValidationErrors(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).
+		Return(weathererr.NewValidationError("email cannot be empty"))
+
+	queue := NewEmailQueue(mockEmailService, 1)
+	queue.retryBaseDelay = time.Millisecond
+	defer queue.Close()
+
+	assert.Error(t, <-queue.Enqueue(WeatherUpdateEmailParams{}))
+	mockEmailService.AssertNumberOfCalls(t, "SendWeatherUpdateEmailWithParams", 1)
+}
+
+func TestEmailQueue_CloseDrainsPendingSends(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).
+		Run(func(mock.Arguments) { time.Sleep(5 * time.Millisecond) }).Return(nil)
+
+	queue := NewEmailQueue(mockEmailService, 1)
+	for i := 0; i < 4; i++ {
+		queue.Enqueue(WeatherUpdateEmailParams{Email: "test@example.com"})
+	}
+
+	queue.Close()
+	mockEmailService.AssertNumberOfCalls(t, "SendWeatherUpdateEmailWithParams", 4)
+}
+
+// mockFailedEmailRecorder mocks the queue's dead-letter log
+type mockFailedEmailRecorder struct {
+	mock.Mock
+}
+
+func (m *mockFailedEmailRecorder) Record(to, subject, reason string) error {
+	args := m.Called(to, subject, reason)
+	return args.Error(0)
+}
+
+func TestEmailQueue_DeadLettersTerminalFailures(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).
+		Return(weathererr.NewEmailError("smtp down", nil))
+
+	recorder := new(mockFailedEmailRecorder)
+	recorder.On("Record", "test@example.com", "Weather Update for London", mock.AnythingOfType("string")).Return(nil)
+
+	queue := NewEmailQueueWithRecorder(mockEmailService, recorder, 1)
+	queue.retryBaseDelay = time.Millisecond
+	defer queue.Close()
+
+	assert.Error(t, <-queue.Enqueue(WeatherUpdateEmailParams{Email: "test@example.com", City: "London"}))
+	recorder.AssertExpectations(t)
+}
+
+func TestEmailQueue_DoesNotDeadLetterSuccesses(t *testing.T) {
+	mockEmailService := new(mockEmailService)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil)
+
+	recorder := new(mockFailedEmailRecorder)
+
+	queue := NewEmailQueueWithRecorder(mockEmailService, recorder, 1)
+	defer queue.Close()
+
+	assert.NoError(t, <-queue.Enqueue(WeatherUpdateEmailParams{Email: "test@example.com", City: "London"}))
+	recorder.AssertNotCalled(t, "Record")
+}
+
+func TestSubscriptionService_SendWeatherUpdate_Queued(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	queue := NewEmailQueue(mockEmailService, 2)
+	defer queue.Close()
+
+	service := NewSubscriptionServiceWithEmailQueue(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		queue,
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "ok@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{ID: 2, Email: "broken@example.com", City: "Paris", Frequency: "daily", Confirmed: true},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "daily").Return(subscriptions, nil)
+	mockSubRepo.On("TouchLastNotified", uint(1)).Return(nil)
+	mockWeatherService.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	mockWeatherService.On("GetWeather", "Paris").Return((*models.WeatherResponse)(nil), weathererr.NewExternalAPIError("provider down", nil))
+	mockTokenRepo.On("FindBySubscriptionIDAndType", uint(1), "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil)
+
+	result, err := service.SendWeatherUpdate("daily")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "broken@example.com")
+	mockSubRepo.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
@@ -3,8 +3,11 @@ package service
 import (
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"weatherapi.app/errors"
+	"weatherapi.app/metrics"
 	"weatherapi.app/models"
 	"weatherapi.app/providers"
 )
@@ -12,6 +15,7 @@ import (
 // EmailService handles email operations using a provider
 type EmailService struct {
 	provider providers.EmailProvider
+	renderer *TemplateRenderer
 }
 
 // NewEmailService creates a new email service with the specified provider
@@ -21,11 +25,72 @@ func NewEmailService(provider providers.EmailProvider) *EmailService {
 	}
 }
 
+// NewEmailServiceWithRenderer creates an email service that renders bodies
+// from the renderer's template files, falling back to the embedded defaults
+// for emails without a template
+func NewEmailServiceWithRenderer(provider providers.EmailProvider, renderer *TemplateRenderer) *EmailService {
+	return &EmailService{
+		provider: provider,
+		renderer: renderer,
+	}
+}
+
+// sendHTML delivers an HTML email, attaching a generated plain-text
+// alternative when the provider can send multipart messages so text-only
+// clients get a readable copy. Every attempt is recorded in the email
+// metrics under the given email type. The city scopes the provider's From
+// name when it is templated; emails without a single city pass ""
+func (s *EmailService) sendHTML(emailType, city, to, subject, htmlBody string) error {
+	start := time.Now()
+	err := s.deliverHTML(city, to, subject, htmlBody)
+	metrics.ObserveEmailSend(emailType, start, err)
+	return err
+}
+
+func (s *EmailService) deliverHTML(city, to, subject, htmlBody string) error {
+	provider := s.provider
+	if scoped, ok := provider.(providers.CityScopedEmailSender); ok && city != "" {
+		provider = scoped.ForCity(city)
+	}
+	if sender, ok := provider.(providers.MultipartEmailSender); ok {
+		if textBody := htmlToPlainText(htmlBody); textBody != "" {
+			return sender.SendMultipartEmail(to, subject, htmlBody, textBody)
+		}
+	}
+	return provider.SendEmail(to, subject, htmlBody, true)
+}
+
+// renderBody renders the named template with data, returning fallback when
+// no renderer is configured or the template is missing. A language-suffixed
+// template (e.g. confirmation.uk.html) takes precedence for that language
+func (s *EmailService) renderBody(name, lang string, data interface{}, fallback string) string {
+	if s.renderer == nil {
+		return fallback
+	}
+	if lang != "" {
+		if body, ok := s.renderer.Render(name+"."+lang, data); ok {
+			return body
+		}
+	}
+	if body, ok := s.renderer.Render(name, data); ok {
+		return body
+	}
+	return fallback
+}
+
+// weatherUpdateSubject builds the weather update subject line; shared with
+// the email queue's dead-letter records
+func weatherUpdateSubject(lang, city string) string {
+	return fmt.Sprintf(emailText(lang, "weather_update.subject"), city)
+}
+
 // ConfirmationEmailParams holds parameters for sending confirmation emails
 type ConfirmationEmailParams struct {
 	Email      string
 	ConfirmURL string
 	City       string
+	CancelURL  string // optional; included when cancel links are enabled
+	Lang       string // subscriber language; empty means English
 }
 
 // validateConfirmationEmailParams validates parameters for confirmation email
@@ -48,6 +113,7 @@ type WelcomeEmailParams struct {
 	City           string
 	Frequency      string
 	UnsubscribeURL string
+	Lang           string
 }
 
 // validateWelcomeEmailParams validates parameters for welcome email
@@ -71,6 +137,7 @@ func (s *EmailService) validateWelcomeEmailParams(params WelcomeEmailParams) err
 type UnsubscribeEmailParams struct {
 	Email string
 	City  string
+	Lang  string
 }
 
 // validateUnsubscribeEmailParams validates parameters for unsubscribe email
@@ -84,12 +151,35 @@ func (s *EmailService) validateUnsubscribeEmailParams(params UnsubscribeEmailPar
 	return nil
 }
 
+// UnsubscribeAllEmailParams holds parameters for unsubscribe-all confirmation emails
+type UnsubscribeAllEmailParams struct {
+	Email             string
+	ConfirmURL        string
+	SubscriptionCount int
+	Lang              string
+}
+
+// validateUnsubscribeAllEmailParams validates parameters for unsubscribe-all email
+func (s *EmailService) validateUnsubscribeAllEmailParams(params UnsubscribeAllEmailParams) error {
+	if params.Email == "" {
+		return errors.NewValidationError("email cannot be empty")
+	}
+	if params.ConfirmURL == "" {
+		return errors.NewValidationError("confirmation URL cannot be empty")
+	}
+	if params.SubscriptionCount < 1 {
+		return errors.NewValidationError("subscription count must be positive")
+	}
+	return nil
+}
+
 // WeatherUpdateEmailParams holds parameters for weather update emails
 type WeatherUpdateEmailParams struct {
 	Email          string
 	City           string
 	Weather        *models.WeatherResponse
 	UnsubscribeURL string
+	Lang           string
 }
 
 // validateWeatherUpdateEmailParams validates parameters for weather update email
@@ -109,6 +199,72 @@ func (s *EmailService) validateWeatherUpdateEmailParams(params WeatherUpdateEmai
 	return nil
 }
 
+// WeatherDigestCity holds one city's section of a multi-city digest email
+type WeatherDigestCity struct {
+	City           string
+	Weather        *models.WeatherResponse
+	UnsubscribeURL string
+}
+
+// WeatherDigestEmailParams holds parameters for multi-city digest emails
+type WeatherDigestEmailParams struct {
+	Email  string
+	Cities []WeatherDigestCity
+	Lang   string
+}
+
+// validateWeatherDigestEmailParams validates parameters for digest email
+func (s *EmailService) validateWeatherDigestEmailParams(params WeatherDigestEmailParams) error {
+	if params.Email == "" {
+		return errors.NewValidationError("email cannot be empty")
+	}
+	if len(params.Cities) == 0 {
+		return errors.NewValidationError("digest must cover at least one city")
+	}
+	for _, city := range params.Cities {
+		if city.City == "" {
+			return errors.NewValidationError("city cannot be empty")
+		}
+		if city.Weather == nil {
+			return errors.NewValidationError("weather data cannot be nil")
+		}
+		if city.UnsubscribeURL == "" {
+			return errors.NewValidationError("unsubscribe URL cannot be empty")
+		}
+	}
+	return nil
+}
+
+// WeatherAlertEmailParams holds parameters for severe-weather alert emails
+type WeatherAlertEmailParams struct {
+	Email          string
+	City           string
+	Reason         string // human-readable description of the crossed threshold
+	Weather        *models.WeatherResponse
+	UnsubscribeURL string
+	Lang           string
+}
+
+// validateWeatherAlertEmailParams validates parameters for weather alert email
+func (s *EmailService) validateWeatherAlertEmailParams(params WeatherAlertEmailParams) error {
+	if params.Email == "" {
+		return errors.NewValidationError("email cannot be empty")
+	}
+	if params.City == "" {
+		return errors.NewValidationError("city cannot be empty")
+	}
+	if params.Reason == "" {
+		return errors.NewValidationError("alert reason cannot be empty")
+	}
+	if params.Weather == nil {
+		return errors.NewValidationError("weather data cannot be nil")
+	}
+	if params.UnsubscribeURL == "" {
+		return errors.NewValidationError("unsubscribe URL cannot be empty")
+	}
+	return nil
+}
+
 // SendConfirmationEmailWithParams sends a confirmation email using parameter struct
 func (s *EmailService) SendConfirmationEmailWithParams(params ConfirmationEmailParams) error {
 	slog.Debug("Sending confirmation email", "email", params.Email, "city", params.City)
@@ -117,15 +273,18 @@ func (s *EmailService) SendConfirmationEmailWithParams(params ConfirmationEmailP
 		return err
 	}
 
-	subject := fmt.Sprintf("Confirm your weather subscription for %s", params.City)
-	htmlContent := fmt.Sprintf(
-		"<p>Please confirm your subscription to weather updates for %s by clicking the following link:</p>"+
-			"<p><a href=\"%s\">Confirm Subscription</a></p>"+
-			"<p>This link will expire in 24 hours.</p>",
-		params.City, params.ConfirmURL,
-	)
+	subject := fmt.Sprintf(emailText(params.Lang, "confirmation.subject"), params.City)
+	return s.sendHTML("confirmation", params.City, params.Email, subject, s.confirmationBody(params))
+}
 
-	return s.provider.SendEmail(params.Email, subject, htmlContent, true)
+// confirmationBody builds the confirmation email HTML; shared with the
+// admin preview endpoint
+func (s *EmailService) confirmationBody(params ConfirmationEmailParams) string {
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "confirmation.body"), params.City, params.ConfirmURL)
+	if params.CancelURL != "" {
+		htmlContent += fmt.Sprintf(emailText(params.Lang, "confirmation.cancel"), params.CancelURL)
+	}
+	return s.renderBody("confirmation", params.Lang, params, htmlContent)
 }
 
 // SendWelcomeEmailWithParams sends a welcome email using parameter struct
@@ -136,20 +295,21 @@ func (s *EmailService) SendWelcomeEmailWithParams(params WelcomeEmailParams) err
 		return err
 	}
 
-	subject := fmt.Sprintf("Welcome to Weather Updates for %s", params.City)
-	frequencyText := "every hour"
+	subject := fmt.Sprintf(emailText(params.Lang, "welcome.subject"), params.City)
+	return s.sendHTML("welcome", params.City, params.Email, subject, s.welcomeBody(params))
+}
+
+// welcomeBody builds the welcome email HTML; shared with the admin preview
+// endpoint
+func (s *EmailService) welcomeBody(params WelcomeEmailParams) string {
+	frequencyText := emailText(params.Lang, "welcome.every_hour")
 	if params.Frequency == "daily" {
-		frequencyText = "every day"
+		frequencyText = emailText(params.Lang, "welcome.every_day")
 	}
 
-	htmlContent := fmt.Sprintf(
-		"<p>Thank you for subscribing to %s weather updates for %s.</p>"+
-			"<p>You will receive updates %s.</p>"+
-			"<p>To unsubscribe, <a href=\"%s\">click here</a>.</p>",
-		params.Frequency, params.City, frequencyText, params.UnsubscribeURL,
-	)
-
-	return s.provider.SendEmail(params.Email, subject, htmlContent, true)
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "welcome.body"),
+		params.Frequency, params.City, frequencyText, params.UnsubscribeURL)
+	return s.renderBody("welcome", params.Lang, params, htmlContent)
 }
 
 // SendUnsubscribeConfirmationEmailWithParams sends unsubscribe confirmation using parameter struct
@@ -160,13 +320,37 @@ func (s *EmailService) SendUnsubscribeConfirmationEmailWithParams(params Unsubsc
 		return err
 	}
 
-	subject := fmt.Sprintf("You have unsubscribed from weather updates for %s", params.City)
-	htmlContent := fmt.Sprintf(
-		"<p>You have successfully unsubscribed from weather updates for %s.</p>",
-		params.City,
-	)
+	subject := fmt.Sprintf(emailText(params.Lang, "unsubscribe.subject"), params.City)
+	return s.sendHTML("unsubscribe", params.City, params.Email, subject, s.unsubscribeBody(params))
+}
 
-	return s.provider.SendEmail(params.Email, subject, htmlContent, true)
+// unsubscribeBody builds the unsubscribe confirmation HTML; shared with the
+// admin preview endpoint
+func (s *EmailService) unsubscribeBody(params UnsubscribeEmailParams) string {
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "unsubscribe.body"), params.City)
+	return s.renderBody("unsubscribe", params.Lang, params, htmlContent)
+}
+
+// SendUnsubscribeAllEmailWithParams sends a confirmation link that removes
+// every subscription for an email address
+func (s *EmailService) SendUnsubscribeAllEmailWithParams(params UnsubscribeAllEmailParams) error {
+	slog.Debug("Sending unsubscribe-all confirmation email", "email", params.Email, "count", params.SubscriptionCount)
+
+	if err := s.validateUnsubscribeAllEmailParams(params); err != nil {
+		return err
+	}
+
+	cityText := emailText(params.Lang, "unsubscribe_all.city")
+	if params.SubscriptionCount > 1 {
+		cityText = emailText(params.Lang, "unsubscribe_all.cities")
+	}
+
+	subject := emailText(params.Lang, "unsubscribe_all.subject")
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "unsubscribe_all.body"),
+		params.SubscriptionCount, cityText, params.ConfirmURL)
+
+	// Unsubscribe-all spans every city, so the From name stays unscoped
+	return s.sendHTML("unsubscribe_all", "", params.Email, subject, s.renderBody("unsubscribe_all", params.Lang, params, htmlContent))
 }
 
 // SendWeatherUpdateEmailWithParams sends weather update email using parameter struct
@@ -177,15 +361,116 @@ func (s *EmailService) SendWeatherUpdateEmailWithParams(params WeatherUpdateEmai
 		return err
 	}
 
-	subject := fmt.Sprintf("Weather Update for %s", params.City)
-	htmlContent := fmt.Sprintf(
-		"<h2>Current weather for %s</h2>"+
-			"<p><strong>Temperature:</strong> %.1f°C</p>"+
-			"<p><strong>Humidity:</strong> %.1f%%</p>"+
-			"<p><strong>Description:</strong> %s</p>"+
-			"<p>To unsubscribe, <a href=\"%s\">click here</a>.</p>",
-		params.City, params.Weather.Temperature, params.Weather.Humidity, params.Weather.Description, params.UnsubscribeURL,
+	subject := weatherUpdateSubject(params.Lang, params.City)
+	return s.sendHTML("weather_update", params.City, params.Email, subject, s.weatherUpdateBody(params))
+}
+
+// weatherUpdateBody builds the weather update email HTML; shared with the
+// admin preview endpoint
+func (s *EmailService) weatherUpdateBody(params WeatherUpdateEmailParams) string {
+	// Mention when the provider observed these conditions, if it told us
+	observedNote := ""
+	if params.Weather.ProviderObservedAt != nil {
+		observedNote = fmt.Sprintf(emailText(params.Lang, "weather_update.observed"), params.Weather.ProviderObservedAt.Format(time.RFC1123))
+	}
+
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "weather_update.body"),
+		params.City, params.Weather.Temperature, params.Weather.Humidity, params.Weather.Description, observedNote, params.UnsubscribeURL)
+	return s.renderBody("weather_update", params.Lang, params, htmlContent)
+}
+
+// SendWeatherDigestEmailWithParams sends a single email covering all of a
+// subscriber's cities for one update run
+func (s *EmailService) SendWeatherDigestEmailWithParams(params WeatherDigestEmailParams) error {
+	slog.Debug("Sending weather digest email", "email", params.Email, "cities", len(params.Cities))
+
+	if err := s.validateWeatherDigestEmailParams(params); err != nil {
+		return err
+	}
+
+	subject := emailText(params.Lang, "weather_digest.subject")
+	// The digest spans several cities, so the From name stays unscoped
+	return s.sendHTML("weather_digest", "", params.Email, subject, s.weatherDigestBody(params))
+}
+
+// weatherDigestBody builds the digest email HTML from one section per city
+func (s *EmailService) weatherDigestBody(params WeatherDigestEmailParams) string {
+	var body strings.Builder
+	body.WriteString(emailText(params.Lang, "weather_digest.intro"))
+	for _, city := range params.Cities {
+		body.WriteString(fmt.Sprintf(emailText(params.Lang, "weather_digest.city_section"),
+			city.City, city.Weather.Temperature, city.Weather.Humidity, city.Weather.Description,
+			city.City, city.UnsubscribeURL))
+	}
+	return s.renderBody("weather_digest", params.Lang, params, body.String())
+}
+
+// SendWeatherAlertEmailWithParams notifies a subscriber that current
+// conditions crossed one of their alert thresholds
+func (s *EmailService) SendWeatherAlertEmailWithParams(params WeatherAlertEmailParams) error {
+	slog.Debug("Sending weather alert email", "email", params.Email, "city", params.City, "reason", params.Reason)
+
+	if err := s.validateWeatherAlertEmailParams(params); err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf(emailText(params.Lang, "weather_alert.subject"), params.City)
+	htmlContent := fmt.Sprintf(emailText(params.Lang, "weather_alert.body"),
+		params.City, params.Reason, params.Weather.Temperature, params.Weather.Humidity, params.Weather.Description, params.UnsubscribeURL)
+
+	return s.sendHTML("weather_alert", params.City, params.Email, subject, s.renderBody("weather_alert", params.Lang, params, htmlContent))
+}
+
+// RenderEmailPreview renders the named email type with sample data so the
+// template output can be inspected without sending anything or touching a
+// subscription. City and frequency default to "London" and "daily"
+func (s *EmailService) RenderEmailPreview(emailType, city, frequency string) (string, error) {
+	if city == "" {
+		city = "London"
+	}
+	if frequency == "" {
+		frequency = "daily"
+	}
+
+	const (
+		sampleEmail = "preview@example.com"
+		sampleURL   = "https://example.com/api"
 	)
 
-	return s.provider.SendEmail(params.Email, subject, htmlContent, true)
+	switch emailType {
+	case "confirmation":
+		return s.confirmationBody(ConfirmationEmailParams{
+			Email:      sampleEmail,
+			City:       city,
+			ConfirmURL: sampleURL + "/confirm/sample-token",
+			CancelURL:  sampleURL + "/cancel/sample-token",
+		}), nil
+	case "welcome":
+		return s.welcomeBody(WelcomeEmailParams{
+			Email:          sampleEmail,
+			City:           city,
+			Frequency:      frequency,
+			UnsubscribeURL: sampleURL + "/unsubscribe/sample-token",
+		}), nil
+	case "unsubscribe":
+		return s.unsubscribeBody(UnsubscribeEmailParams{
+			Email: sampleEmail,
+			City:  city,
+		}), nil
+	case "weather_update":
+		observedAt := time.Now().UTC()
+		return s.weatherUpdateBody(WeatherUpdateEmailParams{
+			Email: sampleEmail,
+			City:  city,
+			Weather: &models.WeatherResponse{
+				Temperature:        21.5,
+				Humidity:           64,
+				Description:        "Partly cloudy",
+				ProviderObservedAt: &observedAt,
+			},
+			UnsubscribeURL: sampleURL + "/unsubscribe/sample-token",
+		}), nil
+	default:
+		return "", errors.NewValidationError(fmt.Sprintf("unknown email type: %s", emailType))
+	}
 }
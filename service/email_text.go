@@ -0,0 +1,32 @@
+package service
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlLineBreaks = regexp.MustCompile(`(?i)</(?:p|h[1-6])>|<br\s*/?>`)
+	htmlAnchors    = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlTags       = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// htmlToPlainText derives a plain-text rendition of an HTML email body for
+// the multipart/alternative part: block endings become line breaks, links
+// keep their text with the URL in parentheses, and remaining markup is
+// stripped
+func htmlToPlainText(htmlBody string) string {
+	text := htmlLineBreaks.ReplaceAllString(htmlBody, "\n")
+	text = htmlAnchors.ReplaceAllString(text, "$2 ($1)")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockMultipartEmailProvider mocks a provider that supports the
+// multipart/alternative capability
+type mockMultipartEmailProvider struct {
+	mock.Mock
+}
+
+func (m *mockMultipartEmailProvider) SendEmail(to, subject, body string, isHTML bool) error {
+	args := m.Called(to, subject, body, isHTML)
+	return args.Error(0)
+}
+
+func (m *mockMultipartEmailProvider) SendMultipartEmail(to, subject, htmlBody, textBody string) error {
+	args := m.Called(to, subject, htmlBody, textBody)
+	return args.Error(0)
+}
+
+func TestHTMLToPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "ParagraphsBecomeLines",
+			html:     "<p>First line.</p><p>Second line.</p>",
+			expected: "First line.\nSecond line.",
+		},
+		{
+			name:     "LinksKeepTextAndURL",
+			html:     "<p>To unsubscribe, <a href=\"http://example.com/unsub\">click here</a>.</p>",
+			expected: "To unsubscribe, click here (http://example.com/unsub).",
+		},
+		{
+			name:     "TagsAndEntitiesStripped",
+			html:     "<h2>Weather for London</h2><p><strong>Temperature:</strong> 15.0&deg;C</p>",
+			expected: "Weather for London\nTemperature: 15.0°C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, htmlToPlainText(tt.html))
+		})
+	}
+}
+
+func TestEmailService_SendsMultipartWhenSupported(t *testing.T) {
+	mockProvider := new(mockMultipartEmailProvider)
+	emailService := NewEmailService(mockProvider)
+
+	mockProvider.On("SendMultipartEmail", "test@example.com", "Confirm your weather subscription for London",
+		mock.AnythingOfType("string"), "Please confirm your subscription to weather updates for London by clicking the following link:\n"+
+			"Confirm Subscription (http://example.com/confirm)\n"+
+			"This link will expire in 24 hours.").Return(nil)
+
+	err := emailService.SendConfirmationEmailWithParams(ConfirmationEmailParams{
+		Email:      "test@example.com",
+		ConfirmURL: "http://example.com/confirm",
+		City:       "London",
+	})
+
+	assert.NoError(t, err)
+	mockProvider.AssertExpectations(t)
+	mockProvider.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
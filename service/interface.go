@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	"weatherapi.app/models"
@@ -15,13 +16,23 @@ type WeatherProviderMetricsInterface = providers.WeatherProviderMetrics
 
 // WeatherServiceInterface defines the interface for weather operations
 type WeatherServiceInterface interface {
-	GetWeather(city string) (*models.WeatherResponse, error)
+	GetWeather(ctx context.Context, city string) (*models.WeatherResponse, error)
+	GetForecast(city string, days int) (*models.ForecastResponse, error)
+}
+
+// ObservationStore records successful weather fetches for the history
+// endpoint; saves are best-effort and must not fail lookups
+type ObservationStore interface {
+	Save(city string, weather *models.WeatherResponse) error
 }
 
 // SubscriptionManagerInterface handles subscription creation and removal
 type SubscriptionManagerInterface interface {
-	Subscribe(req *models.SubscriptionRequest) error
+	Subscribe(req *models.SubscriptionRequest) (*models.Subscription, error)
 	Unsubscribe(token string) error
+	CancelSubscription(token string) error
+	RequestUnsubscribeAll(email string) error
+	UnsubscribeAll(token string) error
 }
 
 // ConfirmationServiceInterface handles subscription confirmations
@@ -31,7 +42,7 @@ type ConfirmationServiceInterface interface {
 
 // NotificationServiceInterface handles sending notifications
 type NotificationServiceInterface interface {
-	SendWeatherUpdate(frequency string) error
+	SendWeatherUpdate(frequency string) (*WeatherUpdateResult, error)
 }
 
 // Combined interface for backward compatibility
@@ -46,26 +57,41 @@ type EmailServiceInterface interface {
 	SendConfirmationEmailWithParams(params ConfirmationEmailParams) error
 	SendWelcomeEmailWithParams(params WelcomeEmailParams) error
 	SendUnsubscribeConfirmationEmailWithParams(params UnsubscribeEmailParams) error
+	SendUnsubscribeAllEmailWithParams(params UnsubscribeAllEmailParams) error
 	SendWeatherUpdateEmailWithParams(params WeatherUpdateEmailParams) error
+	SendWeatherDigestEmailWithParams(params WeatherDigestEmailParams) error
+	SendWeatherAlertEmailWithParams(params WeatherAlertEmailParams) error
 }
 
 // SubscriptionRepositoryInterface defines the interface for subscription data operations
 type SubscriptionRepositoryInterface interface {
 	FindByEmail(email, city string) (*models.Subscription, error)
+	FindAllByEmail(email string) ([]models.Subscription, error)
 	FindByID(id uint) (*models.Subscription, error)
+	CountByEmail(email string) (int64, error)
 	Create(subscription *models.Subscription) error
 	Update(subscription *models.Subscription) error
 	Delete(subscription *models.Subscription) error
 	GetSubscriptionsForUpdates(frequency string) ([]models.Subscription, error)
+	TouchLastNotified(id uint, at time.Time) error
+	AlertCities() ([]string, error)
+	GetAlertSubscriptionsForCity(city string) ([]models.Subscription, error)
+}
+
+// NotificationRunRecorderInterface records weather update runs per send
+// window, so a restarted scheduler can recognize a window already in progress
+type NotificationRunRecorderInterface interface {
+	RecordRun(frequency string, windowStart time.Time) (bool, error)
 }
 
 // TokenRepositoryInterface defines the interface for token operations
 type TokenRepositoryInterface interface {
 	CreateToken(subscriptionID uint, tokenType string, expiresIn time.Duration) (*models.Token, error)
+	CreateEmailToken(email, tokenType string, expiresIn time.Duration) (*models.Token, error)
 	FindByToken(tokenStr string) (*models.Token, error)
 	FindBySubscriptionIDAndType(subscriptionID uint, tokenType string) (*models.Token, error)
 	DeleteToken(token *models.Token) error
-	DeleteExpiredTokens() error
+	DeleteExpiredTokens() (int64, error)
 }
 
 // Ensure implementations satisfy interfaces
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// mxLookupTimeout bounds how long a subscribe request waits on DNS
+	mxLookupTimeout = 3 * time.Second
+
+	// mxCacheTTL is how long a lookup result is reused, so signup bursts for
+	// the same domain do one DNS query instead of many
+	mxCacheTTL = 5 * time.Minute
+)
+
+type mxCacheEntry struct {
+	hasMX   bool
+	expires time.Time
+}
+
+// mxVerifier checks whether an email domain has MX records, caching results
+// briefly. Lookups that fail for reasons other than "no such records" (e.g.
+// a DNS outage) count as passing, so transient resolver trouble never blocks
+// signups
+type mxVerifier struct {
+	// lookupMX is swappable in tests; it defaults to the system resolver
+	lookupMX func(ctx context.Context, domain string) ([]*net.MX, error)
+
+	mu    sync.Mutex
+	cache map[string]mxCacheEntry
+}
+
+func newMXVerifier() *mxVerifier {
+	return &mxVerifier{
+		lookupMX: net.DefaultResolver.LookupMX,
+		cache:    make(map[string]mxCacheEntry),
+	}
+}
+
+// hasMX reports whether the domain has at least one MX record
+func (v *mxVerifier) hasMX(domain string) bool {
+	v.mu.Lock()
+	if entry, ok := v.cache[domain]; ok && time.Now().Before(entry.expires) {
+		v.mu.Unlock()
+		return entry.hasMX
+	}
+	v.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), mxLookupTimeout)
+	defer cancel()
+
+	records, err := v.lookupMX(ctx, domain)
+	hasMX := len(records) > 0
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			// Fail open on timeouts and resolver errors
+			hasMX = true
+		}
+	}
+
+	v.mu.Lock()
+	v.cache[domain] = mxCacheEntry{hasMX: hasMX, expires: time.Now().Add(mxCacheTTL)}
+	v.mu.Unlock()
+
+	return hasMX
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"weatherapi.app/config"
+	weathererr "weatherapi.app/errors"
+	"weatherapi.app/models"
+)
+
+func TestMXVerifier(t *testing.T) {
+	t.Run("DomainWithMX", func(t *testing.T) {
+		verifier := newMXVerifier()
+		verifier.lookupMX = func(_ context.Context, domain string) ([]*net.MX, error) {
+			assert.Equal(t, "example.com", domain)
+			return []*net.MX{{Host: "mx.example.com"}}, nil
+		}
+
+		assert.True(t, verifier.hasMX("example.com"))
+	})
+
+	t.Run("DomainWithoutMX", func(t *testing.T) {
+		verifier := newMXVerifier()
+		verifier.lookupMX = func(_ context.Context, _ string) ([]*net.MX, error) {
+			return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+		}
+
+		assert.False(t, verifier.hasMX("gamil.com"))
+	})
+
+	t.Run("ResolverOutageFailsOpen", func(t *testing.T) {
+		verifier := newMXVerifier()
+		verifier.lookupMX = func(_ context.Context, _ string) ([]*net.MX, error) {
+			return nil, errors.New("dns unreachable")
+		}
+
+		assert.True(t, verifier.hasMX("example.com"))
+	})
+
+	t.Run("ResultsCached", func(t *testing.T) {
+		lookups := 0
+		verifier := newMXVerifier()
+		verifier.lookupMX = func(_ context.Context, _ string) ([]*net.MX, error) {
+			lookups++
+			return []*net.MX{{Host: "mx.example.com"}}, nil
+		}
+
+		assert.True(t, verifier.hasMX("example.com"))
+		assert.True(t, verifier.hasMX("example.com"))
+		assert.Equal(t, 1, lookups)
+	})
+}
+
+func TestSubscriptionService_Subscribe_NoMXRecords(t *testing.T) {
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Email.VerifyMX = true
+	service := NewSubscriptionService(nil, new(mockSubscriptionRepository), nil, nil, nil, cfg)
+	service.mxVerifier.lookupMX = func(_ context.Context, _ string) ([]*net.MX, error) {
+		return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+	}
+
+	req := &models.SubscriptionRequest{
+		Email:     "user@gamil.com",
+		City:      "London",
+		Frequency: "daily",
+	}
+
+	_, err := service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "no mail servers")
+}
@@ -1,20 +1,31 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"weatherapi.app/config"
 	"weatherapi.app/errors"
+	"weatherapi.app/metrics"
 	"weatherapi.app/models"
+	"weatherapi.app/providers"
+	"weatherapi.app/repository"
 )
 
 // WeatherService handles weather-related operations using provider manager
 // Follows Facade pattern: simple interface to complex provider chain
 type WeatherService struct {
 	providerManager WeatherProviderManagerInterface
+	observations    ObservationStore
+	cityMaxLength   int
 }
 
 // NewWeatherService creates a new weather service with the specified provider manager
@@ -24,33 +35,90 @@ func NewWeatherService(providerManager WeatherProviderManagerInterface) *Weather
 	}
 }
 
+// WithCityMaxLength overrides the default bound on accepted city-name
+// length; non-positive values keep the default
+func (s *WeatherService) WithCityMaxLength(maxLength int) *WeatherService {
+	s.cityMaxLength = maxLength
+	return s
+}
+
+// NewWeatherServiceWithObservations creates a weather service that also
+// records each successful fetch to the given store for the history endpoint
+func NewWeatherServiceWithObservations(providerManager WeatherProviderManagerInterface, observations ObservationStore) *WeatherService {
+	service := NewWeatherService(providerManager)
+	service.observations = observations
+	return service
+}
+
 // GetWeather retrieves current weather information for a specific city
 // Uses chain of responsibility with caching and logging
-func (s *WeatherService) GetWeather(city string) (*models.WeatherResponse, error) {
+func (s *WeatherService) GetWeather(ctx context.Context, city string) (*models.WeatherResponse, error) {
 	slog.Debug("Getting weather", "city", city)
 
 	if city == "" {
 		return nil, errors.NewValidationError("city cannot be empty")
 	}
+	if message, _ := cityNameIssue(city, s.cityMaxLength); message != "" {
+		return nil, errors.NewValidationError(message)
+	}
 
-	weather, err := s.providerManager.GetWeather(city)
+	weather, err := s.providerManager.GetWeather(ctx, city)
 	if err != nil {
 		slog.Error("Weather provider error", "error", err, "city", city)
 		return nil, err
 	}
 
 	slog.Debug("Weather data retrieved", "city", city, "temp", weather.Temperature, "description", weather.Description)
+
+	// History is best-effort: a failed save must not fail the lookup
+	if s.observations != nil {
+		if err := s.observations.Save(city, weather); err != nil {
+			slog.Warn("Failed to store weather observation", "error", err, "city", city)
+		}
+	}
+
 	return weather, nil
 }
 
+// GetForecast retrieves a multi-day forecast for a specific city.
+// A days value of zero defaults to the maximum supported forecast length.
+func (s *WeatherService) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	slog.Debug("Getting forecast", "city", city, "days", days)
+
+	if city == "" {
+		return nil, errors.NewValidationError("city cannot be empty")
+	}
+	if days == 0 {
+		days = providers.MaxForecastDays
+	}
+	if days < 1 || days > providers.MaxForecastDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("days must be between 1 and %d", providers.MaxForecastDays))
+	}
+
+	forecast, err := s.providerManager.GetForecast(city, days)
+	if err != nil {
+		slog.Error("Forecast provider error", "error", err, "city", city)
+		return nil, err
+	}
+
+	slog.Debug("Forecast data retrieved", "city", city, "days", len(forecast.Days))
+	return forecast, nil
+}
+
 // SubscriptionService handles subscription-related business logic
 type SubscriptionService struct {
 	db               *gorm.DB
 	subscriptionRepo SubscriptionRepositoryInterface
 	tokenRepo        TokenRepositoryInterface
 	emailService     EmailServiceInterface
+	emailQueue       *EmailQueue
 	weatherService   WeatherServiceInterface
 	config           *config.Config
+	mxVerifier       *mxVerifier
+	notificationRuns NotificationRunRecorderInterface
+
+	lastRunMu sync.Mutex
+	lastRun   *WeatherRunStats
 }
 
 // NewSubscriptionService creates a new subscription service
@@ -62,50 +130,159 @@ func NewSubscriptionService(
 	weatherService WeatherServiceInterface,
 	config *config.Config,
 ) *SubscriptionService {
-	return &SubscriptionService{
+	service := &SubscriptionService{
 		db:               db,
 		subscriptionRepo: subscriptionRepo,
 		tokenRepo:        tokenRepo,
 		emailService:     emailService,
 		weatherService:   weatherService,
 		config:           config,
+		mxVerifier:       newMXVerifier(),
+	}
+	if db != nil {
+		service.notificationRuns = repository.NewNotificationRunRepository(db)
 	}
+	return service
+}
+
+// NewSubscriptionServiceWithEmailQueue creates a subscription service whose
+// weather update emails are delivered through the given worker queue instead
+// of synchronously in the update loop
+func NewSubscriptionServiceWithEmailQueue(
+	db *gorm.DB,
+	subscriptionRepo SubscriptionRepositoryInterface,
+	tokenRepo TokenRepositoryInterface,
+	emailService EmailServiceInterface,
+	emailQueue *EmailQueue,
+	weatherService WeatherServiceInterface,
+	config *config.Config,
+) *SubscriptionService {
+	service := NewSubscriptionService(db, subscriptionRepo, tokenRepo, emailService, weatherService, config)
+	service.emailQueue = emailQueue
+	return service
 }
 
-// Subscribe creates a new weather subscription or updates an existing one
-func (s *SubscriptionService) Subscribe(req *models.SubscriptionRequest) error {
+// Subscribe creates a new weather subscription or updates an existing one,
+// returning the resulting subscription so callers can echo its state
+func (s *SubscriptionService) Subscribe(req *models.SubscriptionRequest) (*models.Subscription, error) {
 	slog.Debug("Processing subscription", "email", req.Email, "city", req.City, "frequency", req.Frequency)
 
 	if err := s.validateSubscriptionRequest(req); err != nil {
-		return err
+		return nil, err
 	}
 
+	// Store and look up the canonical form so " london " and "LONDON" cannot
+	// create duplicate subscriptions for the same city
+	req.City = models.NormalizeCity(req.City)
+
 	existing, err := s.subscriptionRepo.FindByEmail(req.Email, req.City)
 	if err != nil {
-		return errors.NewDatabaseError("check existing subscription", err)
+		return nil, errors.NewDatabaseError("check existing subscription", err)
 	}
 
 	if existing != nil && existing.Confirmed {
-		return errors.NewAlreadyExistsError("email already subscribed")
+		return nil, errors.NewAlreadyExistsError("email already subscribed")
+	}
+
+	// Only brand-new cities count against the per-email cap; re-subscribing
+	// to a pending city updates it in place
+	if existing == nil && s.config.MaxSubscriptionsPerEmail > 0 {
+		count, err := s.subscriptionRepo.CountByEmail(req.Email)
+		if err != nil {
+			return nil, errors.NewDatabaseError("count subscriptions for email", err)
+		}
+		if count >= int64(s.config.MaxSubscriptionsPerEmail) {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("subscription limit of %d per email reached", s.config.MaxSubscriptionsPerEmail))
+		}
 	}
 
 	subscription, err := s.createOrUpdateSubscription(existing, req)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := s.sendConfirmationEmail(subscription); err != nil {
+		return nil, err
 	}
 
-	return s.sendConfirmationEmail(subscription)
+	return subscription, nil
+}
+
+// defaultCityMaxLength bounds city names when no limit is configured
+const defaultCityMaxLength = 100
+
+// cityNameIssue checks a city name against the length bound and the
+// character whitelist — letters, spaces, hyphens, apostrophes, and periods —
+// that legitimate names need, so oversized or injection-looking input is
+// rejected before it reaches providers or storage. It returns a message and
+// machine-readable reason, both empty when the name is acceptable
+func cityNameIssue(city string, maxLength int) (string, string) {
+	if maxLength <= 0 {
+		maxLength = defaultCityMaxLength
+	}
+	if utf8.RuneCountInString(city) > maxLength {
+		return fmt.Sprintf("city must be at most %d characters", maxLength), "too_long"
+	}
+	for _, r := range city {
+		switch {
+		case unicode.IsLetter(r):
+		case r == ' ' || r == '-' || r == '\'' || r == '’' || r == '.':
+		default:
+			return "city may only contain letters, spaces, hyphens, apostrophes, and periods", "invalid_characters"
+		}
+	}
+	return "", ""
 }
 
 func (s *SubscriptionService) validateSubscriptionRequest(req *models.SubscriptionRequest) error {
 	if req.Email == "" {
-		return errors.NewValidationError("email is required")
+		return errors.NewFieldValidationError("email is required", "email", "required")
+	}
+	if !s.config.Email.DomainAllowed(req.Email) {
+		return errors.NewFieldValidationError("email domain is not allowed", "email", "domain_not_allowed")
+	}
+	if err := s.verifyEmailDomainMX(req.Email); err != nil {
+		return err
 	}
 	if req.City == "" {
-		return errors.NewValidationError("city is required")
+		return errors.NewFieldValidationError("city is required", "city", "required")
+	}
+	if message, reason := cityNameIssue(req.City, s.config.Weather.CityMaxLength); message != "" {
+		return errors.NewFieldValidationError(message, "city", reason)
+	}
+	if _, ok := models.FrequencyFromString(req.Frequency); !ok {
+		return errors.NewFieldValidationError("frequency must be 'hourly', 'daily', 'weekly', or 'alert'", "frequency", "invalid_value")
+	}
+	if req.Frequency == models.FrequencyAlert.String() && !req.HasAlertThreshold() {
+		return errors.NewFieldValidationError("alert subscriptions require at least one threshold", "alert_condition", "required")
+	}
+	if req.SendHour != nil && (*req.SendHour < 0 || *req.SendHour > 23) {
+		return errors.NewFieldValidationError("send_hour must be between 0 and 23", "send_hour", "out_of_range")
 	}
-	if req.Frequency != "hourly" && req.Frequency != "daily" {
-		return errors.NewValidationError("frequency must be either 'hourly' or 'daily'")
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return errors.NewFieldValidationError("timezone must be a valid IANA timezone name", "timezone", "invalid_value")
+		}
+	}
+	if _, ok := models.ParseLanguage(req.Lang); !ok {
+		return errors.NewFieldValidationError("lang must be 'en', 'uk', or 'de'", "lang", "invalid_value")
+	}
+	return nil
+}
+
+// verifyEmailDomainMX rejects domains without MX records when EMAIL_VERIFY_MX
+// is on; typos like gamil.com bounce anyway, so catch them at signup
+func (s *SubscriptionService) verifyEmailDomainMX(email string) error {
+	if !s.config.Email.VerifyMX || s.mxVerifier == nil {
+		return nil
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return nil
+	}
+	if !s.mxVerifier.hasMX(email[at+1:]) {
+		return errors.NewFieldValidationError("email domain has no mail servers", "email", "no_mx_records")
 	}
 	return nil
 }
@@ -121,23 +298,44 @@ func (s *SubscriptionService) createOrUpdateSubscription(existing *models.Subscr
 		}
 	}()
 
+	// Store the normalized language so an omitted value reads back as "en"
+	language, _ := models.ParseLanguage(req.Lang)
+
 	var subscription *models.Subscription
 	if existing != nil {
 		subscription = existing
 		subscription.Frequency = req.Frequency
+		subscription.SendHour = req.SendHour
+		subscription.Timezone = req.Timezone
+		subscription.AlertTempBelow = req.AlertTempBelow
+		subscription.AlertTempAbove = req.AlertTempAbove
+		subscription.AlertCondition = req.AlertCondition
+		subscription.Language = language.String()
 		if err := tx.Save(subscription).Error; err != nil {
 			tx.Rollback()
 			return nil, errors.NewDatabaseError("update subscription", err)
 		}
 	} else {
 		subscription = &models.Subscription{
-			Email:     req.Email,
-			City:      req.City,
-			Frequency: req.Frequency,
-			Confirmed: false,
+			Email:          req.Email,
+			City:           req.City,
+			Frequency:      req.Frequency,
+			Confirmed:      false,
+			SendHour:       req.SendHour,
+			Timezone:       req.Timezone,
+			AlertTempBelow: req.AlertTempBelow,
+			AlertTempAbove: req.AlertTempAbove,
+			AlertCondition: req.AlertCondition,
+			Language:       language.String(),
 		}
 		if err := tx.Create(subscription).Error; err != nil {
 			tx.Rollback()
+			// A concurrent subscribe can win the race between the
+			// FindByEmail check and this insert; the unique index on
+			// (email, city) catches it here
+			if repository.IsUniqueViolation(err) {
+				return nil, errors.NewAlreadyExistsError("email already subscribed")
+			}
 			return nil, errors.NewDatabaseError("create subscription", err)
 		}
 	}
@@ -146,11 +344,18 @@ func (s *SubscriptionService) createOrUpdateSubscription(existing *models.Subscr
 		return nil, errors.NewDatabaseError("commit transaction", err)
 	}
 
+	// Count distinct new signups separately from re-subscribe updates
+	if existing != nil {
+		metrics.Subscriptions().RecordUpdated()
+	} else {
+		metrics.Subscriptions().RecordCreated()
+	}
+
 	return subscription, nil
 }
 
 func (s *SubscriptionService) sendConfirmationEmail(subscription *models.Subscription) error {
-	token, err := s.tokenRepo.CreateToken(subscription.ID, "confirmation", 24*time.Hour)
+	token, err := s.tokenRepo.CreateToken(subscription.ID, "confirmation", s.config.ConfirmationTokenTTL())
 	if err != nil {
 		return errors.NewDatabaseError("create confirmation token", err)
 	}
@@ -161,6 +366,15 @@ func (s *SubscriptionService) sendConfirmationEmail(subscription *models.Subscri
 		Email:      subscription.Email,
 		ConfirmURL: confirmURL,
 		City:       subscription.City,
+		Lang:       subscription.Language,
+	}
+
+	if s.config.Email.IncludeCancelLink {
+		cancelToken, err := s.tokenRepo.CreateToken(subscription.ID, "cancel", s.config.ConfirmationTokenTTL())
+		if err != nil {
+			return errors.NewDatabaseError("create cancel token", err)
+		}
+		params.CancelURL = fmt.Sprintf("%s/api/cancel/%s", s.config.AppBaseURL, cancelToken.Token)
 	}
 
 	if err := s.emailService.SendConfirmationEmailWithParams(params); err != nil {
@@ -195,6 +409,29 @@ func (s *SubscriptionService) ConfirmSubscription(tokenStr string) error {
 	return s.processConfirmation(subscription, token)
 }
 
+// lockForUpdate applies row-level locking on dialects that support it.
+// SQLite (used in tests) has no FOR UPDATE; its writes are serialized anyway.
+func lockForUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "sqlite" {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// lockSubscription re-reads the subscription under a row lock so a concurrent
+// confirm/unsubscribe cannot modify or delete it mid-transaction
+func (s *SubscriptionService) lockSubscription(tx *gorm.DB, id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	result := lockForUpdate(tx).First(&subscription, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("subscription not found")
+		}
+		return nil, errors.NewDatabaseError("lock subscription", result.Error)
+	}
+	return &subscription, nil
+}
+
 func (s *SubscriptionService) processConfirmation(subscription *models.Subscription, token *models.Token) error {
 	tx := s.db.Begin()
 	if tx.Error != nil {
@@ -206,6 +443,12 @@ func (s *SubscriptionService) processConfirmation(subscription *models.Subscript
 		}
 	}()
 
+	subscription, err := s.lockSubscription(tx, subscription.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	subscription.Confirmed = true
 	if err := tx.Save(subscription).Error; err != nil {
 		tx.Rollback()
@@ -227,6 +470,8 @@ func (s *SubscriptionService) processConfirmation(subscription *models.Subscript
 		return errors.NewDatabaseError("commit transaction", err)
 	}
 
+	metrics.Subscriptions().RecordConfirmed()
+
 	unsubscribeURL := fmt.Sprintf("%s/api/unsubscribe/%s", s.config.AppBaseURL, unsubscribeToken.Token)
 
 	// Try to send welcome email but don't fail if it doesn't work
@@ -235,6 +480,7 @@ func (s *SubscriptionService) processConfirmation(subscription *models.Subscript
 		City:           subscription.City,
 		Frequency:      subscription.Frequency,
 		UnsubscribeURL: unsubscribeURL,
+		Lang:           subscription.Language,
 	}
 
 	if err := s.emailService.SendWelcomeEmailWithParams(params); err != nil {
@@ -280,6 +526,12 @@ func (s *SubscriptionService) processUnsubscription(subscription *models.Subscri
 		}
 	}()
 
+	subscription, err := s.lockSubscription(tx, subscription.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Delete(subscription).Error; err != nil {
 		tx.Rollback()
 		return errors.NewDatabaseError("delete subscription", err)
@@ -298,6 +550,7 @@ func (s *SubscriptionService) processUnsubscription(subscription *models.Subscri
 	params := UnsubscribeEmailParams{
 		Email: subscription.Email,
 		City:  subscription.City,
+		Lang:  subscription.Language,
 	}
 
 	if err := s.emailService.SendUnsubscribeConfirmationEmailWithParams(params); err != nil {
@@ -307,64 +560,590 @@ func (s *SubscriptionService) processUnsubscription(subscription *models.Subscri
 	return nil
 }
 
-// SendWeatherUpdate sends weather updates to all subscribers of the specified frequency
-func (s *SubscriptionService) SendWeatherUpdate(frequency string) error {
+// RequestUnsubscribeAll emails a single confirmation link that removes every
+// subscription for the address. Whether the email has any subscriptions is
+// never revealed to the caller: an address with none simply gets no email
+func (s *SubscriptionService) RequestUnsubscribeAll(email string) error {
+	slog.Debug("Processing unsubscribe-all request", "email", email)
+
+	if email == "" {
+		return errors.NewValidationError("email is required")
+	}
+
+	subscriptions, err := s.subscriptionRepo.FindAllByEmail(email)
+	if err != nil {
+		return errors.NewDatabaseError("find subscriptions by email", err)
+	}
+
+	// A mixed-language address gets the first confirmed subscription's
+	// language for the single confirmation email
+	confirmed := 0
+	lang := ""
+	for _, subscription := range subscriptions {
+		if subscription.Confirmed {
+			confirmed++
+			if lang == "" {
+				lang = subscription.Language
+			}
+		}
+	}
+	if confirmed == 0 {
+		slog.Debug("No confirmed subscriptions for unsubscribe-all request", "email", email)
+		return nil
+	}
+
+	token, err := s.tokenRepo.CreateEmailToken(email, "unsubscribe_all", 24*time.Hour)
+	if err != nil {
+		return errors.NewDatabaseError("create unsubscribe-all token", err)
+	}
+
+	params := UnsubscribeAllEmailParams{
+		Email:             email,
+		ConfirmURL:        fmt.Sprintf("%s/api/unsubscribe-all/%s", s.config.AppBaseURL, token.Token),
+		SubscriptionCount: confirmed,
+		Lang:              lang,
+	}
+
+	return s.emailService.SendUnsubscribeAllEmailWithParams(params)
+}
+
+// UnsubscribeAll deletes every subscription for the email an unsubscribe-all
+// token is scoped to
+func (s *SubscriptionService) UnsubscribeAll(tokenStr string) error {
+	slog.Debug("Processing unsubscribe-all", "token", tokenStr)
+
+	if tokenStr == "" {
+		return errors.NewValidationError("token cannot be empty")
+	}
+
+	token, err := s.tokenRepo.FindByToken(tokenStr)
+	if err != nil {
+		return errors.NewTokenError("token not found or expired")
+	}
+
+	if token.Type != "unsubscribe_all" || token.Email == "" {
+		return errors.NewTokenError("invalid token type")
+	}
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return errors.NewDatabaseError("begin transaction", tx.Error)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("email = ?", token.Email).Delete(&models.Subscription{}).Error; err != nil {
+		tx.Rollback()
+		return errors.NewDatabaseError("delete subscriptions", err)
+	}
+
+	if err := tx.Delete(token).Error; err != nil {
+		tx.Rollback()
+		return errors.NewDatabaseError("delete token", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.NewDatabaseError("commit transaction", err)
+	}
+
+	slog.Info("Removed all subscriptions for email", "email", token.Email)
+	return nil
+}
+
+// CancelSubscription deletes a pending subscription using a cancel token
+func (s *SubscriptionService) CancelSubscription(tokenStr string) error {
+	slog.Debug("Cancelling pending subscription", "token", tokenStr)
+
+	if tokenStr == "" {
+		return errors.NewValidationError("token cannot be empty")
+	}
+
+	token, err := s.tokenRepo.FindByToken(tokenStr)
+	if err != nil {
+		return errors.NewTokenError("token not found or expired")
+	}
+
+	if token.Type != "cancel" {
+		return errors.NewTokenError("invalid token type")
+	}
+
+	subscription, err := s.subscriptionRepo.FindByID(token.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if subscription.Confirmed {
+		return errors.NewTokenError("subscription already confirmed")
+	}
+
+	return s.processUnsubscription(subscription, token)
+}
+
+// WeatherUpdateResult summarizes a bulk weather update run so callers can
+// surface partial failures instead of treating the run as all-or-nothing
+type WeatherUpdateResult struct {
+	Sent   int
+	Failed int
+	Errors []error
+}
+
+// WeatherRunStats describes the most recent bulk send run for the admin
+// stats endpoint
+type WeatherRunStats struct {
+	Frequency   string    `json:"frequency"`
+	Sent        int       `json:"sent"`
+	Failed      int       `json:"failed"`
+	CompletedAt time.Time `json:"completed_at"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+// recordWeatherRun remembers the outcome of a bulk send run so operators can
+// inspect it later
+func (s *SubscriptionService) recordWeatherRun(frequency string, result *WeatherUpdateResult, start time.Time) {
+	s.lastRunMu.Lock()
+	defer s.lastRunMu.Unlock()
+	s.lastRun = &WeatherRunStats{
+		Frequency:   frequency,
+		Sent:        result.Sent,
+		Failed:      result.Failed,
+		CompletedAt: time.Now(),
+		DurationMS:  time.Since(start).Milliseconds(),
+	}
+}
+
+// LastWeatherRun reports the most recent bulk send run, or false when no run
+// has completed yet
+func (s *SubscriptionService) LastWeatherRun() (WeatherRunStats, bool) {
+	s.lastRunMu.Lock()
+	defer s.lastRunMu.Unlock()
+	if s.lastRun == nil {
+		return WeatherRunStats{}, false
+	}
+	return *s.lastRun, true
+}
+
+// markNotified stamps the subscriber's last notified time; failing to record
+// it never fails the send that already went out
+func (s *SubscriptionService) markNotified(subscription models.Subscription) {
+	if err := s.subscriptionRepo.TouchLastNotified(subscription.ID, time.Now()); err != nil {
+		slog.Warn("record last notified time", "error", err, "email", subscription.Email)
+	}
+}
+
+// SendWeatherUpdate sends weather updates to all subscribers of the specified
+// frequency; per-subscriber failures are collected in the result rather than
+// aborting the run
+func (s *SubscriptionService) SendWeatherUpdate(frequency string) (*WeatherUpdateResult, error) {
 	slog.Debug("Sending weather updates", "frequency", frequency)
 
-	if frequency != "hourly" && frequency != "daily" {
-		return errors.NewValidationError("frequency must be either 'hourly' or 'daily'")
+	start := time.Now()
+
+	// Alert subscriptions are notified by SendWeatherAlerts, not digests
+	if freq, ok := models.FrequencyFromString(frequency); !ok || freq == models.FrequencyAlert {
+		return nil, errors.NewValidationError("frequency must be 'hourly', 'daily', or 'weekly'")
 	}
 
 	subscriptions, err := s.subscriptionRepo.GetSubscriptionsForUpdates(frequency)
 	if err != nil {
-		return errors.NewDatabaseError("get subscriptions for updates", err)
+		return nil, errors.NewDatabaseError("get subscriptions for updates", err)
 	}
 
 	slog.Debug("Found subscriptions for updates", "count", len(subscriptions), "frequency", frequency)
 
+	windowStart := s.updateWindowStart(frequency, time.Now())
+	s.recordNotificationRun(frequency, windowStart)
+
+	if s.config.Email.AggregateDigest {
+		result := s.sendWeatherDigests(subscriptions, windowStart)
+		s.recordWeatherRun(frequency, result, start)
+		return result, nil
+	}
+
+	if s.emailQueue != nil {
+		result := s.sendWeatherUpdatesQueued(subscriptions, windowStart)
+		s.recordWeatherRun(frequency, result, start)
+		return result, nil
+	}
+
+	// Spreading sends out keeps a large batch from hammering the weather
+	// providers and SMTP server all at once
+	stagger := time.Duration(s.config.Scheduler.BatchStaggerMS) * time.Millisecond
+
+	result := &WeatherUpdateResult{}
 	for _, subscription := range subscriptions {
+		if !subscriberDue(subscription, time.Now()) {
+			slog.Debug("Skipping subscriber outside preferred send hour",
+				"email", subscription.Email, "sendHour", *subscription.SendHour, "timezone", subscription.Timezone)
+			continue
+		}
+		if alreadyNotifiedInWindow(subscription, windowStart) {
+			slog.Debug("Skipping subscriber already notified in this window",
+				"email", subscription.Email, "city", subscription.City, "windowStart", windowStart)
+			continue
+		}
+		if stagger > 0 && result.Sent+result.Failed > 0 {
+			time.Sleep(stagger)
+		}
 		if err := s.sendWeatherUpdateToSubscriber(subscription); err != nil {
 			slog.Warn("send weather update", "error", err, "email", subscription.Email, "city", subscription.City)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", subscription.Email, subscription.City, err))
 			continue
 		}
+		s.markNotified(subscription)
+		result.Sent++
 	}
 
-	return nil
+	s.recordWeatherRun(frequency, result, start)
+	return result, nil
+}
+
+// DryRunWeatherUpdate walks the same selection and preparation pipeline as
+// SendWeatherUpdate — due-hour filtering, weather fetch, unsubscribe link —
+// but logs a "would send" entry instead of delivering anything, so recipient
+// targeting can be verified before a new frequency goes live. The weather
+// fetch still runs for real to surface provider issues
+func (s *SubscriptionService) DryRunWeatherUpdate(frequency string) (*WeatherUpdateResult, error) {
+	slog.Debug("Dry-running weather updates", "frequency", frequency)
+
+	if freq, ok := models.FrequencyFromString(frequency); !ok || freq == models.FrequencyAlert {
+		return nil, errors.NewValidationError("frequency must be 'hourly', 'daily', or 'weekly'")
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetSubscriptionsForUpdates(frequency)
+	if err != nil {
+		return nil, errors.NewDatabaseError("get subscriptions for updates", err)
+	}
+
+	result := &WeatherUpdateResult{}
+	for _, subscription := range subscriptions {
+		if !subscriberDue(subscription, time.Now()) {
+			slog.Debug("Skipping subscriber outside preferred send hour",
+				"email", subscription.Email, "sendHour", *subscription.SendHour, "timezone", subscription.Timezone)
+			continue
+		}
+		params, err := s.prepareWeatherUpdate(subscription)
+		if err != nil {
+			slog.Warn("dry-run weather update", "error", err, "email", subscription.Email, "city", subscription.City)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", subscription.Email, subscription.City, err))
+			continue
+		}
+		slog.Info("Dry run: would send weather update",
+			"email", params.Email, "city", params.City, "frequency", frequency,
+			"temperature", params.Weather.Temperature, "description", params.Weather.Description)
+		result.Sent++
+	}
+
+	slog.Info("Dry run complete", "frequency", frequency, "wouldSend", result.Sent, "failed", result.Failed)
+	return result, nil
+}
+
+// queuedUpdate pairs a subscriber with the pending outcome of their email
+type queuedUpdate struct {
+	subscription models.Subscription
+	result       <-chan error
+}
+
+// sendWeatherUpdatesQueued prepares each due subscriber's email in the run
+// goroutine (weather lookups, token handling) but hands the sends to the
+// email queue's workers, then waits for all outcomes
+func (s *SubscriptionService) sendWeatherUpdatesQueued(subscriptions []models.Subscription, windowStart time.Time) *WeatherUpdateResult {
+	result := &WeatherUpdateResult{}
+
+	var pending []queuedUpdate
+	for _, subscription := range subscriptions {
+		if !subscriberDue(subscription, time.Now()) {
+			slog.Debug("Skipping subscriber outside preferred send hour",
+				"email", subscription.Email, "sendHour", *subscription.SendHour, "timezone", subscription.Timezone)
+			continue
+		}
+		if alreadyNotifiedInWindow(subscription, windowStart) {
+			slog.Debug("Skipping subscriber already notified in this window",
+				"email", subscription.Email, "city", subscription.City, "windowStart", windowStart)
+			continue
+		}
+
+		params, err := s.prepareWeatherUpdate(subscription)
+		if err != nil {
+			slog.Warn("send weather update", "error", err, "email", subscription.Email, "city", subscription.City)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", subscription.Email, subscription.City, err))
+			continue
+		}
+
+		pending = append(pending, queuedUpdate{subscription: subscription, result: s.emailQueue.Enqueue(*params)})
+	}
+
+	for _, update := range pending {
+		if err := <-update.result; err != nil {
+			slog.Warn("send weather update", "error", err, "email", update.subscription.Email, "city", update.subscription.City)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", update.subscription.Email, update.subscription.City, err))
+			continue
+		}
+		s.markNotified(update.subscription)
+		result.Sent++
+	}
+
+	return result
+}
+
+// sendWeatherDigests groups due subscribers by email address and sends each
+// one a single digest covering all their cities instead of one email per
+// city. Cities whose weather fetch fails are left out of the digest and
+// counted as failures; digests always send synchronously since the email
+// queue is typed for per-city updates
+func (s *SubscriptionService) sendWeatherDigests(subscriptions []models.Subscription, windowStart time.Time) *WeatherUpdateResult {
+	result := &WeatherUpdateResult{}
+
+	// Group by address, preserving first-seen order so runs are deterministic
+	grouped := make(map[string][]models.Subscription)
+	var order []string
+	for _, subscription := range subscriptions {
+		if !subscriberDue(subscription, time.Now()) {
+			slog.Debug("Skipping subscriber outside preferred send hour",
+				"email", subscription.Email, "sendHour", *subscription.SendHour, "timezone", subscription.Timezone)
+			continue
+		}
+		if alreadyNotifiedInWindow(subscription, windowStart) {
+			slog.Debug("Skipping subscriber already notified in this window",
+				"email", subscription.Email, "city", subscription.City, "windowStart", windowStart)
+			continue
+		}
+		if _, ok := grouped[subscription.Email]; !ok {
+			order = append(order, subscription.Email)
+		}
+		grouped[subscription.Email] = append(grouped[subscription.Email], subscription)
+	}
+
+	for _, email := range order {
+		group := grouped[email]
+
+		digest := WeatherDigestEmailParams{Email: email, Lang: group[0].Language}
+		var covered []models.Subscription
+		for _, subscription := range group {
+			params, err := s.prepareWeatherUpdate(subscription)
+			if err != nil {
+				slog.Warn("prepare digest entry", "error", err, "email", subscription.Email, "city", subscription.City)
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", subscription.Email, subscription.City, err))
+				continue
+			}
+			digest.Cities = append(digest.Cities, WeatherDigestCity{
+				City:           params.City,
+				Weather:        params.Weather,
+				UnsubscribeURL: params.UnsubscribeURL,
+			})
+			covered = append(covered, subscription)
+		}
+
+		if len(digest.Cities) == 0 {
+			continue
+		}
+
+		if err := s.emailService.SendWeatherDigestEmailWithParams(digest); err != nil {
+			slog.Warn("send weather digest", "error", err, "email", email, "cities", len(digest.Cities))
+			result.Failed += len(covered)
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", email, err))
+			continue
+		}
+		for _, subscription := range covered {
+			s.markNotified(subscription)
+			result.Sent++
+		}
+	}
+
+	return result
+}
+
+// updateWindowStart returns the start of the current send window for the
+// frequency, aligned to wall-clock multiples of its configured interval. A
+// zero time disables window tracking when no interval is configured
+func (s *SubscriptionService) updateWindowStart(frequency string, now time.Time) time.Time {
+	var minutes int
+	switch frequency {
+	case models.FrequencyHourly.String():
+		minutes = s.config.Scheduler.HourlyInterval
+	case models.FrequencyDaily.String():
+		minutes = s.config.Scheduler.DailyInterval
+	case models.FrequencyWeekly.String():
+		minutes = s.config.Scheduler.WeeklyInterval
+	}
+	if minutes <= 0 {
+		return time.Time{}
+	}
+	return now.Truncate(time.Duration(minutes) * time.Minute)
+}
+
+// recordNotificationRun marks the window as started. Failing to record it
+// never blocks the run, and a pre-existing record just means a restarted
+// scheduler is resuming a window already in progress
+func (s *SubscriptionService) recordNotificationRun(frequency string, windowStart time.Time) {
+	if s.notificationRuns == nil || windowStart.IsZero() {
+		return
+	}
+
+	started, err := s.notificationRuns.RecordRun(frequency, windowStart)
+	if err != nil {
+		slog.Warn("record notification run", "error", err, "frequency", frequency)
+		return
+	}
+	if !started {
+		slog.Info("Resuming weather update window already in progress",
+			"frequency", frequency, "windowStart", windowStart)
+	}
+}
+
+// alreadyNotifiedInWindow reports whether the subscriber was emailed during
+// the current send window, as happens when a restarted scheduler replays a
+// partially completed run
+func alreadyNotifiedInWindow(subscription models.Subscription, windowStart time.Time) bool {
+	if windowStart.IsZero() || subscription.LastNotifiedAt == nil {
+		return false
+	}
+	return !subscription.LastNotifiedAt.Before(windowStart)
+}
+
+// subscriberDue reports whether a subscriber should receive an update now,
+// honoring an optional preferred send hour in the subscriber's timezone;
+// subscribers without a preferred hour are always due
+func subscriberDue(subscription models.Subscription, now time.Time) bool {
+	if subscription.SendHour == nil {
+		return true
+	}
+
+	location := time.Local
+	if subscription.Timezone != "" {
+		if loc, err := time.LoadLocation(subscription.Timezone); err == nil {
+			location = loc
+		}
+	}
+
+	return now.In(location).Hour() == *subscription.SendHour
 }
 
 func (s *SubscriptionService) sendWeatherUpdateToSubscriber(subscription models.Subscription) error {
+	params, err := s.prepareWeatherUpdate(subscription)
+	if err != nil {
+		return err
+	}
+	return s.emailService.SendWeatherUpdateEmailWithParams(*params)
+}
+
+// prepareWeatherUpdate assembles the email parameters for one subscriber:
+// the current weather and an unsubscribe link
+func (s *SubscriptionService) prepareWeatherUpdate(subscription models.Subscription) (*WeatherUpdateEmailParams, error) {
 	slog.Debug("Sending weather update to subscriber", "email", subscription.Email, "city", subscription.City)
 
-	weather, err := s.weatherService.GetWeather(subscription.City)
+	// Bulk update runs are not tied to an inbound request
+	weather, err := s.weatherService.GetWeather(context.Background(), subscription.City)
 	if err != nil {
 		slog.Error("get weather", "error", err, "city", subscription.City)
-		return fmt.Errorf("get weather for %s: %w", subscription.City, err)
+		return nil, fmt.Errorf("get weather for %s: %w", subscription.City, err)
 	}
 	slog.Debug("Retrieved weather data", "weather", weather, "city", subscription.City)
 
-	// Try to find existing unsubscribe token
+	unsubscribeURL, err := s.unsubscribeURLFor(subscription)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Sending weather update email", "email", subscription.Email, "unsubscribeURL", unsubscribeURL)
+
+	return &WeatherUpdateEmailParams{
+		Email:          subscription.Email,
+		City:           subscription.City,
+		Weather:        weather,
+		UnsubscribeURL: unsubscribeURL,
+		Lang:           subscription.Language,
+	}, nil
+}
+
+// unsubscribeURLFor finds the subscription's long-lived unsubscribe token,
+// creating one when none exists, and builds its link
+func (s *SubscriptionService) unsubscribeURLFor(subscription models.Subscription) (string, error) {
 	token, err := s.tokenRepo.FindBySubscriptionIDAndType(subscription.ID, "unsubscribe")
 	if err != nil {
 		slog.Debug("No existing unsubscribe token found, creating new one", "subscriptionID", subscription.ID)
-		// If no existing token found, create a new one
 		token, err = s.tokenRepo.CreateToken(subscription.ID, "unsubscribe", 365*24*time.Hour)
 		if err != nil {
 			slog.Error("create unsubscribe token", "error", err, "subscriptionID", subscription.ID)
-			return fmt.Errorf("create unsubscribe token: %w", err)
+			return "", fmt.Errorf("create unsubscribe token: %w", err)
 		}
 	} else {
 		slog.Debug("Found existing unsubscribe token", "token", token.Token)
 	}
 
-	unsubscribeURL := fmt.Sprintf("%s/api/unsubscribe/%s", s.config.AppBaseURL, token.Token)
-	slog.Debug("Sending weather update email", "email", subscription.Email, "unsubscribeURL", unsubscribeURL)
+	return fmt.Sprintf("%s/api/unsubscribe/%s", s.config.AppBaseURL, token.Token), nil
+}
+
+// SendWeatherAlerts evaluates every confirmed alert subscription against
+// current weather and emails only the subscribers whose thresholds are
+// crossed; per-subscriber failures are collected like the periodic run
+func (s *SubscriptionService) SendWeatherAlerts() (*WeatherUpdateResult, error) {
+	slog.Debug("Running weather alert checks")
+
+	start := time.Now()
+
+	cities, err := s.subscriptionRepo.AlertCities()
+	if err != nil {
+		return nil, errors.NewDatabaseError("list alert cities", err)
+	}
+
+	result := &WeatherUpdateResult{}
+	for _, city := range cities {
+		// Alert runs are not tied to an inbound request
+		weather, err := s.weatherService.GetWeather(context.Background(), city)
+		if err != nil {
+			slog.Error("get weather for alerts", "error", err, "city", city)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("get weather for %s: %w", city, err))
+			continue
+		}
+
+		subscriptions, err := s.subscriptionRepo.GetAlertSubscriptionsForCity(city)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("get alert subscribers for %s: %w", city, err))
+			continue
+		}
+
+		for _, subscription := range subscriptions {
+			reason, triggered := subscription.AlertReason(weather)
+			if !triggered {
+				continue
+			}
+
+			if err := s.sendWeatherAlert(subscription, weather, reason); err != nil {
+				slog.Warn("send weather alert", "error", err, "email", subscription.Email, "city", city)
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Errorf("%s (%s): %w", subscription.Email, city, err))
+				continue
+			}
+			s.markNotified(subscription)
+			result.Sent++
+		}
+	}
+
+	s.recordWeatherRun(models.FrequencyAlert.String(), result, start)
+	return result, nil
+}
+
+func (s *SubscriptionService) sendWeatherAlert(subscription models.Subscription, weather *models.WeatherResponse, reason string) error {
+	unsubscribeURL, err := s.unsubscribeURLFor(subscription)
+	if err != nil {
+		return err
+	}
 
-	params := WeatherUpdateEmailParams{
+	return s.emailService.SendWeatherAlertEmailWithParams(WeatherAlertEmailParams{
 		Email:          subscription.Email,
 		City:           subscription.City,
+		Reason:         reason,
 		Weather:        weather,
 		UnsubscribeURL: unsubscribeURL,
-	}
-
-	return s.emailService.SendWeatherUpdateEmailWithParams(params)
+		Lang:           subscription.Language,
+	})
 }
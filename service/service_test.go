@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -24,7 +25,7 @@ type mockProviderManager struct {
 	mock.Mock
 }
 
-func (m *mockProviderManager) GetWeather(city string) (*models.WeatherResponse, error) {
+func (m *mockProviderManager) GetWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
 	args := m.Called(city)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
@@ -32,6 +33,14 @@ func (m *mockProviderManager) GetWeather(city string) (*models.WeatherResponse,
 	return args.Get(0).(*models.WeatherResponse), nil
 }
 
+func (m *mockProviderManager) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	args := m.Called(city, days)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResponse), nil
+}
+
 // Ensure mock implements the interface
 var _ WeatherProviderManagerInterface = (*mockProviderManager)(nil)
 
@@ -58,7 +67,7 @@ func TestWeatherService_GetWeather_WithProviderManager(t *testing.T) {
 
 	mockManager.On("GetWeather", "London").Return(expectedWeather, nil)
 
-	weather, err := weatherService.GetWeather("London")
+	weather, err := weatherService.GetWeather(context.Background(), "London")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedWeather, weather)
@@ -69,7 +78,7 @@ func TestWeatherService_GetWeather_EmptyCity(t *testing.T) {
 	mockManager := new(mockProviderManager)
 	weatherService := NewWeatherService(mockManager)
 
-	weather, err := weatherService.GetWeather("")
+	weather, err := weatherService.GetWeather(context.Background(), "")
 
 	assert.Error(t, err)
 	assert.Nil(t, weather)
@@ -79,19 +88,124 @@ func TestWeatherService_GetWeather_EmptyCity(t *testing.T) {
 	assert.Equal(t, weathererr.ValidationError, appErr.Type)
 }
 
+func TestWeatherService_GetWeather_CityNameValidation(t *testing.T) {
+	t.Run("AcceptsLegitimateNames", func(t *testing.T) {
+		mockManager := new(mockProviderManager)
+		weatherService := NewWeatherService(mockManager)
+
+		weather := &models.WeatherResponse{Temperature: 15.0, Humidity: 76.0, Description: "Sunny"}
+		for _, city := range []string{"São Paulo", "Zürich", "Київ", "Winston-Salem", "St. John's"} {
+			mockManager.On("GetWeather", city).Return(weather, nil)
+
+			_, err := weatherService.GetWeather(context.Background(), city)
+			assert.NoError(t, err, "city %q should pass validation", city)
+		}
+		mockManager.AssertExpectations(t)
+	})
+
+	t.Run("RejectsDisallowedCharacters", func(t *testing.T) {
+		mockManager := new(mockProviderManager)
+		weatherService := NewWeatherService(mockManager)
+
+		for _, city := range []string{"London; DROP TABLE subscriptions", "city_name", "London123", "<script>"} {
+			_, err := weatherService.GetWeather(context.Background(), city)
+
+			var appErr *weathererr.AppError
+			assert.True(t, errors.As(err, &appErr), "city %q should be rejected", city)
+			assert.Equal(t, weathererr.ValidationError, appErr.Type)
+		}
+		mockManager.AssertNotCalled(t, "GetWeather", mock.Anything)
+	})
+
+	t.Run("RejectsOversizedNames", func(t *testing.T) {
+		mockManager := new(mockProviderManager)
+		weatherService := NewWeatherService(mockManager).WithCityMaxLength(10)
+
+		_, err := weatherService.GetWeather(context.Background(), "Llanfairpwllgwyngyll")
+
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+		assert.Contains(t, appErr.Message, "at most 10 characters")
+	})
+}
+
+func TestSubscriptionService_Subscribe_RejectsInvalidCityName(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	service := NewSubscriptionService(db, nil, nil, nil, nil, &config.Config{})
+
+	req := &models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "London'); DELETE FROM subscriptions; --",
+		Frequency: "daily",
+	}
+
+	_, err = service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "city may only contain")
+}
+
 func TestWeatherService_GetWeather_ProviderError(t *testing.T) {
 	mockManager := new(mockProviderManager)
 	weatherService := NewWeatherService(mockManager)
 
 	mockManager.On("GetWeather", "InvalidCity").Return(nil, weathererr.NewNotFoundError("city not found"))
 
-	weather, err := weatherService.GetWeather("InvalidCity")
+	weather, err := weatherService.GetWeather(context.Background(), "InvalidCity")
 
 	assert.Error(t, err)
 	assert.Nil(t, weather)
 	mockManager.AssertExpectations(t)
 }
 
+// Mock observation store for testing history persistence
+type mockObservationStore struct {
+	mock.Mock
+}
+
+func (m *mockObservationStore) Save(city string, weather *models.WeatherResponse) error {
+	args := m.Called(city, weather)
+	return args.Error(0)
+}
+
+func TestWeatherService_GetWeather_StoresObservation(t *testing.T) {
+	mockManager := new(mockProviderManager)
+	mockStore := new(mockObservationStore)
+	weatherService := NewWeatherServiceWithObservations(mockManager, mockStore)
+
+	expectedWeather := &models.WeatherResponse{Temperature: 15.0, Description: "Partly cloudy"}
+	mockManager.On("GetWeather", "London").Return(expectedWeather, nil)
+	mockStore.On("Save", "London", expectedWeather).Return(nil)
+
+	weather, err := weatherService.GetWeather(context.Background(), "London")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedWeather, weather)
+	mockStore.AssertExpectations(t)
+}
+
+func TestWeatherService_GetWeather_StoreFailureDoesNotFailLookup(t *testing.T) {
+	mockManager := new(mockProviderManager)
+	mockStore := new(mockObservationStore)
+	weatherService := NewWeatherServiceWithObservations(mockManager, mockStore)
+
+	expectedWeather := &models.WeatherResponse{Temperature: 15.0}
+	mockManager.On("GetWeather", "London").Return(expectedWeather, nil)
+	mockStore.On("Save", "London", expectedWeather).Return(weathererr.NewDatabaseError("insert failed", nil))
+
+	weather, err := weatherService.GetWeather(context.Background(), "London")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedWeather, weather)
+}
+
 // Test EmailService with provider
 func TestEmailService_SendConfirmationEmailWithParams(t *testing.T) {
 	mockProvider := new(mockEmailProvider)
@@ -157,7 +271,7 @@ func TestWeatherAPIProvider_GetCurrentWeather(t *testing.T) {
 	}
 
 	provider := providers.NewWeatherAPIProvider(config)
-	weather, err := provider.GetCurrentWeather("London")
+	weather, err := provider.GetCurrentWeather(context.Background(), "London")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, weather)
@@ -178,7 +292,7 @@ func TestWeatherAPIProvider_GetCurrentWeather_NotFound(t *testing.T) {
 	}
 
 	provider := providers.NewWeatherAPIProvider(config)
-	weather, err := provider.GetCurrentWeather("NonExistentCity")
+	weather, err := provider.GetCurrentWeather(context.Background(), "NonExistentCity")
 
 	assert.Error(t, err)
 	assert.Nil(t, weather)
@@ -201,6 +315,19 @@ func (m *mockSubscriptionRepository) FindByEmail(email, city string) (*models.Su
 	return args.Get(0).(*models.Subscription), nil
 }
 
+func (m *mockSubscriptionRepository) FindAllByEmail(email string) ([]models.Subscription, error) {
+	args := m.Called(email)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Subscription), nil
+}
+
+func (m *mockSubscriptionRepository) CountByEmail(email string) (int64, error) {
+	args := m.Called(email)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *mockSubscriptionRepository) FindByID(id uint) (*models.Subscription, error) {
 	args := m.Called(id)
 	if args.Error(1) != nil {
@@ -233,6 +360,27 @@ func (m *mockSubscriptionRepository) GetSubscriptionsForUpdates(frequency string
 	return args.Get(0).([]models.Subscription), nil
 }
 
+func (m *mockSubscriptionRepository) TouchLastNotified(id uint, _ time.Time) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockSubscriptionRepository) AlertCities() ([]string, error) {
+	args := m.Called()
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), nil
+}
+
+func (m *mockSubscriptionRepository) GetAlertSubscriptionsForCity(city string) ([]models.Subscription, error) {
+	args := m.Called(city)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Subscription), nil
+}
+
 type mockTokenRepository struct {
 	mock.Mock
 }
@@ -245,6 +393,14 @@ func (m *mockTokenRepository) CreateToken(subscriptionID uint, tokenType string,
 	return args.Get(0).(*models.Token), nil
 }
 
+func (m *mockTokenRepository) CreateEmailToken(email, tokenType string, expiresIn time.Duration) (*models.Token, error) {
+	args := m.Called(email, tokenType, expiresIn)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Token), nil
+}
+
 func (m *mockTokenRepository) FindByToken(tokenStr string) (*models.Token, error) {
 	args := m.Called(tokenStr)
 	if args.Error(1) != nil {
@@ -266,9 +422,9 @@ func (m *mockTokenRepository) DeleteToken(token *models.Token) error {
 	return args.Error(0)
 }
 
-func (m *mockTokenRepository) DeleteExpiredTokens() error {
+func (m *mockTokenRepository) DeleteExpiredTokens() (int64, error) {
 	args := m.Called()
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 type mockEmailService struct {
@@ -290,16 +446,31 @@ func (m *mockEmailService) SendUnsubscribeConfirmationEmailWithParams(params Uns
 	return args.Error(0)
 }
 
+func (m *mockEmailService) SendUnsubscribeAllEmailWithParams(params UnsubscribeAllEmailParams) error {
+	args := m.Called(params)
+	return args.Error(0)
+}
+
 func (m *mockEmailService) SendWeatherUpdateEmailWithParams(params WeatherUpdateEmailParams) error {
 	args := m.Called(params)
 	return args.Error(0)
 }
 
+func (m *mockEmailService) SendWeatherDigestEmailWithParams(params WeatherDigestEmailParams) error {
+	args := m.Called(params)
+	return args.Error(0)
+}
+
+func (m *mockEmailService) SendWeatherAlertEmailWithParams(params WeatherAlertEmailParams) error {
+	args := m.Called(params)
+	return args.Error(0)
+}
+
 type mockWeatherService struct {
 	mock.Mock
 }
 
-func (m *mockWeatherService) GetWeather(city string) (*models.WeatherResponse, error) {
+func (m *mockWeatherService) GetWeather(_ context.Context, city string) (*models.WeatherResponse, error) {
 	args := m.Called(city)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
@@ -307,6 +478,14 @@ func (m *mockWeatherService) GetWeather(city string) (*models.WeatherResponse, e
 	return args.Get(0).(*models.WeatherResponse), nil
 }
 
+func (m *mockWeatherService) GetForecast(city string, days int) (*models.ForecastResponse, error) {
+	args := m.Called(city, days)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResponse), nil
+}
+
 // Test SubscriptionService with improved architecture
 func TestSubscriptionService_Subscribe_Success(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -349,11 +528,14 @@ func TestSubscriptionService_Subscribe_Success(t *testing.T) {
 		Email:      "test@example.com",
 		ConfirmURL: "http://localhost:8080/api/confirm/test-token",
 		City:       "London",
+		Lang:       "en", // omitted lang is normalized to English
 	}).Return(nil)
 
-	err = service.Subscribe(req)
+	subscription, err := service.Subscribe(req)
 
 	assert.NoError(t, err)
+	assert.NotNil(t, subscription)
+	assert.False(t, subscription.Confirmed)
 	mockSubRepo.AssertExpectations(t)
 	mockTokenRepo.AssertExpectations(t)
 	mockEmailService.AssertExpectations(t)
@@ -371,7 +553,7 @@ func TestSubscriptionService_Subscribe_ValidationError(t *testing.T) {
 		Frequency: "daily",
 	}
 
-	err = service.Subscribe(req)
+	_, err = service.Subscribe(req)
 
 	assert.Error(t, err)
 
@@ -404,7 +586,7 @@ func TestSubscriptionService_Subscribe_AlreadyExists(t *testing.T) {
 
 	mockSubRepo.On("FindByEmail", "existing@example.com", "London").Return(existingSub, nil)
 
-	err = service.Subscribe(req)
+	_, err = service.Subscribe(req)
 
 	assert.Error(t, err)
 
@@ -414,6 +596,55 @@ func TestSubscriptionService_Subscribe_AlreadyExists(t *testing.T) {
 	mockSubRepo.AssertExpectations(t)
 }
 
+// TestSubscriptionService_Subscribe_LimitReached verifies the per-email cap:
+// a new city beyond the limit is rejected before anything is written
+func TestSubscriptionService_Subscribe_LimitReached(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080", MaxSubscriptionsPerEmail: 2}
+	service := NewSubscriptionService(nil, mockSubRepo, nil, nil, nil, cfg)
+
+	req := &models.SubscriptionRequest{
+		Email:     "collector@example.com",
+		City:      "Berlin",
+		Frequency: "daily",
+	}
+
+	mockSubRepo.On("FindByEmail", "collector@example.com", "Berlin").Return((*models.Subscription)(nil), nil)
+	mockSubRepo.On("CountByEmail", "collector@example.com").Return(int64(2), nil)
+
+	_, err := service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "subscription limit of 2 per email reached")
+	mockSubRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Subscribe_BlockedDomain(t *testing.T) {
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Email.BlockedDomains = []string{"mailinator.com"}
+	service := NewSubscriptionService(nil, new(mockSubscriptionRepository), nil, nil, nil, cfg)
+
+	req := &models.SubscriptionRequest{
+		Email:     "user@MAIL.Mailinator.com",
+		City:      "London",
+		Frequency: "daily",
+	}
+
+	_, err := service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "email domain is not allowed")
+}
+
 // Test ProviderManager Integration (Optional - demonstrates real usage)
 func TestProviderManager_Integration(t *testing.T) {
 	// This test demonstrates integration but won't make actual API calls
@@ -546,7 +777,7 @@ func TestProviderManager_ChainOfResponsibility_Complete(t *testing.T) {
 			weatherService := NewWeatherService(manager)
 
 			// Test the chain
-			weather, err := weatherService.GetWeather("London")
+			weather, err := weatherService.GetWeather(context.Background(), "London")
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -572,7 +803,7 @@ func TestProviderManager_ChainOfResponsibility_Complete(t *testing.T) {
 				// Test caching if enabled
 				if tt.config.CacheConfig != nil {
 					// Second call should be cached
-					weather2, err2 := weatherService.GetWeather("London")
+					weather2, err2 := weatherService.GetWeather(context.Background(), "London")
 					assert.NoError(t, err2)
 					assert.Equal(t, weather.Temperature, weather2.Temperature)
 				}
@@ -624,7 +855,7 @@ func TestProviderManager_Builder_Pattern(t *testing.T) {
 
 	// Test that it actually works
 	weatherService := NewWeatherService(manager)
-	weather, err := weatherService.GetWeather("London")
+	weather, err := weatherService.GetWeather(context.Background(), "London")
 	assert.NoError(t, err)
 	assert.NotNil(t, weather)
 	assert.Equal(t, 22.5, weather.Temperature)
@@ -684,3 +915,638 @@ func TestWeatherProviders_Individual(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionService_SendWeatherUpdate_PartialFailures(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "ok@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{ID: 2, Email: "broken@example.com", City: "Paris", Frequency: "daily", Confirmed: true},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "daily").Return(subscriptions, nil)
+	mockSubRepo.On("TouchLastNotified", uint(1)).Return(nil)
+	mockWeatherService.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	mockWeatherService.On("GetWeather", "Paris").Return((*models.WeatherResponse)(nil), weathererr.NewExternalAPIError("provider down", nil))
+	mockTokenRepo.On("FindBySubscriptionIDAndType", uint(1), "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil)
+
+	result, err := service.SendWeatherUpdate("daily")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "broken@example.com")
+	mockSubRepo.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+}
+
+func TestSubscriptionService_SendWeatherUpdate_InvalidFrequency(t *testing.T) {
+	service := NewSubscriptionService(nil, nil, nil, nil, nil, &config.Config{})
+
+	result, err := service.SendWeatherUpdate("monthly")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+}
+
+// TestSubscriptionService_DryRunWeatherUpdate verifies the dry run walks the
+// full pipeline — including the weather fetch — without delivering email or
+// stamping last-notified times; the mocks would panic on either call
+func TestSubscriptionService_DryRunWeatherUpdate(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "ok@example.com", City: "London", Frequency: "weekly", Confirmed: true},
+		{ID: 2, Email: "broken@example.com", City: "Paris", Frequency: "weekly", Confirmed: true},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "weekly").Return(subscriptions, nil)
+	mockWeatherService.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	mockWeatherService.On("GetWeather", "Paris").Return((*models.WeatherResponse)(nil), weathererr.NewExternalAPIError("provider down", nil))
+	mockTokenRepo.On("FindBySubscriptionIDAndType", uint(1), "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+
+	result, err := service.DryRunWeatherUpdate("weekly")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "broken@example.com")
+	mockSubRepo.AssertExpectations(t)
+	mockWeatherService.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+}
+
+func TestSubscriptionService_DryRunWeatherUpdate_InvalidFrequency(t *testing.T) {
+	service := NewSubscriptionService(nil, nil, nil, nil, nil, &config.Config{})
+
+	result, err := service.DryRunWeatherUpdate("alert")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+}
+
+func TestSubscriptionService_Subscribe_InvalidSendHour(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	service := NewSubscriptionService(db, nil, nil, nil, nil, &config.Config{})
+
+	sendHour := 24
+	req := &models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+		SendHour:  &sendHour,
+	}
+
+	_, err = service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "send_hour")
+}
+
+func TestSubscriptionService_Subscribe_InvalidTimezone(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	service := NewSubscriptionService(db, nil, nil, nil, nil, &config.Config{})
+
+	req := &models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+		Timezone:  "Not/AZone",
+	}
+
+	_, err = service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "timezone")
+}
+
+func TestSubscriberDue(t *testing.T) {
+	hour := func(h int) *int { return &h }
+	// 14:30 UTC is 16:30 in Kyiv (UTC+2 in winter)
+	now := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		subscription models.Subscription
+		expected     bool
+	}{
+		{
+			name:         "no preferred hour is always due",
+			subscription: models.Subscription{Email: "a@example.com"},
+			expected:     true,
+		},
+		{
+			name:         "matching hour in UTC",
+			subscription: models.Subscription{SendHour: hour(14), Timezone: "UTC"},
+			expected:     true,
+		},
+		{
+			name:         "non-matching hour in UTC",
+			subscription: models.Subscription{SendHour: hour(8), Timezone: "UTC"},
+			expected:     false,
+		},
+		{
+			name:         "matching hour in subscriber timezone",
+			subscription: models.Subscription{SendHour: hour(16), Timezone: "Europe/Kyiv"},
+			expected:     true,
+		},
+		{
+			name:         "unknown timezone falls back without panicking",
+			subscription: models.Subscription{SendHour: hour(14), Timezone: "Not/AZone"},
+			expected:     subscriberDue(models.Subscription{SendHour: hour(14)}, now),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, subscriberDue(tt.subscription, now))
+		})
+	}
+}
+
+func TestSubscriptionService_SendWeatherUpdate_SkipsOutsidePreferredHour(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockWeatherService := new(mockWeatherService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		nil,
+		nil,
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	// A preferred hour guaranteed not to match the current UTC hour
+	notNow := (time.Now().UTC().Hour() + 1) % 24
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "later@example.com", City: "London", Frequency: "daily", Confirmed: true, SendHour: &notNow, Timezone: "UTC"},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "daily").Return(subscriptions, nil)
+
+	result, err := service.SendWeatherUpdate("daily")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Sent)
+	assert.Equal(t, 0, result.Failed)
+	mockSubRepo.AssertExpectations(t)
+	mockWeatherService.AssertNotCalled(t, "GetWeather", mock.Anything)
+}
+
+// TestSubscriptionService_SendWeatherUpdate_RestartDoesNotDoubleSend
+// simulates a scheduler restart mid-window: the rerun sees one subscriber
+// already stamped inside the current send window and only emails the other
+func TestSubscriptionService_SendWeatherUpdate_RestartDoesNotDoubleSend(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Scheduler.HourlyInterval = 60
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		mockWeatherService,
+		cfg,
+	)
+
+	alreadySent := time.Now()
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "done@example.com", City: "London", Frequency: "hourly", Confirmed: true, LastNotifiedAt: &alreadySent},
+		{ID: 2, Email: "pending@example.com", City: "Paris", Frequency: "hourly", Confirmed: true},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "hourly").Return(subscriptions, nil)
+	mockSubRepo.On("TouchLastNotified", uint(2)).Return(nil)
+	mockWeatherService.On("GetWeather", "Paris").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	mockTokenRepo.On("FindBySubscriptionIDAndType", uint(2), "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+	mockEmailService.On("SendWeatherUpdateEmailWithParams", mock.Anything).Return(nil).Once()
+
+	result, err := service.SendWeatherUpdate("hourly")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 0, result.Failed)
+	mockSubRepo.AssertExpectations(t)
+	mockWeatherService.AssertNotCalled(t, "GetWeather", "London")
+	mockEmailService.AssertExpectations(t)
+}
+
+func TestSubscriptionService_SendWeatherUpdate_AggregatedDigest(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	cfg := &config.Config{AppBaseURL: "http://localhost:8080"}
+	cfg.Email.AggregateDigest = true
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		mockWeatherService,
+		cfg,
+	)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "multi@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{ID: 2, Email: "multi@example.com", City: "Paris", Frequency: "daily", Confirmed: true},
+		{ID: 3, Email: "single@example.com", City: "Kyiv", Frequency: "daily", Confirmed: true},
+	}
+
+	mockSubRepo.On("GetSubscriptionsForUpdates", "daily").Return(subscriptions, nil)
+	mockSubRepo.On("TouchLastNotified", uint(1)).Return(nil)
+	mockSubRepo.On("TouchLastNotified", uint(2)).Return(nil)
+	mockSubRepo.On("TouchLastNotified", uint(3)).Return(nil)
+	mockWeatherService.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: 15.0}, nil)
+	mockWeatherService.On("GetWeather", "Paris").Return(&models.WeatherResponse{Temperature: 18.0}, nil)
+	mockWeatherService.On("GetWeather", "Kyiv").Return(&models.WeatherResponse{Temperature: 21.0}, nil)
+	mockTokenRepo.On("FindBySubscriptionIDAndType", mock.Anything, "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+	mockEmailService.On("SendWeatherDigestEmailWithParams", mock.MatchedBy(func(params WeatherDigestEmailParams) bool {
+		return params.Email == "multi@example.com" && len(params.Cities) == 2
+	})).Return(nil).Once()
+	mockEmailService.On("SendWeatherDigestEmailWithParams", mock.MatchedBy(func(params WeatherDigestEmailParams) bool {
+		return params.Email == "single@example.com" && len(params.Cities) == 1
+	})).Return(nil).Once()
+
+	result, err := service.SendWeatherUpdate("daily")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Sent)
+	assert.Equal(t, 0, result.Failed)
+	mockSubRepo.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+	mockEmailService.AssertNotCalled(t, "SendWeatherUpdateEmailWithParams", mock.Anything)
+}
+
+func TestSubscriptionService_RequestUnsubscribeAll_SendsSingleLink(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		nil,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "test@example.com", City: "London", Confirmed: true},
+		{ID: 2, Email: "test@example.com", City: "Paris", Confirmed: true},
+		{ID: 3, Email: "test@example.com", City: "Kyiv", Confirmed: false},
+	}
+
+	mockSubRepo.On("FindAllByEmail", "test@example.com").Return(subscriptions, nil)
+	mockTokenRepo.On("CreateEmailToken", "test@example.com", "unsubscribe_all", 24*time.Hour).Return(&models.Token{
+		Token: "all-token",
+		Email: "test@example.com",
+		Type:  "unsubscribe_all",
+	}, nil)
+	mockEmailService.On("SendUnsubscribeAllEmailWithParams", UnsubscribeAllEmailParams{
+		Email:             "test@example.com",
+		ConfirmURL:        "http://localhost:8080/api/unsubscribe-all/all-token",
+		SubscriptionCount: 2,
+	}).Return(nil)
+
+	err := service.RequestUnsubscribeAll("test@example.com")
+
+	assert.NoError(t, err)
+	mockSubRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+}
+
+func TestSubscriptionService_RequestUnsubscribeAll_UnknownEmail(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+
+	service := NewSubscriptionService(nil, mockSubRepo, mockTokenRepo, mockEmailService, nil, &config.Config{})
+
+	mockSubRepo.On("FindAllByEmail", "unknown@example.com").Return([]models.Subscription{}, nil)
+
+	err := service.RequestUnsubscribeAll("unknown@example.com")
+
+	assert.NoError(t, err)
+	mockTokenRepo.AssertNotCalled(t, "CreateEmailToken", mock.Anything, mock.Anything, mock.Anything)
+	mockEmailService.AssertNotCalled(t, "SendUnsubscribeAllEmailWithParams", mock.Anything)
+}
+
+func TestSubscriptionService_UnsubscribeAll_DeletesEverySubscription(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Subscription{}, &models.Token{})
+	require.NoError(t, err)
+
+	subscriptions := []models.Subscription{
+		{Email: "test@example.com", City: "London", Frequency: "daily", Confirmed: true},
+		{Email: "test@example.com", City: "Paris", Frequency: "hourly", Confirmed: true},
+		{Email: "other@example.com", City: "London", Frequency: "daily", Confirmed: true},
+	}
+	for i := range subscriptions {
+		require.NoError(t, db.Create(&subscriptions[i]).Error)
+	}
+
+	token := &models.Token{Token: "all-token", Email: "test@example.com", Type: "unsubscribe_all", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, db.Create(token).Error)
+
+	mockTokenRepo := new(mockTokenRepository)
+	mockTokenRepo.On("FindByToken", "all-token").Return(token, nil)
+
+	service := NewSubscriptionService(db, nil, mockTokenRepo, nil, nil, &config.Config{})
+
+	err = service.UnsubscribeAll("all-token")
+	assert.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Subscription{}).Where("email = ?", "test@example.com").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	require.NoError(t, db.Model(&models.Subscription{}).Where("email = ?", "other@example.com").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_UnsubscribeAll_WrongTokenType(t *testing.T) {
+	mockTokenRepo := new(mockTokenRepository)
+	mockTokenRepo.On("FindByToken", "sub-token").Return(&models.Token{
+		Token:          "sub-token",
+		SubscriptionID: 1,
+		Type:           "unsubscribe",
+	}, nil)
+
+	service := NewSubscriptionService(nil, nil, mockTokenRepo, nil, nil, &config.Config{})
+
+	err := service.UnsubscribeAll("sub-token")
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.TokenError, appErr.Type)
+}
+
+func TestSubscriptionService_Subscribe_AlertWithoutThreshold(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	service := NewSubscriptionService(db, nil, nil, nil, nil, &config.Config{})
+
+	req := &models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "alert",
+	}
+
+	_, err = service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "threshold")
+}
+
+func TestSubscriptionService_SendWeatherAlerts(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+	mockWeatherService := new(mockWeatherService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	below := 0.0
+	above := 30.0
+	subscriptions := []models.Subscription{
+		{ID: 1, Email: "cold@example.com", City: "London", Frequency: "alert", Confirmed: true, AlertTempBelow: &below},
+		{ID: 2, Email: "hot@example.com", City: "London", Frequency: "alert", Confirmed: true, AlertTempAbove: &above},
+	}
+
+	mockSubRepo.On("AlertCities").Return([]string{"London"}, nil)
+	mockSubRepo.On("GetAlertSubscriptionsForCity", "London").Return(subscriptions, nil)
+	mockSubRepo.On("TouchLastNotified", uint(1)).Return(nil)
+	mockWeatherService.On("GetWeather", "London").Return(&models.WeatherResponse{Temperature: -3.0, Description: "Snow"}, nil)
+	mockTokenRepo.On("FindBySubscriptionIDAndType", uint(1), "unsubscribe").Return(&models.Token{Token: "unsub-token"}, nil)
+	mockEmailService.On("SendWeatherAlertEmailWithParams", mock.MatchedBy(func(params WeatherAlertEmailParams) bool {
+		return params.Email == "cold@example.com" && params.Reason != ""
+	})).Return(nil)
+
+	result, err := service.SendWeatherAlerts()
+
+	// Only the below-threshold subscriber crosses; the above-threshold one
+	// is skipped without an email
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 0, result.Failed)
+	mockSubRepo.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+}
+
+func TestSubscriptionService_SendWeatherAlerts_WeatherFetchFails(t *testing.T) {
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockWeatherService := new(mockWeatherService)
+
+	service := NewSubscriptionService(
+		nil,
+		mockSubRepo,
+		new(mockTokenRepository),
+		new(mockEmailService),
+		mockWeatherService,
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	mockSubRepo.On("AlertCities").Return([]string{"Paris"}, nil)
+	mockWeatherService.On("GetWeather", "Paris").Return((*models.WeatherResponse)(nil), weathererr.NewExternalAPIError("provider down", nil))
+
+	result, err := service.SendWeatherAlerts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Sent)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "Paris")
+}
+
+func TestSubscriptionService_Subscribe_InvalidLanguage(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	service := NewSubscriptionService(db, nil, nil, nil, nil, &config.Config{})
+
+	req := &models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "London",
+		Frequency: "daily",
+		Lang:      "fr",
+	}
+
+	_, err = service.Subscribe(req)
+
+	assert.Error(t, err)
+
+	var appErr *weathererr.AppError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	assert.Contains(t, appErr.Message, "lang")
+}
+
+func TestEmailService_SendConfirmationEmailWithParams_Localized(t *testing.T) {
+	mockProvider := new(mockEmailProvider)
+	emailService := NewEmailService(mockProvider)
+
+	mockProvider.On("SendEmail", "test@example.com", "Підтвердіть підписку на погоду для London", mock.AnythingOfType("string"), true).Return(nil)
+
+	params := ConfirmationEmailParams{
+		Email:      "test@example.com",
+		ConfirmURL: "http://example.com/confirm/token",
+		City:       "London",
+		Lang:       "uk",
+	}
+
+	err := emailService.SendConfirmationEmailWithParams(params)
+
+	assert.NoError(t, err)
+	mockProvider.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Subscribe_NormalizesCity(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Subscription{}, &models.Token{})
+	require.NoError(t, err)
+
+	mockSubRepo := new(mockSubscriptionRepository)
+	mockTokenRepo := new(mockTokenRepository)
+	mockEmailService := new(mockEmailService)
+
+	service := NewSubscriptionService(
+		db,
+		mockSubRepo,
+		mockTokenRepo,
+		mockEmailService,
+		new(mockWeatherService),
+		&config.Config{AppBaseURL: "http://localhost:8080"},
+	)
+
+	// The lookup and the stored row both use the canonical city form
+	mockSubRepo.On("FindByEmail", "test@example.com", "New York").Return((*models.Subscription)(nil), nil)
+	mockTokenRepo.On("CreateToken", uint(1), "confirmation", 24*time.Hour).Return(&models.Token{Token: "test-token"}, nil)
+	mockEmailService.On("SendConfirmationEmailWithParams", mock.Anything).Return(nil)
+
+	subscription, err := service.Subscribe(&models.SubscriptionRequest{
+		Email:     "test@example.com",
+		City:      "  new   YORK ",
+		Frequency: "daily",
+	})
+
+	assert.NoError(t, err)
+	require.NotNil(t, subscription)
+	assert.Equal(t, "New York", subscription.City)
+	mockSubRepo.AssertExpectations(t)
+}
+
+func TestEmailService_RenderEmailPreview(t *testing.T) {
+	emailService := NewEmailService(new(mockEmailProvider))
+
+	t.Run("WeatherUpdate", func(t *testing.T) {
+		html, err := emailService.RenderEmailPreview("weather_update", "Kyiv", "daily")
+
+		assert.NoError(t, err)
+		assert.Contains(t, html, "Kyiv")
+		assert.Contains(t, html, "21.5")
+		assert.Contains(t, html, "Partly cloudy")
+	})
+
+	t.Run("DefaultsApplied", func(t *testing.T) {
+		html, err := emailService.RenderEmailPreview("welcome", "", "")
+
+		assert.NoError(t, err)
+		assert.Contains(t, html, "London")
+		assert.Contains(t, html, "daily")
+	})
+
+	t.Run("AllTypesRender", func(t *testing.T) {
+		for _, emailType := range []string{"confirmation", "welcome", "unsubscribe", "weather_update"} {
+			html, err := emailService.RenderEmailPreview(emailType, "London", "hourly")
+			assert.NoError(t, err, emailType)
+			assert.NotEmpty(t, html, emailType)
+		}
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		html, err := emailService.RenderEmailPreview("invoice", "London", "daily")
+
+		assert.Empty(t, html)
+		var appErr *weathererr.AppError
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, weathererr.ValidationError, appErr.Type)
+	})
+}
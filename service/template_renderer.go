@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateRenderer loads HTML email bodies from template files so branding
+// changes do not require a recompile. Emails whose template file is missing
+// fall back to the embedded defaults
+type TemplateRenderer struct {
+	templates map[string]*template.Template
+}
+
+// NewTemplateRenderer parses every .html file in dir, keyed by file name
+// without extension (e.g. confirmation.html renders the confirmation email).
+// A missing directory yields an empty renderer so the embedded defaults
+// apply out of the box; a template that fails to parse is a configuration
+// error and fails fast
+func NewTemplateRenderer(dir string) (*TemplateRenderer, error) {
+	renderer := &TemplateRenderer{templates: make(map[string]*template.Template)}
+	if dir == "" {
+		return renderer, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return renderer, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read email template directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".html") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		tmpl, err := template.ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("parse email template %s: %w", path, err)
+		}
+
+		name := strings.TrimSuffix(entry.Name(), ".html")
+		renderer.templates[name] = tmpl
+		slog.Debug("Loaded email template", "name", name, "path", path)
+	}
+
+	return renderer, nil
+}
+
+// Render executes the named template, reporting false when no template file
+// was loaded for it or it failed to execute so the caller can fall back
+func (r *TemplateRenderer) Render(name string, data interface{}) (string, bool) {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return "", false
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		slog.Error("Email template execution failed; using embedded default", "name", name, "error", err)
+		return "", false
+	}
+
+	return buf.String(), true
+}
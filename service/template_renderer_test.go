@@ -0,0 +1,99 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeEmailTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestTemplateRenderer_RendersLoadedTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeEmailTemplate(t, dir, "confirmation.html", "<p>Custom confirmation for {{.City}}</p>")
+
+	renderer, err := NewTemplateRenderer(dir)
+	require.NoError(t, err)
+
+	body, ok := renderer.Render("confirmation", ConfirmationEmailParams{City: "London"})
+	assert.True(t, ok)
+	assert.Equal(t, "<p>Custom confirmation for London</p>", body)
+}
+
+func TestTemplateRenderer_MissingTemplate(t *testing.T) {
+	renderer, err := NewTemplateRenderer(t.TempDir())
+	require.NoError(t, err)
+
+	body, ok := renderer.Render("welcome", WelcomeEmailParams{City: "London"})
+	assert.False(t, ok)
+	assert.Empty(t, body)
+}
+
+func TestTemplateRenderer_MissingDirectory(t *testing.T) {
+	renderer, err := NewTemplateRenderer(filepath.Join(t.TempDir(), "does-not-exist"))
+	require.NoError(t, err)
+
+	_, ok := renderer.Render("confirmation", ConfirmationEmailParams{City: "London"})
+	assert.False(t, ok)
+}
+
+func TestTemplateRenderer_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeEmailTemplate(t, dir, "confirmation.html", "<p>{{.City</p>")
+
+	_, err := NewTemplateRenderer(dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parse email template")
+}
+
+func TestEmailService_UsesTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	writeEmailTemplate(t, dir, "confirmation.html", "<p>Branded confirmation for {{.City}}: {{.ConfirmURL}}</p>")
+
+	renderer, err := NewTemplateRenderer(dir)
+	require.NoError(t, err)
+
+	mockProvider := new(mockEmailProvider)
+	emailService := NewEmailServiceWithRenderer(mockProvider, renderer)
+
+	mockProvider.On("SendEmail", "test@example.com", "Confirm your weather subscription for London",
+		"<p>Branded confirmation for London: http://example.com/confirm</p>", true).Return(nil)
+
+	err = emailService.SendConfirmationEmailWithParams(ConfirmationEmailParams{
+		Email:      "test@example.com",
+		ConfirmURL: "http://example.com/confirm",
+		City:       "London",
+	})
+
+	assert.NoError(t, err)
+	mockProvider.AssertExpectations(t)
+}
+
+func TestEmailService_FallsBackWithoutTemplate(t *testing.T) {
+	renderer, err := NewTemplateRenderer(t.TempDir())
+	require.NoError(t, err)
+
+	mockProvider := new(mockEmailProvider)
+	emailService := NewEmailServiceWithRenderer(mockProvider, renderer)
+
+	mockProvider.On("SendEmail", "test@example.com", mock.AnythingOfType("string"), mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "Please confirm your subscription to weather updates for London")
+	}), true).Return(nil)
+
+	err = emailService.SendConfirmationEmailWithParams(ConfirmationEmailParams{
+		Email:      "test@example.com",
+		ConfirmURL: "http://example.com/confirm",
+		City:       "London",
+	})
+
+	assert.NoError(t, err)
+	mockProvider.AssertExpectations(t)
+}
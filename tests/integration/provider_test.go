@@ -40,8 +40,9 @@ func (s *IntegrationTestSuite) TestProviderManagerIntegration() {
 	subscriptionService := s.getSubscriptionService() // Uses valid config
 
 	// This should succeed and send weather update
-	err = subscriptionService.SendWeatherUpdate("daily")
+	result, err := subscriptionService.SendWeatherUpdate("daily")
 	s.NoError(err)
+	s.Equal(0, result.Failed)
 
 	// Wait for email to be sent
 	time.Sleep(2 * time.Second)
@@ -97,7 +97,8 @@ func (s *IntegrationTestSuite) SetupSuite() {
 	providerManager, err := providers.NewProviderManager(providerConfig, nil)
 	s.Require().NoError(err)
 
-	emailProvider := providers.NewSMTPEmailProvider(&testConfig.Email)
+	emailProvider, err := providers.NewSMTPEmailProvider(&testConfig.Email)
+	s.Require().NoError(err)
 
 	weatherService := service.NewWeatherService(providerManager)
 	emailService := service.NewEmailService(emailProvider)
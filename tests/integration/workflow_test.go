@@ -120,14 +120,16 @@ func (s *IntegrationTestSuite) TestWeatherUpdateWorkflow() {
 
 	subscriptionService := s.getSubscriptionService()
 
-	err = subscriptionService.SendWeatherUpdate("daily")
+	result, err := subscriptionService.SendWeatherUpdate("daily")
 	s.NoError(err)
+	s.Equal(0, result.Failed)
 
 	time.Sleep(2 * time.Second)
 	s.AssertEmailSent("daily@example.com", "Weather Update for London")
 
-	err = subscriptionService.SendWeatherUpdate("hourly")
+	result, err = subscriptionService.SendWeatherUpdate("hourly")
 	s.NoError(err)
+	s.Equal(0, result.Failed)
 
 	time.Sleep(2 * time.Second)
 	s.AssertEmailSent("hourly@example.com", "Weather Update for Paris")
@@ -222,7 +224,8 @@ func (s *IntegrationTestSuite) getSubscriptionService() service.SubscriptionServ
 	providerManager, err := providers.NewProviderManager(providerConfig, nil)
 	s.Require().NoError(err)
 
-	emailProvider := providers.NewSMTPEmailProvider(&s.config.Email)
+	emailProvider, err := providers.NewSMTPEmailProvider(&s.config.Email)
+	s.Require().NoError(err)
 
 	weatherService := service.NewWeatherService(providerManager)
 	emailService := service.NewEmailService(emailProvider)
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type traceIDKey struct{}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or an empty string
+// when no span has been started from it
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// SlogTracer emits completed spans as structured debug logs. It stands in
+// for a collector-backed exporter: spans started from a context produced by
+// StartSpan share a trace ID, so the log lines of one request can be
+// correlated
+type SlogTracer struct {
+	logger *slog.Logger
+}
+
+// NewSlogTracer creates a tracer that logs spans through the default slog
+// logger
+func NewSlogTracer() *SlogTracer {
+	return &SlogTracer{logger: slog.Default()}
+}
+
+// NewSlogTracerWithLogger creates a tracer that logs spans through the given
+// logger
+func NewSlogTracerWithLogger(logger *slog.Logger) *SlogTracer {
+	return &SlogTracer{logger: logger}
+}
+
+// StartSpan begins a span, reusing the trace ID from ctx when present so
+// nested spans stay correlated
+func (t *SlogTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		traceID = uuid.New().String()
+		ctx = context.WithValue(ctx, traceIDKey{}, traceID)
+	}
+
+	return ctx, &slogSpan{
+		logger:  t.logger,
+		name:    name,
+		traceID: traceID,
+		started: time.Now(),
+	}
+}
+
+type slogSpan struct {
+	logger  *slog.Logger
+	name    string
+	traceID string
+	started time.Time
+
+	mu    sync.Mutex
+	attrs []slog.Attr
+	err   error
+}
+
+func (s *slogSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, slog.Any(key, value))
+}
+
+func (s *slogSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *slogSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attrs := []slog.Attr{
+		slog.String("trace_id", s.traceID),
+		slog.Duration("duration", time.Since(s.started)),
+	}
+	attrs = append(attrs, s.attrs...)
+	if s.err != nil {
+		attrs = append(attrs, slog.Any("error", s.err))
+	}
+
+	s.logger.LogAttrs(context.Background(), slog.LevelDebug, "span "+s.name, attrs...)
+}
@@ -0,0 +1,41 @@
+// Package tracing defines the minimal span API the application instruments
+// against, mirroring the subset of OpenTelemetry it needs. Components take a
+// Tracer through their builders and default to the no-op implementation, so
+// instrumentation costs nothing unless tracing is enabled.
+package tracing
+
+import (
+	"context"
+)
+
+// Tracer starts spans. StartSpan returns a derived context carrying the
+// trace so spans started from it join the same trace
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span records details about one traced operation. Spans must be ended
+// exactly once
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// NewNoopTracer returns a tracer whose spans record nothing; it is the
+// default when tracing is disabled
+func NewNoopTracer() Tracer {
+	return noopTracer{}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) StartSpan(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) End()                             {}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCapturingTracer() (*SlogTracer, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return NewSlogTracerWithLogger(logger), &buf
+}
+
+func TestNoopTracer(t *testing.T) {
+	tracer := NewNoopTracer()
+
+	ctx, span := tracer.StartSpan(context.Background(), "noop")
+	span.SetAttribute("key", "value")
+	span.RecordError(errors.New("ignored"))
+	span.End()
+
+	assert.Empty(t, TraceIDFromContext(ctx))
+}
+
+func TestSlogTracer_EmitsSpanLog(t *testing.T) {
+	tracer, buf := newCapturingTracer()
+
+	_, span := tracer.StartSpan(context.Background(), "test.operation")
+	span.SetAttribute("city", "London")
+	span.RecordError(errors.New("boom"))
+	span.End()
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "span test.operation", record["msg"])
+	assert.Equal(t, "London", record["city"])
+	assert.Equal(t, "boom", record["error"])
+	assert.NotEmpty(t, record["trace_id"])
+}
+
+func TestSlogTracer_ChildSharesTraceID(t *testing.T) {
+	tracer, buf := newCapturingTracer()
+
+	ctx, parent := tracer.StartSpan(context.Background(), "parent")
+	_, child := tracer.StartSpan(ctx, "child")
+	child.End()
+	parent.End()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	traceIDs := make([]string, 0, 2)
+	for _, line := range lines {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		traceID, ok := record["trace_id"].(string)
+		require.True(t, ok)
+		traceIDs = append(traceIDs, traceID)
+	}
+
+	assert.Equal(t, traceIDs[0], traceIDs[1])
+	assert.Equal(t, TraceIDFromContext(ctx), traceIDs[0])
+}
+
+func TestSlogTracer_SeparateRootsGetSeparateTraces(t *testing.T) {
+	tracer, _ := newCapturingTracer()
+
+	ctxA, spanA := tracer.StartSpan(context.Background(), "a")
+	spanA.End()
+	ctxB, spanB := tracer.StartSpan(context.Background(), "b")
+	spanB.End()
+
+	assert.NotEqual(t, TraceIDFromContext(ctxA), TraceIDFromContext(ctxB))
+}